@@ -0,0 +1,113 @@
+package farewells
+
+import (
+	"fmt"
+
+	"example.com/greetings"
+)
+
+// catalog holds the farewell for each locale greetings supports, so a
+// session can start and end in the same language.
+var catalog = greetings.Catalog{
+	"en": {
+		Template:    "Goodbye, %v. See you soon",
+		Punctuation: "!",
+		Casual:      greetings.Variant{Template: "Bye, %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "Farewell, %v. Until we meet again", Punctuation: "."},
+	},
+	"es": {
+		Template:    "Adiós, %v. Hasta pronto",
+		Punctuation: ".",
+		Casual:      greetings.Variant{Template: "¡Chao, %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "Reciba un cordial saludo de despedida, %v", Punctuation: "."},
+		Conjunction: " y ",
+	},
+	"fr": {
+		Template:    "Au revoir, %v. À bientôt",
+		Punctuation: "\u00a0!",
+		Casual:      greetings.Variant{Template: "Salut %v, à plus", Punctuation: "\u00a0!"},
+		Formal:      greetings.Variant{Template: "Nous vous disons au revoir, %v", Punctuation: "."},
+		Conjunction: " et ",
+	},
+	"de": {
+		Template:    "Auf Wiedersehen, %v. Bis bald",
+		Punctuation: "!",
+		Casual:      greetings.Variant{Template: "Tschüss, %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "Auf Wiedersehen, %v. Wir freuen uns auf Ihren nächsten Besuch", Punctuation: "."},
+		Conjunction: " und ",
+	},
+	"pt": {
+		Template:    "Adeus, %v. Até breve",
+		Punctuation: "!",
+		Casual:      greetings.Variant{Template: "Tchau, %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "Despedimo-nos de si, %v. Até uma próxima ocasião", Punctuation: "."},
+		Conjunction: " e ",
+	},
+	"ja": {
+		Template:    "さようなら、%v。またね",
+		Punctuation: "！",
+		Casual:      greetings.Variant{Template: "じゃあね、%v", Punctuation: "！"},
+		Formal:      greetings.Variant{Template: "本日はありがとうございました、%v", Punctuation: "。"},
+		TitleFormat: "%[2]s%[1]s",
+		Separator:   "、",
+		Conjunction: "と",
+	},
+	"ar": {
+		Template:    "مع السلامة، %v. إلى اللقاء",
+		Punctuation: "!",
+		Casual:      greetings.Variant{Template: "باي %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "نودّعكم على أمل اللقاء قريبًا، %v", Punctuation: "."},
+		Separator:   "، ",
+		Conjunction: " و",
+	},
+	"he": {
+		Template:    "להתראות, %v. נתראה בקרוב",
+		Punctuation: "!",
+		Casual:      greetings.Variant{Template: "ביי %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "תודה על ביקורכם, %v. להתראות", Punctuation: "."},
+		Conjunction: " ו",
+	},
+	"pl": {
+		Template:    "Do widzenia, %v. Do zobaczenia wkrótce",
+		Punctuation: "!",
+		Casual:      greetings.Variant{Template: "Na razie, %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "Dziękujemy za wizytę, %v. Do widzenia", Punctuation: "."},
+		Conjunction: " i ",
+	},
+	"ru": {
+		Template:    "До свидания, %v. До скорой встречи",
+		Punctuation: "!",
+		Casual:      greetings.Variant{Template: "Пока, %v", Punctuation: "!"},
+		Formal:      greetings.Variant{Template: "Благодарим вас за визит, %v. До свидания", Punctuation: "."},
+		Conjunction: " и ",
+	},
+}
+
+// std is the Greeter behind the package-level functions.
+var std, _ = New()
+
+// New returns a greetings.Greeter that says goodbye instead of hello. It
+// accepts the same options as greetings.New, so locale, template and clock
+// settings carry over unchanged.
+func New(opts ...greetings.Option) (*greetings.Greeter, error) {
+	return greetings.New(append([]greetings.Option{greetings.WithCatalog(catalog)}, opts...)...)
+}
+
+// Goodbye returns a farewell for the named person. It fails when the name
+// does not pass greetings.Validate.
+func Goodbye(name string) (string, error) {
+	return std.Hello(name)
+}
+
+// GoodbyeLocale returns a farewell for the named person in the given locale.
+// An unknown locale still yields the English farewell, alongside an error
+// wrapping greetings.ErrUnknownLocale.
+func GoodbyeLocale(name, locale string) (string, error) {
+
+	if err := greetings.Validate(name); err != nil {
+		return "", err
+	}
+
+	msg, err := catalog.Lookup(locale)
+	return fmt.Sprintf(msg.Template, name) + msg.Punctuation, err
+}
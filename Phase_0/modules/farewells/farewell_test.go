@@ -0,0 +1,34 @@
+package farewells
+
+import (
+	"slices"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestCatalogMatchesGreetings(t *testing.T) {
+	if got, want := catalog.Locales(), greetings.Locales(); !slices.Equal(got, want) {
+		t.Errorf("farewell locales = %v, greeting locales = %v", got, want)
+	}
+}
+
+func TestGoodbyeLocale(t *testing.T) {
+	for _, tt := range []struct {
+		locale, want string
+	}{
+		{"en", "Goodbye, Ada. See you soon!"},
+		{"pl", "Do widzenia, Ada. Do zobaczenia wkrótce!"},
+		{"ru", "До свидания, Ada. До скорой встречи!"},
+		{"pt-BR", "Adeus, Ada. Até breve!"},
+	} {
+		got, err := GoodbyeLocale("Ada", tt.locale)
+		if err != nil {
+			t.Errorf("GoodbyeLocale(Ada, %s): %v", tt.locale, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("GoodbyeLocale(Ada, %s) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
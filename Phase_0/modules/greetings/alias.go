@@ -0,0 +1,130 @@
+package greetings
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Aliases maps names to the names people prefer to be greeted by, such as
+// "Robert" to "Bob". Lookups ignore case. An Aliases is safe for
+// concurrent use, so entries can be added and removed while Greeters
+// use it; the zero value is an empty table.
+type Aliases struct {
+	mu sync.RWMutex
+	m  map[string]string // lower-cased name to alias
+}
+
+// NewAliases returns a table holding the name-to-alias pairs of m.
+func NewAliases(m map[string]string) *Aliases {
+	a := new(Aliases)
+	for name, alias := range m {
+		a.Set(name, alias)
+	}
+	return a
+}
+
+// LoadAliases reads an alias table from a YAML or JSON file mapping names
+// to aliases:
+//
+//	Robert: Bob
+//	Elizabeth: Liz
+func LoadAliases(path string) (*Aliases, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseAliases(path, data)
+}
+
+// ParseAliases is like LoadAliases for data already in memory; name is
+// used in error messages.
+func ParseAliases(name string, data []byte) (*Aliases, error) {
+
+	var m map[string]string
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("greetings: aliases %s: %w", name, err)
+	}
+	for from, to := range m {
+		if from == "" || to == "" {
+			return nil, fmt.Errorf("greetings: aliases %s: empty name in %q: %q", name, from, to)
+		}
+	}
+
+	return NewAliases(m), nil
+}
+
+// Set makes alias the name name is greeted by, replacing any previous
+// alias.
+func (a *Aliases) Set(name, alias string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.m == nil {
+		a.m = make(map[string]string)
+	}
+	a.m[strings.ToLower(name)] = alias
+}
+
+// Remove deletes the alias of name and reports whether there was one.
+func (a *Aliases) Remove(name string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	key := strings.ToLower(name)
+	_, ok := a.m[key]
+	delete(a.m, key)
+	return ok
+}
+
+// Lookup returns the alias of name, if it has one.
+func (a *Aliases) Lookup(name string) (string, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	alias, ok := a.m[strings.ToLower(name)]
+	return alias, ok
+}
+
+// Len returns the number of aliases in the table.
+func (a *Aliases) Len() int {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return len(a.m)
+}
+
+// aliases is the table behind SetAlias and RemoveAlias, used by every
+// Greeter not given its own with WithAliases.
+var aliases = new(Aliases)
+
+// SetAlias adds an alias to the package-wide table; see Aliases.Set.
+func SetAlias(name, alias string) {
+	aliases.Set(name, alias)
+}
+
+// RemoveAlias removes an alias from the package-wide table; see
+// Aliases.Remove.
+func RemoveAlias(name string) bool {
+	return aliases.Remove(name)
+}
+
+// WithAliases makes the Greeter resolve names through a instead of the
+// package-wide table. A nil table turns alias resolution off.
+func WithAliases(a *Aliases) Option {
+	return func(g *Greeter) error {
+		g.aliases = a
+		return nil
+	}
+}
+
+// resolveAlias returns the alias of name in the Greeter's table, or name
+// itself when it has none.
+func (g *Greeter) resolveAlias(name string) string {
+	if g.aliases == nil {
+		return name
+	}
+	if alias, ok := g.aliases.Lookup(name); ok {
+		return alias
+	}
+	return name
+}
@@ -0,0 +1,198 @@
+// Package analytics counts the greetings a Greeter issues, per name, per
+// locale and per hour, so product teams can see how greetings are used
+// without running a metrics stack. An Aggregator, installed with With or
+// greetings.Use, does the counting; its Snapshot is plain data, ready to
+// serve as JSON:
+//
+//	a := analytics.New()
+//	g, _ := greetings.New(analytics.With(a))
+//	...
+//	a.WriteJSON(w)
+package analytics
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"io"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"example.com/greetings"
+)
+
+// DefaultRetention is how many hours of hourly counts an Aggregator keeps
+// unless told otherwise: a week.
+const DefaultRetention = 7 * 24 * time.Hour
+
+// Aggregator counts greetings. It is safe for concurrent use.
+type Aggregator struct {
+	mu        sync.Mutex
+	retention time.Duration
+	total     int64
+	names     map[string]int64
+	locales   map[string]int64
+	hours     map[time.Time]int64
+	latest    time.Time
+}
+
+// New returns an empty Aggregator keeping DefaultRetention of hourly
+// counts.
+func New() *Aggregator {
+	return &Aggregator{
+		retention: DefaultRetention,
+		names:     make(map[string]int64),
+		locales:   make(map[string]int64),
+		hours:     make(map[time.Time]int64),
+	}
+}
+
+// SetRetention sets how far back from the latest greeting hourly counts
+// are kept; older hours are dropped. Zero or less keeps every hour. Counts
+// per name and locale are kept regardless.
+func (a *Aggregator) SetRetention(d time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.retention = d
+	a.prune()
+}
+
+// With counts the Greeter's greetings in a; it is a shorthand for
+// greetings.Use(a.Middleware).
+func With(a *Aggregator) greetings.Option {
+	return greetings.Use(a.Middleware)
+}
+
+// Middleware counts every greeting next issues successfully, once for
+// each recipient, in the hour of the request's time.
+func (a *Aggregator) Middleware(next greetings.Provider) greetings.Provider {
+	return greetings.ProviderFunc(func(ctx context.Context, req greetings.Request) (greetings.Greeting, error) {
+		greeting, err := next.Greet(ctx, req)
+		if err != nil {
+			return greeting, err
+		}
+		for _, p := range req.Recipients {
+			a.Record(p.Name, req.Locale, req.Time)
+		}
+		return greeting, nil
+	})
+}
+
+// Record counts one greeting of name in locale at t. A t older than
+// the retention window still counts towards the totals, but not in Hours.
+func (a *Aggregator) Record(name, locale string, t time.Time) {
+
+	hour := t.UTC().Truncate(time.Hour)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.total++
+	a.names[name]++
+	a.locales[locale]++
+	if hour.After(a.latest) {
+		a.latest = hour
+		a.prune()
+	}
+	if a.retained(hour) {
+		a.hours[hour]++
+	}
+}
+
+// retained reports whether hour is inside the retention window. a.mu
+// must be held.
+func (a *Aggregator) retained(hour time.Time) bool {
+	return a.retention <= 0 || hour.After(a.latest.Add(-a.retention))
+}
+
+// prune drops hours outside the retention window. a.mu must be held.
+func (a *Aggregator) prune() {
+	maps.DeleteFunc(a.hours, func(hour time.Time, _ int64) bool {
+		return !a.retained(hour)
+	})
+}
+
+// Reset forgets every count.
+func (a *Aggregator) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.total = 0
+	clear(a.names)
+	clear(a.locales)
+	clear(a.hours)
+	a.latest = time.Time{}
+}
+
+// Snapshot is the state of an Aggregator at one moment.
+type Snapshot struct {
+	// Total counts every greeting recorded, including those in hours
+	// no longer retained.
+	Total int64 `json:"total"`
+
+	Names   map[string]int64 `json:"names"`
+	Locales map[string]int64 `json:"locales"`
+
+	// Hours holds the retained hourly counts, oldest first. Hours
+	// without greetings are left out.
+	Hours []HourCount `json:"hours"`
+}
+
+// HourCount is the number of greetings in the hour starting at Hour, in
+// UTC.
+type HourCount struct {
+	Hour  time.Time `json:"hour"`
+	Count int64     `json:"count"`
+}
+
+// NameCount is the number of greetings of one name.
+type NameCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// Snapshot returns a copy of the current counts.
+func (a *Aggregator) Snapshot() Snapshot {
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := Snapshot{
+		Total:   a.total,
+		Names:   maps.Clone(a.names),
+		Locales: maps.Clone(a.locales),
+		Hours:   make([]HourCount, 0, len(a.hours)),
+	}
+	for hour, n := range a.hours {
+		s.Hours = append(s.Hours, HourCount{hour, n})
+	}
+	slices.SortFunc(s.Hours, func(x, y HourCount) int {
+		return x.Hour.Compare(y.Hour)
+	})
+
+	return s
+}
+
+// TopNames returns the n most greeted names, most greeted first and ties
+// in name order. A negative n returns them all.
+func (s Snapshot) TopNames(n int) []NameCount {
+
+	top := make([]NameCount, 0, len(s.Names))
+	for name, count := range s.Names {
+		top = append(top, NameCount{name, count})
+	}
+	slices.SortFunc(top, func(x, y NameCount) int {
+		return cmp.Or(cmp.Compare(y.Count, x.Count), cmp.Compare(x.Name, y.Name))
+	})
+	if n >= 0 && n < len(top) {
+		top = top[:n]
+	}
+
+	return top
+}
+
+// WriteJSON writes the current Snapshot to w as indented JSON.
+func (a *Aggregator) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a.Snapshot())
+}
@@ -0,0 +1,169 @@
+package analytics_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/analytics"
+	"example.com/greetings/greetingstest"
+)
+
+var noon = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func TestRecordCountsByNameLocaleAndHour(t *testing.T) {
+
+	a := analytics.New()
+	a.Record("Ada", "en", noon.Add(5*time.Minute))
+	a.Record("Ada", "fr", noon.Add(55*time.Minute))
+	a.Record("Cy", "en", noon.Add(time.Hour))
+	// The same instant in another zone falls in the same UTC hour.
+	a.Record("Cy", "en", noon.Add(10*time.Minute).In(time.FixedZone("CEST", 2*60*60)))
+
+	s := a.Snapshot()
+	if s.Total != 4 {
+		t.Errorf("Total = %d, want 4", s.Total)
+	}
+	if want := map[string]int64{"Ada": 2, "Cy": 2}; !mapsEqual(s.Names, want) {
+		t.Errorf("Names = %v, want %v", s.Names, want)
+	}
+	if want := map[string]int64{"en": 3, "fr": 1}; !mapsEqual(s.Locales, want) {
+		t.Errorf("Locales = %v, want %v", s.Locales, want)
+	}
+	want := []analytics.HourCount{{Hour: noon, Count: 3}, {Hour: noon.Add(time.Hour), Count: 1}}
+	if !slices.Equal(s.Hours, want) {
+		t.Errorf("Hours = %v, want %v", s.Hours, want)
+	}
+}
+
+func TestRetentionDropsOldHours(t *testing.T) {
+
+	a := analytics.New()
+	a.SetRetention(2 * time.Hour)
+	for h := range 4 {
+		a.Record("Ada", "en", noon.Add(time.Duration(h)*time.Hour))
+	}
+	s := a.Snapshot()
+	if s.Total != 4 || s.Names["Ada"] != 4 {
+		t.Errorf("Total, Names[Ada] = %d, %d, want 4, 4", s.Total, s.Names["Ada"])
+	}
+	want := []analytics.HourCount{{Hour: noon.Add(2 * time.Hour), Count: 1}, {Hour: noon.Add(3 * time.Hour), Count: 1}}
+	if !slices.Equal(s.Hours, want) {
+		t.Errorf("Hours = %v, want %v", s.Hours, want)
+	}
+
+	// Shrinking the window prunes at once; an older greeting after the
+	// latest does not move it.
+	a.SetRetention(time.Hour)
+	a.Record("Ada", "en", noon)
+	if got := a.Snapshot().Hours; len(got) != 1 || !got[0].Hour.Equal(noon.Add(3*time.Hour)) {
+		t.Errorf("Hours after SetRetention(1h) = %v, want only %v", got, noon.Add(3*time.Hour))
+	}
+}
+
+func TestZeroRetentionKeepsEveryHour(t *testing.T) {
+	a := analytics.New()
+	a.SetRetention(0)
+	a.Record("Ada", "en", noon)
+	a.Record("Ada", "en", noon.AddDate(1, 0, 0))
+	if got := len(a.Snapshot().Hours); got != 2 {
+		t.Errorf("len(Hours) = %d, want 2", got)
+	}
+}
+
+func TestTopNames(t *testing.T) {
+
+	s := analytics.Snapshot{Names: map[string]int64{"Cy": 2, "Ada": 2, "Bo": 5, "Di": 1}}
+	full := []analytics.NameCount{{"Bo", 5}, {"Ada", 2}, {"Cy", 2}, {"Di", 1}}
+	for _, tt := range []struct {
+		n    int
+		want []analytics.NameCount
+	}{
+		{-1, full},
+		{0, []analytics.NameCount{}},
+		{2, full[:2]},
+		{10, full},
+	} {
+		if got := s.TopNames(tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("TopNames(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMiddlewareCountsSuccessfulGreetings(t *testing.T) {
+
+	a := analytics.New()
+	clock := greetingstest.NewFakeClock(noon.Add(30 * time.Minute))
+	g, err := greetings.New(analytics.With(a), greetings.WithClock(clock), greetings.WithLocale("fr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Ada", "Cy", "Ada"} {
+		if _, err := g.Hello(name); err != nil {
+			t.Fatalf("Hello(%q): %v", name, err)
+		}
+	}
+	s := a.Snapshot()
+	if s.Total != 3 || s.Names["Ada"] != 2 || s.Locales["fr"] != 3 {
+		t.Errorf("Snapshot = %+v, want 3 fr greetings, 2 of Ada", s)
+	}
+	if want := []analytics.HourCount{{Hour: noon, Count: 3}}; !slices.Equal(s.Hours, want) {
+		t.Errorf("Hours = %v, want %v", s.Hours, want)
+	}
+
+	failing := a.Middleware(greetings.ProviderFunc(func(context.Context, greetings.Request) (greetings.Greeting, error) {
+		return greetings.Greeting{}, errors.New("boom")
+	}))
+	req := greetings.Request{Recipients: []greetings.Person{{Name: "Bo"}}, Locale: "en", Time: noon}
+	if _, err := failing.Greet(context.Background(), req); err == nil {
+		t.Fatal("failing provider returned no error")
+	}
+	if got := a.Snapshot().Total; got != 3 {
+		t.Errorf("Total after a failed greeting = %d, want 3", got)
+	}
+}
+
+func TestResetAndWriteJSON(t *testing.T) {
+
+	a := analytics.New()
+	a.Record("Ada", "en", noon)
+	var buf bytes.Buffer
+	if err := a.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var got analytics.Snapshot
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("WriteJSON wrote invalid JSON: %v\n%s", err, buf.Bytes())
+	}
+	if got.Total != 1 || got.Names["Ada"] != 1 || len(got.Hours) != 1 || !got.Hours[0].Hour.Equal(noon) {
+		t.Errorf("decoded %+v, want one greeting of Ada at %v", got, noon)
+	}
+
+	a.Reset()
+	s := a.Snapshot()
+	if s.Total != 0 || len(s.Names) != 0 || len(s.Locales) != 0 || len(s.Hours) != 0 {
+		t.Errorf("Snapshot after Reset = %+v, want it empty", s)
+	}
+	// Reset also forgets the latest hour, so retention restarts.
+	a.Record("Ada", "en", noon.AddDate(0, 0, -30))
+	if got := len(a.Snapshot().Hours); got != 1 {
+		t.Errorf("len(Hours) after Reset = %d, want 1", got)
+	}
+}
+
+func mapsEqual(x, y map[string]int64) bool {
+	if len(x) != len(y) {
+		return false
+	}
+	for k, v := range x {
+		if w, ok := y[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
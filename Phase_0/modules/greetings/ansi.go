@@ -0,0 +1,65 @@
+package greetings
+
+import (
+	"os"
+	"strings"
+)
+
+// The ANSI escape sequences FormatANSI colors with.
+const (
+	ansiSalutation = "\x1b[36m"
+	ansiName       = "\x1b[1;33m"
+	ansiReset      = "\x1b[0m"
+)
+
+// WithColor turns FormatANSI colors on or off regardless of where
+// standard output goes and of NO_COLOR, for programs that know better,
+// such as one writing to a terminal other than its own.
+func WithColor(on bool) Option {
+	return func(g *Greeter) error {
+		g.color = on
+		g.colorSet = true
+		return nil
+	}
+}
+
+// colorTerminal reports whether FormatANSI should color by default: when
+// standard output is a terminal, TERM is not "dumb" and NO_COLOR is unset
+// or empty (see https://no-color.org).
+func colorTerminal() bool {
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// ansi renders greeting with the salutation and the name colored, or as
+// its plain message when color is off. Escape characters in the message
+// are dropped so it cannot smuggle in sequences of its own.
+func ansi(greeting Greeting, color bool) string {
+
+	if !color {
+		return greeting.Message
+	}
+	s := split(greeting)
+	clean := func(text string) string {
+		return strings.ReplaceAll(text, "\x1b", "")
+	}
+
+	var b strings.Builder
+	if s.before != "" {
+		b.WriteString(ansiSalutation + clean(s.before) + ansiReset)
+	}
+	if s.name != "" {
+		b.WriteString(ansiName + clean(s.name) + ansiReset)
+	}
+	b.WriteString(clean(s.rest + s.tail))
+
+	return b.String()
+}
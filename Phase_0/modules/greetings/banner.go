@@ -0,0 +1,196 @@
+package greetings
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// defaultBannerWidth is the width FormatBanner wraps at when it cannot
+// tell the terminal's.
+const defaultBannerWidth = 80
+
+// WithBannerWidth makes FormatBanner wrap at width columns instead of the
+// terminal's width, for output that goes somewhere else.
+func WithBannerWidth(width int) Option {
+	return func(g *Greeter) error {
+		if width < 1 {
+			return fmt.Errorf("greetings: invalid banner width %d", width)
+		}
+		g.bannerWidth = width
+		return nil
+	}
+}
+
+// terminalWidth returns the width of the terminal as the shell reports it
+// in COLUMNS, or defaultBannerWidth.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	return defaultBannerWidth
+}
+
+// bannerFont is a font of ASCII-art glyphs, all of the same height.
+type bannerFont struct {
+	height int
+	glyphs map[rune][]string
+}
+
+// loadBannerFont returns the font embedded in data/banner.txt.
+var loadBannerFont = sync.OnceValue(func() *bannerFont {
+	data, err := embedded.ReadFile("data/banner.txt")
+	if err != nil {
+		panic(err)
+	}
+	f, err := parseBannerFont(string(data))
+	if err != nil {
+		panic(err)
+	}
+	return f
+})
+
+// parseBannerFont reads a font in the format described at the top of
+// data/banner.txt.
+func parseBannerFont(data string) (*bannerFont, error) {
+
+	f := &bannerFont{glyphs: make(map[rune][]string)}
+	var r rune
+	var rows []string
+	add := func() error {
+		if rows == nil {
+			return nil
+		}
+		if f.height == 0 {
+			f.height = len(rows)
+		}
+		if len(rows) != f.height {
+			return fmt.Errorf("greetings: banner glyph %q has %d rows, want %d", r, len(rows), f.height)
+		}
+		for _, row := range rows {
+			if len(row) != len(rows[0]) {
+				return fmt.Errorf("greetings: banner glyph %q has rows of different widths", r)
+			}
+		}
+		f.glyphs[r] = rows
+		return nil
+	}
+
+	glyph := false
+	for line := range strings.Lines(data) {
+		line = strings.TrimRight(line, "\r\n")
+		if c, ok := strings.CutPrefix(line, "@"); ok && len([]rune(c)) == 1 {
+			if err := add(); err != nil {
+				return nil, err
+			}
+			r, rows, glyph = []rune(c)[0], []string{}, true
+			continue
+		}
+		if glyph {
+			rows = append(rows, strings.ReplaceAll(line, ".", " "))
+		}
+	}
+	if err := add(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// bannerPunctuation spells punctuation the font lacks with characters it
+// has.
+var bannerPunctuation = strings.NewReplacer(
+	"‘", "'", "’", "'", "“", `"`, "”", `"`, "«", `"`, "»", `"`,
+	"…", "...", "\u00a0", " ", "\u202f", " ",
+)
+
+// text returns message upper-cased and spelled with the font's glyphs:
+// letters lose their diacritics and symbols without a glyph, such as
+// emoji, are dropped. It reports false when a letter or digit has no
+// glyph even so, as those of other scripts do not.
+func (f *bannerFont) text(message string) (string, bool) {
+
+	var b strings.Builder
+	for _, r := range basicTransliterate(bannerPunctuation.Replace(message)) {
+		r = unicode.ToUpper(r)
+		switch {
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+		case f.glyphs[r] != nil:
+			b.WriteRune(r)
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return "", false
+		}
+	}
+
+	return b.String(), true
+}
+
+// word returns the rows of word drawn in f, with a column of paper
+// between glyphs.
+func (f *bannerFont) word(word string) []string {
+	rows := make([]string, f.height)
+	for i, r := range word {
+		for j, row := range f.glyphs[r] {
+			if i > 0 {
+				rows[j] += " "
+			}
+			rows[j] += row
+		}
+	}
+	return rows
+}
+
+// banner renders message in big letters for FormatBanner, breaking lines
+// between words to fit width columns, with a blank line between lines of
+// letters. It returns message as it is when the font cannot spell it or a
+// word is wider than width.
+func banner(message string, width int) string {
+
+	f := loadBannerFont()
+	text, ok := f.text(message)
+	if !ok {
+		return message
+	}
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return message
+	}
+
+	space := f.word(" ")
+	var lines [][]string
+	var line []string
+	for _, w := range words {
+		rows := f.word(w)
+		if len(rows[0]) > width {
+			return message
+		}
+		if line != nil && len(line[0])+1+len(space[0])+1+len(rows[0]) <= width {
+			for j := range line {
+				line[j] += " " + space[j] + " " + rows[j]
+			}
+			continue
+		}
+		if line != nil {
+			lines = append(lines, line)
+		}
+		line = rows
+	}
+	lines = append(lines, line)
+
+	var b strings.Builder
+	for i, rows := range lines {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for _, row := range rows {
+			b.WriteString(strings.TrimRight(row, " "))
+			b.WriteString("\n")
+		}
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
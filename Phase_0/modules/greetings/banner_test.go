@@ -0,0 +1,122 @@
+package greetings_test
+
+import (
+	"strings"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func bannerGreeter(t *testing.T, opts ...greetings.Option) *greetings.Greeter {
+	t.Helper()
+	g, err := greetings.New(append([]greetings.Option{greetings.WithFormat(greetings.FormatBanner)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestBannerGlyphs(t *testing.T) {
+
+	g := bannerGreeter(t, greetings.WithTemplate("%v"), greetings.WithEmoji(true), greetings.WithBannerWidth(80))
+	got, err := g.Greet("ab")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Letters are upper-cased and the emoji dropped.
+	want := strings.Join([]string{
+		" ###  ####  #",
+		"#   # #   # #",
+		"##### ####  #",
+		"#   # #   #",
+		"#   # ####  #",
+	}, "\n")
+	if got.Formatted != want {
+		t.Errorf("Formatted =\n%s\nwant\n%s", got.Formatted, want)
+	}
+	if got.Message != "ab! 👋" {
+		t.Errorf("Message = %q, want it unchanged", got.Message)
+	}
+}
+
+func TestBannerWraps(t *testing.T) {
+	for _, tt := range []struct {
+		width, lines int
+	}{
+		{200, 1},
+		{60, 2},
+		{47, 2},
+	} {
+		got, err := bannerGreeter(t, greetings.WithBannerWidth(tt.width)).Greet("Ada")
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(got.Formatted, "\n\n")
+		if len(lines) != tt.lines {
+			t.Errorf("width %d: %d lines of letters, want %d:\n%s", tt.width, len(lines), tt.lines, got.Formatted)
+		}
+		for _, row := range strings.Split(got.Formatted, "\n") {
+			if len(row) > tt.width || strings.TrimRight(row, " ") != row {
+				t.Errorf("width %d: row %q is too wide or ends in spaces", tt.width, row)
+			}
+		}
+		for i, line := range lines {
+			if n := strings.Count(line, "\n") + 1; n != 5 {
+				t.Errorf("width %d: line %d is %d rows high, want 5", tt.width, i, n)
+			}
+		}
+	}
+}
+
+func TestBannerFallsBackToText(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []greetings.Option
+		want string
+	}{
+		{"other script", []greetings.Option{greetings.WithLocale("ja")}, "こんにちは、Ada。ようこそ！"},
+		{"word too wide", []greetings.Option{greetings.WithBannerWidth(40)}, "Hi, Ada. Welcome!"},
+	} {
+		got, err := bannerGreeter(t, tt.opts...).Greet("Ada")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Formatted != tt.want {
+			t.Errorf("%s: Formatted = %q, want %q", tt.name, got.Formatted, tt.want)
+		}
+	}
+}
+
+func TestBannerDiacritics(t *testing.T) {
+	plain, _ := bannerGreeter(t, greetings.WithLocale("fr"), greetings.WithBannerWidth(300)).Greet("Zoe")
+	accented, _ := bannerGreeter(t, greetings.WithLocale("fr"), greetings.WithBannerWidth(300)).Greet("Zoë")
+	if accented.Formatted == accented.Message || accented.Formatted != plain.Formatted {
+		t.Errorf("Zoë drawn as\n%s\nwant it drawn as Zoe:\n%s", accented.Formatted, plain.Formatted)
+	}
+}
+
+func TestBannerWidthFromColumns(t *testing.T) {
+
+	t.Setenv("COLUMNS", "60")
+	got, err := bannerGreeter(t).Greet("Ada")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(strings.Split(got.Formatted, "\n\n")); n != 2 {
+		t.Errorf("COLUMNS=60: %d lines of letters, want 2", n)
+	}
+
+	t.Setenv("COLUMNS", "junk")
+	got, _ = bannerGreeter(t).Greet("Ada")
+	if n := len(strings.Split(got.Formatted, "\n\n")); n != 2 {
+		t.Errorf("COLUMNS=junk: %d lines of letters at the default 80 columns, want 2", n)
+	}
+}
+
+func TestWithBannerWidthRejectsNonPositive(t *testing.T) {
+	for _, width := range []int{0, -1} {
+		if _, err := greetings.New(greetings.WithBannerWidth(width)); greetings.CodeOf(err) != greetings.InvalidConfig {
+			t.Errorf("WithBannerWidth(%d) = %v, want an InvalidConfig error", width, err)
+		}
+	}
+}
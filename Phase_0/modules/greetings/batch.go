@@ -0,0 +1,177 @@
+package greetings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"example.com/collections"
+	"example.com/concurrent"
+	"golang.org/x/sync/errgroup"
+)
+
+// batchChunk is how many consecutive names a worker takes at a time. Handing
+// out chunks rather than single names keeps channel traffic negligible next
+// to the cost of rendering.
+const batchChunk = 256
+
+// WithWorkers makes Greeter.Hellos spread large batches over n goroutines.
+// Zero or less means one per CPU (runtime.GOMAXPROCS); the default, 1,
+// greets sequentially.
+func WithWorkers(n int) Option {
+	return func(g *Greeter) error {
+		if n <= 0 {
+			n = runtime.GOMAXPROCS(0)
+		}
+		g.workers = n
+		return nil
+	}
+}
+
+// Hellos greets every name and returns the messages in the same order as
+// names. A name that fails validation, or repeats an earlier name, leaves
+// an empty string at its index and contributes an error to the returned
+// errors.Join, as with the package-level Hellos; the rest of the batch is
+// still greeted. Batches larger than a few hundred names are processed by
+// a pool of WithWorkers goroutines.
+func (g *Greeter) Hellos(names []string) ([]string, error) {
+
+	messages := make([]string, len(names))
+	errs := duplicates(names)
+	// greet greets the chunk of names starting at index lo.
+	greet := func(lo int, chunk []string) {
+		for j, name := range chunk {
+			i := lo + j
+			if errs[i] != nil {
+				continue
+			}
+			message, err := g.Hello(name)
+			if err != nil {
+				errs[i] = fmt.Errorf("names[%d]: %w", i, err)
+				continue
+			}
+			messages[i] = message
+		}
+	}
+
+	if g.workers <= 1 || len(names) <= batchChunk {
+		greet(0, names)
+		return messages, errors.Join(errs...)
+	}
+
+	chunks := collections.Chunk(names, batchChunk)
+	pool := concurrent.NewPool(min(g.workers, len(chunks)), func(c int) {
+		greet(c*batchChunk, chunks[c])
+	})
+	for c := range chunks {
+		pool.Submit(c)
+	}
+	pool.Close()
+
+	return messages, errors.Join(errs...)
+}
+
+// duplicates returns an error for every name that repeats an earlier one,
+// at its index, and nil elsewhere.
+func duplicates(names []string) []error {
+	errs := make([]error, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for i, name := range names {
+		if _, ok := seen[name]; ok {
+			errs[i] = fmt.Errorf("names[%d] %q: %w", i, name, ErrDuplicateName)
+			continue
+		}
+		seen[name] = struct{}{}
+	}
+	return errs
+}
+
+// ErrorPolicy says how HellosCtx deals with names that cannot be greeted.
+type ErrorPolicy int
+
+const (
+	// CollectAll greets every name it can and reports all failures
+	// together, like Hellos. It is the default.
+	CollectAll ErrorPolicy = iota
+
+	// FailFast stops the batch at the first failure and returns it.
+	FailFast
+)
+
+// WithErrorPolicy selects how HellosCtx handles failing names.
+func WithErrorPolicy(p ErrorPolicy) Option {
+	return func(g *Greeter) error {
+		if p != CollectAll && p != FailFast {
+			return fmt.Errorf("greetings: invalid error policy %d", int(p))
+		}
+		g.errorPolicy = p
+		return nil
+	}
+}
+
+// HellosCtx is like Hellos but stops promptly once ctx is done, returning
+// ctx.Err(), and honors the Greeter's ErrorPolicy: with FailFast the first
+// name that fails cancels the rest of the batch and its error is
+// returned. Either way the messages greeted so far are returned at their
+// indexes, with empty strings elsewhere.
+func (g *Greeter) HellosCtx(ctx context.Context, names []string) ([]string, error) {
+
+	messages := make([]string, len(names))
+	errs := duplicates(names)
+	eg, gctx := errgroup.WithContext(ctx)
+	eg.SetLimit(max(g.workers, 1))
+	for c, chunk := range collections.Chunk(names, batchChunk) {
+		if gctx.Err() != nil {
+			break
+		}
+		eg.Go(func() error {
+			for j, name := range chunk {
+				i := c*batchChunk + j
+				if errs[i] != nil {
+					if g.errorPolicy == FailFast {
+						return errs[i]
+					}
+					continue
+				}
+				message, err := g.HelloCtx(gctx, name)
+				switch {
+				case gctx.Err() != nil:
+					return gctx.Err()
+				case err != nil && g.errorPolicy == FailFast:
+					return fmt.Errorf("names[%d]: %w", i, err)
+				case err != nil:
+					errs[i] = fmt.Errorf("names[%d]: %w", i, err)
+				default:
+					messages[i] = message
+				}
+			}
+			return nil
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return messages, coded(Canceled, ctx.Err())
+		}
+		return messages, err
+	}
+	if err := ctx.Err(); err != nil {
+		return messages, coded(Canceled, err)
+	}
+
+	return messages, errors.Join(errs...)
+}
+
+// HellosConcurrent greets every name with the default greeting using a pool
+// of workers goroutines (zero or less for one per CPU) and returns the
+// messages in input order. See Greeter.Hellos for error handling.
+func HellosConcurrent(names []string, workers int) ([]string, error) {
+
+	g, err := New(WithWorkers(workers))
+	if err != nil {
+		return nil, err
+	}
+
+	return g.Hellos(names)
+}
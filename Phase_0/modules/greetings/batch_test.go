@@ -0,0 +1,74 @@
+package greetings_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestGreeterHellosReportsDuplicates(t *testing.T) {
+	names := []string{"Ada", "", "Bob", "Ada"}
+	want := []string{"Hi, Ada. Welcome!", "", "Hi, Bob. Welcome!", ""}
+
+	for _, workers := range []int{1, 4} {
+		g, err := greetings.New(greetings.WithWorkers(workers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		messages, err := g.Hellos(names)
+		if !slices.Equal(messages, want) {
+			t.Errorf("workers=%d: Hellos = %q, want %q", workers, messages, want)
+		}
+		if !errors.Is(err, greetings.ErrEmptyName) || !errors.Is(err, greetings.ErrDuplicateName) {
+			t.Errorf("workers=%d: Hellos error = %v, want empty and duplicate names", workers, err)
+		}
+
+		messages, ctxErr := g.HellosCtx(context.Background(), names)
+		if !slices.Equal(messages, want) || fmt.Sprint(ctxErr) != fmt.Sprint(err) {
+			t.Errorf("workers=%d: HellosCtx = %q, %v; want Hellos's %q, %v", workers, messages, ctxErr, want, err)
+		}
+	}
+
+	// The package-level Hellos reports the same duplicate.
+	if _, err := greetings.Hellos(names); !errors.Is(err, greetings.ErrDuplicateName) {
+		t.Errorf("Hellos error = %v, want a duplicate name", err)
+	}
+}
+
+func TestGreeterHellosLargeBatch(t *testing.T) {
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = fmt.Sprintf("Gopher %d", i)
+	}
+	names[700] = names[10]
+
+	g, err := greetings.New(greetings.WithWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages, err := g.Hellos(names)
+	if want := `names[700] "Gopher 10": greetings: duplicate name`; fmt.Sprint(err) != want {
+		t.Errorf("Hellos error = %v, want %s", err, want)
+	}
+	for i, message := range messages {
+		want := fmt.Sprintf("Hi, %s. Welcome!", names[i])
+		if i == 700 {
+			want = ""
+		}
+		if message != want {
+			t.Fatalf("messages[%d] = %q, want %q", i, message, want)
+		}
+	}
+
+	g, err = greetings.New(greetings.WithErrorPolicy(greetings.FailFast))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.HellosCtx(context.Background(), names); !errors.Is(err, greetings.ErrDuplicateName) {
+		t.Errorf("FailFast HellosCtx error = %v, want the duplicate", err)
+	}
+}
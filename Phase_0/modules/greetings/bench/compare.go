@@ -0,0 +1,83 @@
+package bench
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Change sets a benchmark's result in an old run next to a new one.
+type Change struct {
+	Name     string
+	Old, New Result
+}
+
+// Regressed reports whether the new run allocates more per iteration
+// than the old one. Allocation counts are stable between runs and
+// machines, unlike timings, so they make a reliable guardrail.
+func (c Change) Regressed() bool {
+	return c.New.AllocsPerOp > c.Old.AllocsPerOp
+}
+
+// Compare pairs the results of a baseline run with those of a later one
+// by name, in the order of the later run. Benchmarks missing from either
+// run are left out.
+func Compare(baseline, results []Result) []Change {
+
+	byName := make(map[string]Result, len(baseline))
+	for _, r := range baseline {
+		byName[r.Name] = r
+	}
+
+	var changes []Change
+	for _, r := range results {
+		if o, ok := byName[r.Name]; ok {
+			changes = append(changes, Change{Name: r.Name, Old: o, New: r})
+		}
+	}
+
+	return changes
+}
+
+// WriteChanges writes changes to w as a table of time and allocations per
+// iteration, old and new, marking regressions.
+func WriteChanges(w io.Writer, changes []Change) error {
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tOLD ns/op\tNEW ns/op\tDELTA\tOLD allocs/op\tNEW allocs/op\t")
+	for _, c := range changes {
+		delta := "~"
+		if c.Old.NsPerOp > 0 {
+			delta = fmt.Sprintf("%+.1f%%", (c.New.NsPerOp/c.Old.NsPerOp-1)*100)
+		}
+		mark := ""
+		if c.Regressed() {
+			mark = "REGRESSED"
+		}
+		fmt.Fprintf(tw, "%s\t%.1f\t%.1f\t%s\t%d\t%d\t%s\n",
+			c.Name, c.Old.NsPerOp, c.New.NsPerOp, delta, c.Old.AllocsPerOp, c.New.AllocsPerOp, mark)
+	}
+
+	return tw.Flush()
+}
+
+// Budgets caps the allocations per iteration of the benchmarks whose
+// allocations are part of the package's promises: HelloTo must not
+// allocate at all.
+var Budgets = map[string]int64{
+	"HelloTo": 0,
+}
+
+// Check reports the results that allocate more per iteration than
+// budgets allows them, joined into one error. Benchmarks without a budget
+// pass.
+func Check(results []Result, budgets map[string]int64) error {
+	var errs []error
+	for _, r := range results {
+		if limit, ok := budgets[r.Name]; ok && r.AllocsPerOp > limit {
+			errs = append(errs, fmt.Errorf("bench: %s: %d allocs/op, budget is %d", r.Name, r.AllocsPerOp, limit))
+		}
+	}
+	return errors.Join(errs...)
+}
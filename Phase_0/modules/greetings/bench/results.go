@@ -0,0 +1,107 @@
+// Package bench keeps the performance of the greetings package from
+// regressing. The benchmarks themselves live next to the code they
+// measure, in the packages' _test.go files: a single greeting through
+// each rendering path, a fmt.Sprintf baseline, a large batch and the
+// worker-pool mode. ParseResults and WriteResults load and save the
+// output of "go test -bench", and Compare and Check set two runs side by
+// side and hold results to allocation budgets such as the zero-allocation
+// HelloTo:
+//
+//	results, err := bench.ParseResults(output)
+//	...
+//	if err := bench.Check(results, bench.Budgets); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// The greet-bench command does the same from the command line.
+package bench
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Result is the outcome of one benchmark.
+type Result struct {
+	Name        string
+	N           int     // iterations
+	NsPerOp     float64 // nanoseconds per iteration
+	BytesPerOp  int64   // bytes allocated per iteration
+	AllocsPerOp int64   // allocations per iteration
+}
+
+// String returns r as a line of "go test -bench" output.
+func (r Result) String() string {
+	return fmt.Sprintf("Benchmark%s\t%d\t%.1f ns/op\t%d B/op\t%d allocs/op",
+		r.Name, r.N, r.NsPerOp, r.BytesPerOp, r.AllocsPerOp)
+}
+
+// WriteResults writes results to w in the format of "go test -bench", one
+// line each.
+func WriteResults(w io.Writer, results []Result) error {
+	for _, r := range results {
+		if _, err := fmt.Fprintln(w, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseResults reads the benchmark lines of "go test -bench" output, such
+// as WriteResults writes, skipping every other line. The "-8" suffix go
+// test adds for GOMAXPROCS is dropped from names, so runs on machines
+// with different numbers of CPUs compare. Lines without -benchmem figures have zero
+// allocations.
+func ParseResults(r io.Reader) ([]Result, error) {
+
+	var results []Result
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+			continue
+		}
+		res := Result{Name: trimProcs(strings.TrimPrefix(fields[0], "Benchmark"))}
+		n, err := strconv.Atoi(fields[1])
+		if err != nil {
+			continue // a benchmark's own log line
+		}
+		res.N = n
+		for i := 2; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("bench: line %d: bad %s value %q", line, fields[i+1], fields[i])
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				res.NsPerOp = value
+			case "B/op":
+				res.BytesPerOp = int64(math.Round(value))
+			case "allocs/op":
+				res.AllocsPerOp = int64(math.Round(value))
+			}
+		}
+		results = append(results, res)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// trimProcs drops the "-8" go test appends to benchmark names.
+func trimProcs(name string) string {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
@@ -0,0 +1,88 @@
+package bench_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"example.com/greetings/bench"
+)
+
+const output = `goos: linux
+goarch: amd64
+pkg: example.com/greetings
+BenchmarkHello/catalog-8         	 1000000	      883.9 ns/op	     195 B/op	       4 allocs/op
+BenchmarkSprintf-8               	 9000000	      132.6 ns/op	      24 B/op	       1 allocs/op
+BenchmarkHelloTo-8               	20000000	       68.5 ns/op
+PASS
+`
+
+func TestParseResults(t *testing.T) {
+	results, err := bench.ParseResults(strings.NewReader(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []bench.Result{
+		{Name: "Hello/catalog", N: 1000000, NsPerOp: 883.9, BytesPerOp: 195, AllocsPerOp: 4},
+		{Name: "Sprintf", N: 9000000, NsPerOp: 132.6, BytesPerOp: 24, AllocsPerOp: 1},
+		{Name: "HelloTo", N: 20000000, NsPerOp: 68.5},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("ParseResults = %+v, want %+v", results, want)
+	}
+	for i := range want {
+		if results[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, results[i], want[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := bench.WriteResults(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+	again, err := bench.ParseResults(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range results {
+		if again[i] != results[i] {
+			t.Errorf("after WriteResults, result %d = %+v, want %+v", i, again[i], results[i])
+		}
+	}
+}
+
+func TestCompareAndCheck(t *testing.T) {
+	baseline := []bench.Result{
+		{Name: "Hello/catalog", AllocsPerOp: 4},
+		{Name: "HelloTo", AllocsPerOp: 0},
+		{Name: "Gone", AllocsPerOp: 1},
+	}
+	results := []bench.Result{
+		{Name: "HelloTo", AllocsPerOp: 1},
+		{Name: "Hello/catalog", AllocsPerOp: 3},
+		{Name: "New", AllocsPerOp: 9},
+	}
+
+	changes := bench.Compare(baseline, results)
+	for i, tt := range []struct {
+		name      string
+		regressed bool
+	}{
+		{"HelloTo", true},
+		{"Hello/catalog", false},
+	} {
+		if i >= len(changes) || changes[i].Name != tt.name || changes[i].Regressed() != tt.regressed {
+			t.Fatalf("Compare = %+v, want %s regressed=%v at %d", changes, tt.name, tt.regressed, i)
+		}
+	}
+	if len(changes) != 2 {
+		t.Errorf("Compare returned %d changes, want 2", len(changes))
+	}
+
+	if err := bench.Check(results, bench.Budgets); err == nil || !strings.Contains(err.Error(), "HelloTo") {
+		t.Errorf("Check = %v, want HelloTo over budget", err)
+	}
+	if err := bench.Check(baseline, bench.Budgets); err != nil {
+		t.Errorf("Check(baseline) = %v, want nil", err)
+	}
+}
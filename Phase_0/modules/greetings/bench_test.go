@@ -0,0 +1,97 @@
+package greetings_test
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"slices"
+	"testing"
+
+	"example.com/greetings"
+)
+
+// newGreeter returns a Greeter configured by opts, failing the benchmark
+// when the options are invalid.
+func newGreeter(b *testing.B, opts ...greetings.Option) *greetings.Greeter {
+	b.Helper()
+	g, err := greetings.New(opts...)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return g
+}
+
+// BenchmarkHello greets one name through the regular rendering path: the
+// catalog, with emoji, through a text/template style and through
+// WithTextTemplate.
+func BenchmarkHello(b *testing.B) {
+	for _, bm := range []struct {
+		name string
+		opts []greetings.Option
+	}{
+		{"catalog", nil},
+		{"emoji", []greetings.Option{greetings.WithEmoji(true)}},
+		{"style", []greetings.Option{greetings.WithStyle("welcome-back")}},
+		{"template", []greetings.Option{greetings.WithTextTemplate("Hi, {{.Name}}. Welcome!")}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			g := newGreeter(b, bm.opts...)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := g.Hello("Gladys"); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSprintf formats the default English greeting by hand, the
+// floor the catalog path is measured against.
+func BenchmarkSprintf(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = fmt.Sprintf("Hi, %v. Welcome!", "Gladys")
+	}
+}
+
+// BenchmarkHelloTo greets one name through the writer-based fast path.
+func BenchmarkHelloTo(b *testing.B) {
+	g := newGreeter(b)
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for b.Loop() {
+		buf.Reset()
+		if _, err := g.HelloTo(&buf, "Gladys"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkHellos greets a batch of 100,000 names one by one and with
+// pools of workers, up to GOMAXPROCS.
+func BenchmarkHellos(b *testing.B) {
+
+	names := make([]string, 100_000)
+	for i := range names {
+		names[i] = fmt.Sprintf("Gopher %d", i)
+	}
+
+	workers := []int{1, 2, 4, 8, runtime.GOMAXPROCS(0)}
+	slices.Sort(workers)
+	for _, w := range slices.Compact(workers) {
+		name := fmt.Sprintf("workers=%d", w)
+		if w == 1 {
+			name = "sequential"
+		}
+		b.Run(name, func(b *testing.B) {
+			g := newGreeter(b, greetings.WithWorkers(w))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := g.Hellos(names); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package greetings
+
+import "golang.org/x/text/language"
+
+// Unicode bidirectional isolates. Text between FSI and PDI is laid out on
+// its own, in the direction of its first strong character, so a Latin
+// name inside an Arabic sentence, or the reverse, cannot reorder the
+// punctuation around it.
+const (
+	fsi = "\u2068" // FIRST STRONG ISOLATE
+	pdi = "\u2069" // POP DIRECTIONAL ISOLATE
+)
+
+// rtlScripts are the scripts written right to left among those the
+// catalogs are likely to meet.
+var rtlScripts = map[string]bool{
+	"Arab": true, "Hebr": true, "Syrc": true, "Thaa": true,
+	"Nkoo": true, "Adlm": true, "Rohg": true,
+}
+
+// IsRTL reports whether locale is written right to left, as "ar" and
+// "he-IL" are. Unparsable locales are assumed left to right.
+func IsRTL(locale string) bool {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return false
+	}
+	script, _ := tag.Script()
+	return rtlScripts[script.String()]
+}
+
+// Isolate wraps s in Unicode bidi isolation marks so that it keeps its own
+// direction when embedded in text of the other direction.
+func Isolate(s string) string {
+	return fsi + s + pdi
+}
+
+// WithBidiIsolation turns wrapping every name the Greeter interpolates
+// in bidi isolation marks (see Isolate) on or off; Greeting.Name stays
+// unmarked. It is on by default for right-to-left locales such as "ar"
+// and "he" (see IsRTL), and worth turning on for any greeting that may
+// mix scripts.
+func WithBidiIsolation(on bool) Option {
+	return func(g *Greeter) error {
+		g.isolate = on
+		g.isolateSet = true
+		return nil
+	}
+}
@@ -0,0 +1,66 @@
+package greetings_test
+
+import (
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestIsRTL(t *testing.T) {
+	for locale, want := range map[string]bool{
+		"ar": true, "he-IL": true, "fa": true, "ur": true,
+		"en": false, "ja": false, "sr-Cyrl": false, "": false, "not a tag": false,
+	} {
+		if got := greetings.IsRTL(locale); got != want {
+			t.Errorf("IsRTL(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+func TestBidiIsolation(t *testing.T) {
+	for _, tt := range []struct {
+		locale string
+		opts   []greetings.Option
+		want   string
+	}{
+		{"ar", nil, "مرحبًا، \u2068Alice\u2069. أهلًا وسهلًا!"},
+		{"ar", []greetings.Option{greetings.WithBidiIsolation(false)}, "مرحبًا، Alice. أهلًا وسهلًا!"},
+		{"en", nil, "Hi, Alice. Welcome!"},
+		{"en", []greetings.Option{greetings.WithBidiIsolation(true)}, "Hi, \u2068Alice\u2069. Welcome!"},
+	} {
+		g, err := greetings.New(append([]greetings.Option{greetings.WithLocale(tt.locale)}, tt.opts...)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		greeting, err := g.Greet("Alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if greeting.Message != tt.want {
+			t.Errorf("%s: Greet(Alice) = %q, want %q", tt.locale, greeting.Message, tt.want)
+		}
+		if greeting.Name != "Alice" {
+			t.Errorf("%s: Greeting.Name = %q, want it unmarked", tt.locale, greeting.Name)
+		}
+	}
+}
+
+func TestHTMLDirection(t *testing.T) {
+	for _, tt := range []struct {
+		greeting greetings.Greeting
+		want     string
+	}{
+		{
+			greetings.Greeting{Message: "Hi, Alice. Welcome!", Name: "Alice", Locale: "en"},
+			`Hi, <strong class="name">Alice</strong>. Welcome!`,
+		},
+		{
+			greetings.Greeting{Message: "שלום, Alice. ברוכים הבאים!", Name: "Alice", Locale: "he"},
+			`<span dir="rtl">שלום, <strong class="name">Alice</strong>. ברוכים הבאים!</span>`,
+		},
+	} {
+		if got := greetings.HTML(tt.greeting); got != tt.want {
+			t.Errorf("HTML(%q) = %q, want %q", tt.greeting.Message, got, tt.want)
+		}
+	}
+}
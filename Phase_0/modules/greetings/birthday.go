@@ -0,0 +1,55 @@
+package greetings
+
+import (
+	"fmt"
+	"time"
+)
+
+// LeapDay says when people born on February 29th celebrate in years
+// without one.
+type LeapDay int
+
+const (
+	// LeapDayFeb28 greets them on February 28th. It is the default.
+	LeapDayFeb28 LeapDay = iota
+
+	// LeapDayMar1 greets them on March 1st.
+	LeapDayMar1
+)
+
+// WithLeapDay selects when recipients born on February 29th get their
+// birthday greeting in common years.
+func WithLeapDay(rule LeapDay) Option {
+	return func(g *Greeter) error {
+		if rule != LeapDayFeb28 && rule != LeapDayMar1 {
+			return fmt.Errorf("greetings: invalid leap day rule %d", int(rule))
+		}
+		g.leapDay = rule
+		return nil
+	}
+}
+
+// IsBirthday reports whether the date of t, in t's location, is the
+// birthday of someone born on birthday, moving February 29th birthdays as
+// rule says in common years. A zero birthday is never matched.
+func IsBirthday(birthday, t time.Time, rule LeapDay) bool {
+
+	if birthday.IsZero() {
+		return false
+	}
+	month, day := birthday.Month(), birthday.Day()
+	if month == time.February && day == 29 && !isLeap(t.Year()) {
+		if rule == LeapDayMar1 {
+			month, day = time.March, 1
+		} else {
+			day = 28
+		}
+	}
+
+	return t.Month() == month && t.Day() == day
+}
+
+// isLeap reports whether year has a February 29th.
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
@@ -0,0 +1,40 @@
+package greetings_test
+
+import (
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/greetingstest"
+)
+
+func TestBirthdayGreeting(t *testing.T) {
+	today := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		name     string
+		opts     []greetings.Option
+		birthday time.Time
+		want     string
+	}{
+		{"not today", nil, time.Date(1990, 7, 4, 0, 0, 0, 0, time.UTC), "Hi, Ada. Welcome!"},
+		{"catalog", nil, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), "Happy birthday, Ada! 🎂"},
+		{"emoji", []greetings.Option{greetings.WithEmoji(true)}, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), "Happy birthday, Ada! 🎂 👋"},
+		{"template", []greetings.Option{greetings.WithTemplate("Howdy %v")}, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), "Howdy Ada!"},
+		{"punctuation", []greetings.Option{greetings.WithPunctuation("?")}, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), "Hi, Ada. Welcome?"},
+		{"formal", []greetings.Option{greetings.WithFormality(greetings.Formal)}, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), "Dear Ada, welcome."},
+		{"es", []greetings.Option{greetings.WithLocale("es")}, time.Date(1990, 3, 1, 0, 0, 0, 0, time.UTC), "¡Feliz cumpleaños, Ada! 🎂"},
+	} {
+		opts := append([]greetings.Option{greetings.WithClock(greetingstest.NewFakeClock(today))}, tt.opts...)
+		g, err := greetings.New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		greeting, err := g.GreetPerson(greetings.Person{Name: "Ada", Birthday: tt.birthday})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if greeting.Message != tt.want {
+			t.Errorf("%s: GreetPerson(Ada) = %q, want %q", tt.name, greeting.Message, tt.want)
+		}
+	}
+}
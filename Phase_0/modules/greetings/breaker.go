@@ -0,0 +1,202 @@
+package greetings
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by a Breaker's Middleware while the breaker
+// is open and the provider behind it is not being called.
+var ErrCircuitOpen = newError(ProviderUnavailable, "greetings: circuit open")
+
+// BreakerState is the state of a Breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets every call through. It is where a Breaker
+	// starts.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen lets no call through until the cooldown is over.
+	BreakerOpen
+
+	// BreakerHalfOpen lets a single probe through; its outcome closes the
+	// breaker again or keeps it open for another cooldown.
+	BreakerHalfOpen
+)
+
+var breakerStateNames = [...]string{BreakerClosed: "closed", BreakerOpen: "open", BreakerHalfOpen: "half-open"}
+
+// String returns the lower-case name of s.
+func (s BreakerState) String() string {
+	if s < 0 || int(s) >= len(breakerStateNames) {
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+	return breakerStateNames[s]
+}
+
+// Breaker is a circuit breaker for providers backed by remote services.
+// After a number of consecutive transient failures (see IsTransient),
+// which fail with the ProviderUnavailable code, it opens and stops calling
+// the provider; once its cooldown is over it lets one probe through, and
+// closes again if the probe succeeds. Permanent errors and the caller's
+// own cancellation do not count. Install it with
+// WithBreaker, which greets from the catalog while the breaker is open,
+// or Use(b.Middleware), which fails with ErrCircuitOpen instead. A
+// Breaker is safe for concurrent use; share one between Greeters calling
+// the same service.
+type Breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	timeout   time.Duration
+	clock     Clock
+
+	state    BreakerState
+	failures int       // consecutive, while closed
+	openedAt time.Time // while open
+	probing  bool      // a half-open probe is under way
+}
+
+// NewBreaker returns a closed Breaker that opens after failures
+// consecutive transient failures and stays open for cooldown. Failures
+// below one are raised to one.
+func NewBreaker(failures int, cooldown time.Duration) *Breaker {
+	return &Breaker{threshold: max(failures, 1), cooldown: cooldown, clock: SystemClock}
+}
+
+// SetTimeout bounds every call b lets through to d; a call that runs out
+// of time fails transiently and counts against the provider. Zero, the
+// default, leaves calls bounded only by the caller's context. SetTimeout
+// must be called before b is used.
+func (b *Breaker) SetTimeout(d time.Duration) {
+	b.timeout = d
+}
+
+// SetClock makes b time its cooldown by c instead of the system's clock. A
+// nil c means SystemClock. SetClock must be called before b is used.
+func (b *Breaker) SetClock(c Clock) {
+	if c == nil {
+		c = SystemClock
+	}
+	b.clock = c
+}
+
+// State returns b's current state. An open breaker whose cooldown is over
+// reports BreakerHalfOpen, as its next call will be a probe.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.cooled() {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// WithBreaker guards the provider set with WithProvider with b. While b is
+// open, greetings come from the Greeter's catalog and templates, as they
+// would without WithProvider, instead of failing. Without WithProvider it
+// has nothing to guard and no effect.
+func WithBreaker(b *Breaker) Option {
+	return func(g *Greeter) error {
+		if b == nil {
+			return fmt.Errorf("greetings: nil breaker")
+		}
+		g.breaker = b
+		return nil
+	}
+}
+
+// Middleware calls next while b lets it and fails with ErrCircuitOpen
+// otherwise.
+func (b *Breaker) Middleware(next Provider) Provider {
+	return b.guard(next, ProviderFunc(func(context.Context, Request) (Greeting, error) {
+		return Greeting{}, ErrCircuitOpen
+	}))
+}
+
+// guard returns a Provider calling next while b lets it, and fallback
+// otherwise.
+func (b *Breaker) guard(next, fallback Provider) Provider {
+	return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+
+		if !b.allow() {
+			return fallback.Greet(ctx, req)
+		}
+
+		var greeting Greeting
+		err := tryOnce(ctx, func(ctx context.Context) error {
+			var err error
+			greeting, err = next.Greet(ctx, req)
+			return err
+		}, b.timeout)
+		failed := err != nil && ctx.Err() == nil && IsTransient(err)
+		b.done(err == nil, failed)
+		if failed && CodeOf(err) != ProviderUnavailable {
+			// A call cut short by b's timeout is the provider's fault,
+			// not the caller's.
+			err = &Error{Code: ProviderUnavailable, Err: err}
+		}
+		if err != nil {
+			return Greeting{}, err
+		}
+
+		return greeting, nil
+	})
+}
+
+// allow reports whether a call may go through, starting a probe when an
+// open breaker has cooled down.
+func (b *Breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if !b.cooled() {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		fallthrough
+	case BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// done records the outcome of a call allow let through. A call that
+// neither succeeded nor failed transiently leaves the failure count
+// alone, but still ends a probe.
+func (b *Breaker) done(ok, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	probe := b.state == BreakerHalfOpen
+	if probe {
+		b.probing = false
+	}
+	switch {
+	case ok:
+		b.state, b.failures = BreakerClosed, 0
+	case failed && probe:
+		b.open()
+	case failed && b.state == BreakerClosed:
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	}
+}
+
+// open opens b for a cooldown. b.mu must be held.
+func (b *Breaker) open() {
+	b.state, b.failures, b.openedAt = BreakerOpen, 0, b.clock.Now()
+}
+
+// cooled reports whether b's cooldown is over. b.mu must be held.
+func (b *Breaker) cooled() bool {
+	return b.clock.Now().Sub(b.openedAt) >= b.cooldown
+}
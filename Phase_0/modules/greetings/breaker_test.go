@@ -0,0 +1,141 @@
+package greetings_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/greetingstest"
+)
+
+// flakyProvider greets remotely, failing with next until it is cleared,
+// and counts the calls.
+type flakyProvider struct {
+	next  error
+	calls int
+}
+
+func (p *flakyProvider) Greet(_ context.Context, req greetings.Request) (greetings.Greeting, error) {
+	p.calls++
+	if p.next != nil {
+		return greetings.Greeting{}, p.next
+	}
+	return greetings.Greeting{Message: "Remote hi, " + req.Recipients[0].Name}, nil
+}
+
+var (
+	errFlaky  = greetings.Transient(errors.New("remote: unavailable"))
+	errBroken = errors.New("remote: bad request")
+)
+
+func TestBreakerStates(t *testing.T) {
+	clock := greetingstest.NewFakeClock(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	b := greetings.NewBreaker(2, time.Minute)
+	b.SetClock(clock)
+	p := &flakyProvider{}
+	g, err := greetings.New(greetings.WithProvider(p), greetings.Use(b.Middleware))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tt := range []struct {
+		advance    time.Duration
+		result     error
+		wantCalled bool
+		wantErr    error
+		wantState  greetings.BreakerState
+	}{
+		{0, errFlaky, true, errFlaky, greetings.BreakerClosed},
+		{0, errBroken, true, errBroken, greetings.BreakerClosed}, // permanent, not counted
+		{0, errFlaky, true, errFlaky, greetings.BreakerOpen},
+		{30 * time.Second, nil, false, greetings.ErrCircuitOpen, greetings.BreakerOpen},
+		{30 * time.Second, errFlaky, true, errFlaky, greetings.BreakerOpen}, // failed probe
+		{0, nil, false, greetings.ErrCircuitOpen, greetings.BreakerOpen},
+		{time.Minute, nil, true, nil, greetings.BreakerClosed}, // successful probe
+		{0, errFlaky, true, errFlaky, greetings.BreakerClosed},
+		{0, nil, true, nil, greetings.BreakerClosed}, // success resets the count
+		{0, errFlaky, true, errFlaky, greetings.BreakerClosed},
+	} {
+		clock.Advance(tt.advance)
+		p.next = tt.result
+		calls := p.calls
+		_, err := g.Greet("Ann")
+		if called := p.calls > calls; called != tt.wantCalled {
+			t.Errorf("step %d: provider called = %v, want %v", i, called, tt.wantCalled)
+		}
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("step %d: error = %v, want %v", i, err, tt.wantErr)
+		}
+		if tt.wantErr == errFlaky && greetings.CodeOf(err) != greetings.ProviderUnavailable {
+			t.Errorf("step %d: error code = %v, want %v", i, greetings.CodeOf(err), greetings.ProviderUnavailable)
+		}
+		if got := b.State(); got != tt.wantState {
+			t.Errorf("step %d: State() = %v, want %v", i, got, tt.wantState)
+		}
+	}
+}
+
+func TestWithBreakerFallsBack(t *testing.T) {
+	clock := greetingstest.NewFakeClock(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	b := greetings.NewBreaker(1, time.Minute)
+	b.SetClock(clock)
+	p := &flakyProvider{next: errFlaky}
+	g, err := greetings.New(greetings.WithProvider(p), greetings.WithBreaker(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		advance   time.Duration
+		want      string
+		wantState greetings.BreakerState
+	}{
+		{0, "", greetings.BreakerOpen},
+		{0, "Hi, Ann. Welcome!", greetings.BreakerOpen}, // from the catalog
+		{time.Minute, "", greetings.BreakerOpen},        // failed probe
+		{0, "Hi, Ann. Welcome!", greetings.BreakerOpen},
+	} {
+		clock.Advance(tt.advance)
+		got, err := g.Hello("Ann")
+		if tt.want == "" && err == nil || tt.want != "" && got != tt.want {
+			t.Errorf("Hello(Ann) = %q, %v, want %q", got, err, tt.want)
+		}
+		if state := b.State(); state != tt.wantState {
+			t.Errorf("State() = %v, want %v", state, tt.wantState)
+		}
+	}
+
+	clock.Advance(time.Minute)
+	if got := b.State(); got != greetings.BreakerHalfOpen {
+		t.Errorf("State() after the cooldown = %v, want %v", got, greetings.BreakerHalfOpen)
+	}
+	p.next = nil
+	if got, err := g.Hello("Ann"); err != nil || got != "Remote hi, Ann" {
+		t.Errorf("probe Hello(Ann) = %q, %v, want the provider's greeting", got, err)
+	}
+	if got := b.State(); got != greetings.BreakerClosed {
+		t.Errorf("State() after a successful probe = %v, want %v", got, greetings.BreakerClosed)
+	}
+}
+
+func TestBreakerTimeout(t *testing.T) {
+	b := greetings.NewBreaker(1, time.Hour)
+	b.SetTimeout(10 * time.Millisecond)
+	slow := greetings.ProviderFunc(func(ctx context.Context, _ greetings.Request) (greetings.Greeting, error) {
+		<-ctx.Done()
+		return greetings.Greeting{}, ctx.Err()
+	})
+	g, err := greetings.New(greetings.WithProvider(slow), greetings.Use(b.Middleware))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.Hello("Ann"); greetings.CodeOf(err) != greetings.ProviderUnavailable {
+		t.Errorf("timed-out Hello error = %v (%v), want %v", err, greetings.CodeOf(err), greetings.ProviderUnavailable)
+	}
+	if got := b.State(); got != greetings.BreakerOpen {
+		t.Errorf("State() = %v, want %v", got, greetings.BreakerOpen)
+	}
+}
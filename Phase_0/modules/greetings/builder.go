@@ -0,0 +1,147 @@
+package greetings
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Builder assembles a Greeter's configuration step by step, for settings
+// gathered across several code paths:
+//
+//	g, err := greetings.NewBuilder().Locale("fr").Formality(greetings.Formal).Build()
+//
+// Unlike New, which stops at the first bad option, Build checks every step
+// and the configuration as a whole and reports all the problems at once.
+// The zero Builder is ready to use; a Builder is not safe for concurrent
+// use.
+type Builder struct {
+	steps []step
+}
+
+// step is one builder call: the option it adds and how to name it in
+// diagnostics.
+type step struct {
+	name string
+	opt  Option
+}
+
+// NewBuilder returns an empty Builder, which builds the default Greeter.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (b *Builder) add(name string, opt Option) *Builder {
+	b.steps = append(b.steps, step{name, opt})
+	return b
+}
+
+// Locale is like WithLocale.
+func (b *Builder) Locale(locale string) *Builder {
+	return b.add(fmt.Sprintf("Locale(%q)", locale), WithLocale(locale))
+}
+
+// Catalog is like WithCatalog.
+func (b *Builder) Catalog(c Catalog) *Builder {
+	return b.add("Catalog", WithCatalog(c))
+}
+
+// Template is like WithTemplate.
+func (b *Builder) Template(template string) *Builder {
+	return b.add(fmt.Sprintf("Template(%q)", template), WithTemplate(template))
+}
+
+// Punctuation is like WithPunctuation.
+func (b *Builder) Punctuation(punctuation string) *Builder {
+	return b.add(fmt.Sprintf("Punctuation(%q)", punctuation), WithPunctuation(punctuation))
+}
+
+// Formality is like WithFormality.
+func (b *Builder) Formality(f Formality) *Builder {
+	return b.add(fmt.Sprintf("Formality(%v)", f), WithFormality(f))
+}
+
+// Style is like WithStyle.
+func (b *Builder) Style(name string) *Builder {
+	return b.add(fmt.Sprintf("Style(%q)", name), WithStyle(name))
+}
+
+// Theme is like WithTheme.
+func (b *Builder) Theme(name string) *Builder {
+	return b.add(fmt.Sprintf("Theme(%q)", name), WithTheme(name))
+}
+
+// TextTemplate is like WithTextTemplate.
+func (b *Builder) TextTemplate(text string) *Builder {
+	return b.add(fmt.Sprintf("TextTemplate(%q)", text), WithTextTemplate(text))
+}
+
+// Honorific is like WithHonorific.
+func (b *Builder) Honorific(title string) *Builder {
+	return b.add(fmt.Sprintf("Honorific(%q)", title), WithHonorific(title))
+}
+
+// Emoji is like WithEmoji.
+func (b *Builder) Emoji(on bool) *Builder {
+	return b.add(fmt.Sprintf("Emoji(%t)", on), WithEmoji(on))
+}
+
+// Format is like WithFormat.
+func (b *Builder) Format(f Format) *Builder {
+	return b.add(fmt.Sprintf("Format(%v)", f), WithFormat(f))
+}
+
+// MaxLength is like WithMaxLength.
+func (b *Builder) MaxLength(n int) *Builder {
+	return b.add(fmt.Sprintf("MaxLength(%d)", n), WithMaxLength(n))
+}
+
+// MaxNameLength is like WithMaxNameLength.
+func (b *Builder) MaxNameLength(n int) *Builder {
+	return b.add(fmt.Sprintf("MaxNameLength(%d)", n), WithMaxNameLength(n))
+}
+
+// Ellipsis is like WithEllipsis.
+func (b *Builder) Ellipsis(s string) *Builder {
+	return b.add(fmt.Sprintf("Ellipsis(%q)", s), WithEllipsis(s))
+}
+
+// Use is like the Use option.
+func (b *Builder) Use(mw ...Middleware) *Builder {
+	return b.add("Use", Use(mw...))
+}
+
+// Options adds options that have no method of their own.
+func (b *Builder) Options(opts ...Option) *Builder {
+	for _, opt := range opts {
+		b.add("Options", opt)
+	}
+	return b
+}
+
+// Build returns the configured Greeter. If any step or the configuration
+// as a whole is invalid it instead returns every problem, each prefixed
+// by the step it comes from, joined into one error.
+func (b *Builder) Build() (*Greeter, error) {
+
+	g := newGreeter()
+	var errs []error
+	for _, s := range b.steps {
+		if err := s.opt(g); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+		}
+	}
+	if g.textTemplate != nil && (g.templateSet || g.punctuationSet) {
+		errs = append(errs, errors.New("greetings: Template and Punctuation have no effect with Style or TextTemplate"))
+	}
+	if g.ellipsisSet && g.maxLength <= 0 {
+		errs = append(errs, errors.New("greetings: Ellipsis has no effect without MaxLength"))
+	}
+	if err := g.init(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return g, nil
+}
@@ -0,0 +1,186 @@
+package greetings
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Cache is a size-bounded, least-recently-used cache of greetings, meant
+// for hot paths that greet the same names over and over. Install it with
+// WithCache or Use(c.Middleware). Entries are keyed by recipients, locale,
+// formality and style; errors are never cached. A cached greeting keeps
+// its message but gets a fresh GeneratedAt, so do not cache templates that
+// print the time. A Cache is safe for concurrent use. Several Greeters may
+// share one to share its size, but not its greetings: each installation
+// of the middleware only gets back the greetings it rendered itself, as
+// Greeters can differ in every setting that shapes a message.
+type Cache struct {
+	mu     sync.Mutex
+	size   int
+	scopes uint64     // installations of Middleware so far
+	order  *list.List // of *cacheEntry, most recently used first
+	items  map[cacheKey]*list.Element
+	hits   uint64
+	misses uint64
+}
+
+type cacheKey struct {
+	scope      uint64
+	recipients string
+	locale     string
+	style      string
+	formality  Formality
+}
+
+type cacheEntry struct {
+	key      cacheKey
+	names    []string
+	greeting Greeting
+}
+
+// CacheStats reports how a Cache has been doing.
+type CacheStats struct {
+	Hits, Misses uint64
+	Len, Size    int
+}
+
+// NewCache returns a Cache holding at most size greetings.
+func NewCache(size int) *Cache {
+	if size < 1 {
+		size = 1
+	}
+	return &Cache{size: size, order: list.New(), items: make(map[cacheKey]*list.Element)}
+}
+
+// WithCache puts c in front of the Greeter's provider.
+func WithCache(c *Cache) Option {
+	return Use(c.Middleware)
+}
+
+// Middleware answers from the cache when it can and otherwise asks next,
+// remembering the result. Every call starts a separate scope of entries.
+func (c *Cache) Middleware(next Provider) Provider {
+
+	c.mu.Lock()
+	c.scopes++
+	scope := c.scopes
+	c.mu.Unlock()
+
+	return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+
+		key := keyFor(req)
+		key.scope = scope
+		if greeting, ok := c.get(key); ok {
+			greeting.GeneratedAt = req.Time
+			return greeting, nil
+		}
+
+		greeting, err := next.Greet(ctx, req)
+		if err != nil {
+			return greeting, err
+		}
+		c.add(key, req, greeting)
+
+		return greeting, nil
+	})
+}
+
+// keyFor returns the cache key of req. Everything about a recipient that
+// can change the message takes part in it, and so do the template
+// variables.
+func keyFor(req Request) cacheKey {
+	var b strings.Builder
+	for _, p := range req.Recipients {
+		fmt.Fprintf(&b, "%s\x00%s\x00%v\x00", p.Name, p.Title, p.Pronouns)
+		if !p.Birthday.IsZero() {
+			fmt.Fprintf(&b, "%s\x00%s\x00", p.Birthday.Format("01-02"), req.Time.Format("01-02"))
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(req.Vars)) {
+		fmt.Fprintf(&b, "%s=%v\x00", k, req.Vars[k])
+	}
+	if !req.LastSeen.IsZero() {
+		fmt.Fprintf(&b, "seen %d days ago\x00", req.DaysSince())
+	}
+	if req.VisitCount > 0 {
+		fmt.Fprintf(&b, "visit %d\x00", req.VisitCount)
+	}
+	return cacheKey{recipients: b.String(), locale: req.Locale, style: req.Style, formality: req.Formality}
+}
+
+func (c *Cache) get(key cacheKey) (Greeting, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return Greeting{}, false
+	}
+	c.hits++
+	c.order.MoveToFront(e)
+	return e.Value.(*cacheEntry).greeting, true
+}
+
+func (c *Cache) add(key cacheKey, req Request, greeting Greeting) {
+
+	names := make([]string, len(req.Recipients))
+	for i, p := range req.Recipients {
+		names[i] = p.Name
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.items[key]; ok {
+		c.order.MoveToFront(e)
+		e.Value.(*cacheEntry).greeting = greeting
+		return
+	}
+	c.items[key] = c.order.PushFront(&cacheEntry{key: key, names: names, greeting: greeting})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove drops e from the cache; c.mu must be held.
+func (c *Cache) remove(e *list.Element) {
+	delete(c.items, e.Value.(*cacheEntry).key)
+	c.order.Remove(e)
+}
+
+// Invalidate drops every cached greeting addressed to name, in any locale
+// or style, and reports how many were dropped. Use it when something that
+// feeds a greeting for that person changes.
+func (c *Cache) Invalidate(name string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for e := c.order.Front(); e != nil; {
+		next := e.Next()
+		if slices.Contains(e.Value.(*cacheEntry).names, name) {
+			c.remove(e)
+			n++
+		}
+		e = next
+	}
+	return n
+}
+
+// Purge empties the cache. The hit and miss counters are kept.
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.items)
+}
+
+// Stats returns the cache's counters and occupancy.
+func (c *Cache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{Hits: c.hits, Misses: c.misses, Len: c.order.Len(), Size: c.size}
+}
@@ -0,0 +1,110 @@
+package greetings_test
+
+import (
+	"context"
+	"testing"
+
+	"example.com/greetings"
+)
+
+// countingProvider greets with its message and counts the calls.
+type countingProvider struct {
+	message string
+	calls   int
+}
+
+func (p *countingProvider) Greet(_ context.Context, req greetings.Request) (greetings.Greeting, error) {
+	p.calls++
+	return greetings.Greeting{Message: p.message + ", " + req.Recipients[0].Name}, nil
+}
+
+func TestCacheHitsAndEviction(t *testing.T) {
+	p := &countingProvider{message: "Hi"}
+	c := greetings.NewCache(2)
+	g, err := greetings.New(greetings.WithProvider(p), greetings.WithCache(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		wantCalls int
+	}{
+		{"Alice", 1}, // miss
+		{"Alice", 1}, // hit
+		{"Bob", 2},   // miss
+		{"Alice", 2}, // hit, Bob is now least recently used
+		{"Carol", 3}, // miss, evicts Bob
+		{"Alice", 3}, // hit
+		{"Bob", 4},   // miss again
+	} {
+		if _, err := g.Hello(tt.name); err != nil {
+			t.Fatal(err)
+		}
+		if p.calls != tt.wantCalls {
+			t.Fatalf("after greeting %s: provider called %d times, want %d", tt.name, p.calls, tt.wantCalls)
+		}
+	}
+
+	want := greetings.CacheStats{Hits: 3, Misses: 4, Len: 2, Size: 2}
+	if got := c.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCacheInvalidate(t *testing.T) {
+	p := &countingProvider{message: "Hi"}
+	c := greetings.NewCache(10)
+	g, err := greetings.New(greetings.WithProvider(p), greetings.WithCache(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Alice", "Bob", "Alice"} {
+		if _, err := g.Hello(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := c.Invalidate("Alice"); n != 1 {
+		t.Errorf("Invalidate(Alice) = %d, want 1", n)
+	}
+	if _, err := g.Hello("Alice"); err != nil {
+		t.Fatal(err)
+	}
+	if p.calls != 3 {
+		t.Errorf("provider called %d times, want 3", p.calls)
+	}
+}
+
+func TestCacheSharedBetweenGreeters(t *testing.T) {
+	c := greetings.NewCache(10)
+	hi, err := greetings.New(greetings.WithCache(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ahoy, err := greetings.New(greetings.WithCache(c), greetings.WithTemplate("Ahoy, %v"), greetings.WithPunctuation("!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		g    *greetings.Greeter
+		want string
+	}{
+		{hi, "Hi, Alice. Welcome!"},
+		{ahoy, "Ahoy, Alice!"},
+		{hi, "Hi, Alice. Welcome!"},
+		{ahoy, "Ahoy, Alice!"},
+	} {
+		got, err := tt.g.Hello("Alice")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Hello(Alice) = %q, want %q", got, tt.want)
+		}
+	}
+	if got := c.Stats(); got.Hits != 2 || got.Len != 2 {
+		t.Errorf("Stats() = %+v, want 2 hits and 2 entries", got)
+	}
+}
@@ -0,0 +1,180 @@
+// Package calendar makes greetings holiday-aware. A Provider says which
+// holidays fall on a date; Builtin knows a handful of fixed-date ones, and
+// callers can plug in their own, such as a company calendar or a service
+// computing movable feasts. Installed with With, the calendar replaces
+// the greeting with the holiday's on matching dates: "Happy New Year,
+// Alice!" on January 1st.
+package calendar
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"example.com/greetings"
+)
+
+// Holiday is a day with its own greeting.
+type Holiday struct {
+	// Name identifies the holiday, such as "New Year's Day".
+	Name string
+
+	// Greetings maps locales to fmt templates with a single %v for the
+	// names, such as "Happy New Year, %v!". Locales without an entry get
+	// the ordinary greeting.
+	Greetings map[string]string
+}
+
+// Greeting returns the template of h for locale, trying the language
+// alone ("pt" for "pt-BR") when the full locale has none.
+func (h Holiday) Greeting(locale string) (string, bool) {
+	for {
+		if t, ok := h.Greetings[locale]; ok {
+			return t, true
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			return "", false
+		}
+		locale = locale[:i]
+	}
+}
+
+// Provider reports the holidays that fall on the date of t, in t's own
+// location. Implementations must be safe for concurrent use.
+type Provider interface {
+	Holidays(t time.Time) []Holiday
+}
+
+// ProviderFunc adapts an ordinary function to the Provider interface.
+type ProviderFunc func(t time.Time) []Holiday
+
+// Holidays returns f(t).
+func (f ProviderFunc) Holidays(t time.Time) []Holiday {
+	return f(t)
+}
+
+// Fixed is a holiday held on the same date every year.
+type Fixed struct {
+	Month time.Month
+	Day   int
+	Holiday
+}
+
+// FixedDates is a Provider of holidays held on the same date every year.
+type FixedDates []Fixed
+
+// Holidays implements Provider.
+func (d FixedDates) Holidays(t time.Time) []Holiday {
+	var out []Holiday
+	for _, f := range d {
+		if t.Month() == f.Month && t.Day() == f.Day {
+			out = append(out, f.Holiday)
+		}
+	}
+	return out
+}
+
+// Combine returns a Provider reporting the holidays of every p, in order.
+func Combine(p ...Provider) Provider {
+	return ProviderFunc(func(t time.Time) []Holiday {
+		var out []Holiday
+		for _, q := range p {
+			out = append(out, q.Holidays(t)...)
+		}
+		return out
+	})
+}
+
+// Builtin knows fixed-date holidays with greetings in the locales of the
+// greetings package's built-in catalog.
+var Builtin Provider = FixedDates{
+	{time.January, 1, Holiday{
+		Name: "New Year's Day",
+		Greetings: map[string]string{
+			"en": "Happy New Year, %v!",
+			"es": "¡Feliz Año Nuevo, %v!",
+			"fr": "Bonne année, %v\u00a0!",
+			"de": "Frohes neues Jahr, %v!",
+			"pt": "Feliz Ano Novo, %v!",
+			"ja": "%vさん、明けましておめでとうございます！",
+		},
+	}},
+	{time.February, 14, Holiday{
+		Name: "Valentine's Day",
+		Greetings: map[string]string{
+			"en": "Happy Valentine's Day, %v!",
+			"es": "¡Feliz día de San Valentín, %v!",
+			"fr": "Joyeuse Saint-Valentin, %v\u00a0!",
+			"de": "Alles Liebe zum Valentinstag, %v!",
+			"pt": "Feliz Dia dos Namorados, %v!",
+		},
+	}},
+	{time.October, 31, Holiday{
+		Name: "Halloween",
+		Greetings: map[string]string{
+			"en": "Happy Halloween, %v!",
+			"es": "¡Feliz Halloween, %v!",
+			"de": "Fröhliches Halloween, %v!",
+		},
+	}},
+	{time.December, 25, Holiday{
+		Name: "Christmas Day",
+		Greetings: map[string]string{
+			"en": "Merry Christmas, %v!",
+			"es": "¡Feliz Navidad, %v!",
+			"fr": "Joyeux Noël, %v\u00a0!",
+			"de": "Frohe Weihnachten, %v!",
+			"pt": "Feliz Natal, %v!",
+			"ja": "%vさん、メリークリスマス！",
+		},
+	}},
+}
+
+// With makes the Greeter use p's holiday greetings on matching dates; it
+// is a shorthand for greetings.Use(Middleware(p)).
+func With(p Provider) greetings.Option {
+	return greetings.Use(Middleware(p))
+}
+
+// Middleware returns a greetings Middleware that asks next for a greeting
+// and, when p reports a holiday on the request's date with a greeting in
+// its locale, replaces the message with the first such holiday's.
+func Middleware(p Provider) greetings.Middleware {
+	return func(next greetings.Provider) greetings.Provider {
+		return greetings.ProviderFunc(func(ctx context.Context, req greetings.Request) (greetings.Greeting, error) {
+
+			greeting, err := next.Greet(ctx, req)
+			if err != nil {
+				return greeting, err
+			}
+
+			for _, h := range p.Holidays(req.Time) {
+				template, ok := h.Greeting(req.Locale)
+				if !ok {
+					continue
+				}
+				name := greeting.Name
+				if name == "" {
+					name = joinNames(req.Recipients)
+				}
+				before, _, _ := strings.Cut(template, "%v")
+				greeting.Salutation = strings.TrimRight(before, " ,、")
+				greeting.Message = fmt.Sprintf(template, name)
+				break
+			}
+
+			return greeting, nil
+		})
+	}
+}
+
+// joinNames lists the recipients' names for providers that did not.
+func joinNames(people []greetings.Person) string {
+	names := make([]string, len(people))
+	for i, p := range people {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
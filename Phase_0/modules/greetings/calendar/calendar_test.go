@@ -0,0 +1,112 @@
+package calendar_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/calendar"
+	"example.com/greetings/greetingstest"
+)
+
+func date(month time.Month, day int) time.Time {
+	return time.Date(2024, month, day, 12, 0, 0, 0, time.UTC)
+}
+
+func names(hs []calendar.Holiday) []string {
+	out := make([]string, len(hs))
+	for i, h := range hs {
+		out[i] = h.Name
+	}
+	return out
+}
+
+func TestHolidayGreetingFallsBackToLanguage(t *testing.T) {
+	h := calendar.Holiday{Greetings: map[string]string{"pt": "Feliz, %v!", "pt-PT": "Feliz em Lisboa, %v!"}}
+	for _, tt := range []struct {
+		locale, want string
+		ok           bool
+	}{
+		{"pt", "Feliz, %v!", true},
+		{"pt-BR", "Feliz, %v!", true},
+		{"pt-PT", "Feliz em Lisboa, %v!", true},
+		{"pt-PT-x-porto", "Feliz em Lisboa, %v!", true},
+		{"en", "", false},
+		{"", "", false},
+	} {
+		got, ok := h.Greeting(tt.locale)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Greeting(%q) = %q, %v, want %q, %v", tt.locale, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBuiltinHolidays(t *testing.T) {
+	for _, tt := range []struct {
+		t    time.Time
+		want []string
+	}{
+		{date(time.January, 1), []string{"New Year's Day"}},
+		{date(time.February, 14), []string{"Valentine's Day"}},
+		{date(time.October, 31), []string{"Halloween"}},
+		{date(time.December, 25), []string{"Christmas Day"}},
+		{date(time.December, 24), []string{}},
+		// The date is read in t's own location.
+		{time.Date(2024, time.December, 31, 23, 30, 0, 0, time.UTC).In(time.FixedZone("+02", 2*60*60)), []string{"New Year's Day"}},
+	} {
+		if got := names(calendar.Builtin.Holidays(tt.t)); !slices.Equal(got, tt.want) {
+			t.Errorf("Holidays(%v) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func TestCombineKeepsOrder(t *testing.T) {
+	company := calendar.FixedDates{{Month: time.January, Day: 1, Holiday: calendar.Holiday{Name: "Founders' Day"}}}
+	movable := calendar.ProviderFunc(func(t time.Time) []calendar.Holiday {
+		return []calendar.Holiday{{Name: "Every Day"}}
+	})
+	got := names(calendar.Combine(company, calendar.Builtin, movable).Holidays(date(time.January, 1)))
+	if want := []string{"Founders' Day", "New Year's Day", "Every Day"}; !slices.Equal(got, want) {
+		t.Errorf("Combine Holidays = %q, want %q", got, want)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+
+	silent := calendar.FixedDates{{Month: time.January, Day: 1, Holiday: calendar.Holiday{Name: "Quiet Day"}}}
+	for _, tt := range []struct {
+		name   string
+		p      calendar.Provider
+		t      time.Time
+		locale string
+		want   string
+		salut  string
+	}{
+		{"holiday", calendar.Builtin, date(time.January, 1), "en", "Happy New Year, Ada!", "Happy New Year"},
+		{"language fallback", calendar.Builtin, date(time.December, 25), "pt-BR", "Feliz Natal, Ada!", "Feliz Natal"},
+		{"name first", calendar.Builtin, date(time.December, 25), "ja", "Adaさん、メリークリスマス！", ""},
+		{"no greeting in locale", calendar.Builtin, date(time.October, 31), "fr", "Bonjour, Ada. Bienvenue\u00a0!", "Bonjour"},
+		{"ordinary day", calendar.Builtin, date(time.March, 3), "en", "Hi, Ada. Welcome!", "Hi"},
+		{"first with a greeting", calendar.Combine(silent, calendar.Builtin), date(time.January, 1), "en", "Happy New Year, Ada!", "Happy New Year"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := greetings.New(
+				calendar.With(tt.p),
+				greetings.WithClock(greetingstest.NewFakeClock(tt.t)),
+				greetings.WithLocale(tt.locale),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := g.GreetCtx(context.Background(), greetings.Person{Name: "Ada"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Message != tt.want || got.Salutation != tt.salut {
+				t.Errorf("Message, Salutation = %q, %q, want %q, %q", got.Message, got.Salutation, tt.want, tt.salut)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package greetings
+
+import (
+	"embed"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// Message is a catalog entry: a fmt format with one %v verb for the name
+// and the punctuation that ends it. Template and Punctuation are the
+// neutral register; Casual and Formal hold the other registers.
+type Message struct {
+	Template    string
+	Punctuation string
+
+	// Casual and Formal are the message in those registers (see
+	// Formality). A zero Variant falls back to the neutral message.
+	Casual Variant
+	Formal Variant
+
+	// TitleFormat places an honorific relative to the name, with %[1]s for
+	// the title and %[2]s for the name. Empty means "%[1]s %[2]s", the
+	// title-first order most European languages use.
+	TitleFormat string
+
+	// Separator and Conjunction join the names of a group greeting, as in
+	// "Alice, Bob and Carol". Empty means ", " and " and ".
+	Separator   string
+	Conjunction string
+
+	// Emoji decorates the greeting when emoji are enabled. Empty means 👋.
+	Emoji string
+
+	// Birthday is the complete neutral-register message, with one %v verb
+	// for the name, for recipients whose birthday it is. Greeters with a
+	// template, punctuation or register of their own keep to it, and
+	// emoji are added as to other greetings. Empty means no birthday
+	// greeting.
+	Birthday string
+
+	// WelcomeBack is the complete neutral-register message, with one %v
+	// verb for the name, for recipients who were greeted before (see
+	// Request.LastSeen), as history middleware tells. Like Birthday it
+	// gives way to the Greeter's own template, punctuation or register.
+	// Empty means they get the ordinary greeting.
+	WelcomeBack string
+
+	// Group is the complete neutral-register message for greeting several
+	// people, in ICU MessageFormat (see MessageFormat) with the arguments
+	// {names}, the joined names, and {count}, so languages whose grammar
+	// changes with the number of people can say so. Empty means Template.
+	Group string
+}
+
+// Variant is a message in one register: a fmt format with one %v verb and
+// its closing punctuation.
+type Variant struct {
+	Template    string
+	Punctuation string
+}
+
+// Catalog maps locales to their messages. Every catalog needs an entry for
+// English, which is what unknown locales fall back to.
+type Catalog map[string]Message
+
+// embedded holds the built-in catalog and themes, so the package needs no
+// files at run time.
+//
+//go:embed data
+var embedded embed.FS
+
+// builtin holds the greeting for each supported locale in all registers.
+var builtin = func() Catalog {
+	c, err := LoadCatalogFS(embedded, "data/catalog.yaml")
+	if err != nil {
+		panic(err)
+	}
+	return c
+}()
+
+// Builtin returns a copy of the built-in catalog, to extend or to pass to
+// Catalog.Overlay.
+func Builtin() Catalog {
+	return maps.Clone(builtin)
+}
+
+// Overlay returns a catalog holding the entries of c and over, where over
+// shadows c locale by locale: an entry in over replaces c's for the same
+// locale as a whole. Neither catalog is modified.
+func (c Catalog) Overlay(over Catalog) Catalog {
+	merged := maps.Clone(c)
+	if merged == nil {
+		merged = make(Catalog, len(over))
+	}
+	maps.Copy(merged, over)
+	return merged
+}
+
+// variant returns the message in register f, falling back to the neutral
+// message when m has nothing for f.
+func (m Message) variant(f Formality) Variant {
+	switch {
+	case f == Casual && m.Casual.Template != "":
+		return m.Casual
+	case f == Formal && m.Formal.Template != "":
+		return m.Formal
+	default:
+		return Variant{m.Template, m.Punctuation}
+	}
+}
+
+// Locales returns the locales of the built-in catalog in sorted order.
+func Locales() []string {
+	return builtin.Locales()
+}
+
+// Locales returns the locales of c in sorted order.
+func (c Catalog) Locales() []string {
+	return slices.Sorted(maps.Keys(c))
+}
+
+// Lookup returns the entry for locale; see Resolve.
+func (c Catalog) Lookup(locale string) (Message, error) {
+	_, msg, err := c.Resolve(locale)
+	return msg, err
+}
+
+// Resolve finds the entry that best serves locale by walking its BCP 47
+// fallback chain, so a request for "pt-BR" is answered from "pt" when c
+// has no "pt-BR" entry, and "es-MX" tries "es-419" before "es". It
+// returns the locale actually used alongside its entry. When nothing in
+// the chain matches, Resolve returns the English entry together with a
+// *LocaleError, which wraps ErrUnknownLocale and may suggest the locale
+// that was meant.
+func (c Catalog) Resolve(locale string) (string, Message, error) {
+
+	for _, candidate := range fallbackChain(locale) {
+		if msg, ok := c[candidate]; ok {
+			return candidate, msg, nil
+		}
+	}
+
+	return defaultLocale, c[defaultLocale], &LocaleError{Locale: locale, Suggestion: c.suggestLocale(locale)}
+}
+
+// fallbackChain returns locale followed by its BCP 47 parents, most
+// specific first: "pt-BR", "pt". Catalog keys are expected in canonical
+// form, so the canonical spelling of locale is tried too.
+func fallbackChain(locale string) []string {
+
+	chain := []string{locale}
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return chain
+	}
+	for ; !tag.IsRoot(); tag = tag.Parent() {
+		if s := tag.String(); !slices.Contains(chain, s) {
+			chain = append(chain, s)
+		}
+	}
+
+	return chain
+}
+
+// Locale is a BCP 47 language tag such as "pt-BR", for flags, config
+// files and map keys. Its text form is the canonical spelling of the tag,
+// and unmarshaling fails for text that is not a well-formed tag, so a
+// Locale that decoded cleanly is safe to pass to WithLocale. Whether a
+// catalog has an entry for it is only known once a Greeter is built.
+type Locale string
+
+// String returns l as a string.
+func (l Locale) String() string {
+	return string(l)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (l Locale) MarshalText() ([]byte, error) {
+	tag, err := language.Parse(string(l))
+	if err != nil {
+		return nil, fmt.Errorf("greetings: invalid locale %q: %w", string(l), err)
+	}
+	return []byte(tag.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, storing the tag in
+// canonical form: "pt-br" becomes "pt-BR".
+func (l *Locale) UnmarshalText(text []byte) error {
+	tag, err := language.Parse(string(text))
+	if err != nil {
+		return fmt.Errorf("greetings: invalid locale %q: %w", text, err)
+	}
+	*l = Locale(tag.String())
+	return nil
+}
+
+// ResolveLocale reports which built-in locale serves locale; see
+// Catalog.Resolve.
+func ResolveLocale(locale string) (string, error) {
+	resolved, _, err := builtin.Resolve(locale)
+	return resolved, err
+}
+
+// Validate checks that c has an English entry and that every template is a
+// well-formed format.
+func (c Catalog) Validate() error {
+	if _, ok := c[defaultLocale]; !ok {
+		return fmt.Errorf("greetings: catalog has no %q entry to fall back to", defaultLocale)
+	}
+	for _, locale := range c.Locales() {
+		msg := c[locale]
+		if err := checkFormat(msg.Template); err != nil {
+			return fmt.Errorf("greetings: catalog entry %q: %w", locale, err)
+		}
+		for _, f := range []Formality{Casual, Formal} {
+			v := msg.variant(f)
+			if err := checkFormat(v.Template); err != nil {
+				return fmt.Errorf("greetings: catalog entry %q (%v): %w", locale, f, err)
+			}
+		}
+		if msg.Birthday != "" {
+			if err := checkFormat(msg.Birthday); err != nil {
+				return fmt.Errorf("greetings: catalog entry %q (birthday): %w", locale, err)
+			}
+		}
+		if msg.WelcomeBack != "" {
+			if err := checkFormat(msg.WelcomeBack); err != nil {
+				return fmt.Errorf("greetings: catalog entry %q (welcome back): %w", locale, err)
+			}
+		}
+		if msg.Group != "" {
+			if _, err := ParseMessageFormat(locale, msg.Group); err != nil {
+				return fmt.Errorf("greetings: catalog entry %q (group): %w", locale, err)
+			}
+		}
+		if msg.TitleFormat != "" && strings.Count(msg.TitleFormat, "%[") != 2 {
+			return fmt.Errorf("greetings: catalog entry %q: title format %q must use %%[1]s and %%[2]s", locale, msg.TitleFormat)
+		}
+	}
+	return nil
+}
+
+// withTitle returns name with the honorific title placed the way the
+// message's language expects. An empty title leaves name unchanged.
+func (m Message) withTitle(title, name string) string {
+	if title == "" {
+		return name
+	}
+	format := m.TitleFormat
+	if format == "" {
+		format = "%[1]s %[2]s"
+	}
+	return fmt.Sprintf(format, title, name)
+}
+
+// checkFormat reports whether template has exactly one %v verb and no other.
+func checkFormat(template string) error {
+	verbs := strings.ReplaceAll(template, "%%", "")
+	if strings.Count(verbs, "%v") != 1 || strings.Count(verbs, "%") != 1 {
+		return fmt.Errorf("template %q must contain exactly one %%v verb", template)
+	}
+	return nil
+}
+
+// HelloLocale returns a greeting for the named person in the given locale.
+// An unknown locale still yields the English greeting, alongside an error
+// wrapping ErrUnknownLocale so the caller can log the miss.
+func HelloLocale(name, locale string) (string, error) {
+
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+
+	msg, err := builtin.Lookup(locale)
+	return fmt.Sprintf(msg.Template, name) + msg.Punctuation, err
+}
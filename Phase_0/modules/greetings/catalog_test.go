@@ -0,0 +1,84 @@
+package greetings_test
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/greetings"
+	"example.com/greetings/greetingstest"
+)
+
+func TestCatalogGolden(t *testing.T) {
+	greetingstest.Golden(t, "testdata", nil, "Gladys", "Ada", "Linus")
+}
+
+func TestResolve(t *testing.T) {
+	c := greetings.Catalog{
+		"en":      {Template: "Hi, %v", Punctuation: "!"},
+		"pt":      {Template: "Olá, %v", Punctuation: "!"},
+		"es":      {Template: "Hola, %v", Punctuation: "!"},
+		"es-419":  {Template: "¡Hola, %v", Punctuation: "!"},
+		"zh-Hant": {Template: "你好，%v", Punctuation: "！"},
+	}
+	for _, tt := range []struct {
+		locale, want string
+		unknown      bool
+		suggestion   string
+	}{
+		{locale: "pt", want: "pt"},
+		{locale: "pt-BR", want: "pt"},
+		{locale: "pt-br", want: "pt"},
+		{locale: "en-GB", want: "en"},
+		{locale: "es-ES", want: "es"},
+		{locale: "es-MX", want: "es-419"},
+		{locale: "zh-Hant-TW", want: "zh-Hant"},
+		{locale: "fr-CA", want: "en", unknown: true},
+		{locale: "ptt", want: "en", unknown: true, suggestion: "pt"},
+		{locale: "px", want: "en", unknown: true},
+		{locale: "enn-US", want: "en", unknown: true, suggestion: "en-US"},
+		{locale: "engl", want: "en", unknown: true},
+		{locale: "not a tag", want: "en", unknown: true},
+	} {
+		got, msg, err := c.Resolve(tt.locale)
+		if got != tt.want || msg != c[tt.want] {
+			t.Errorf("Resolve(%q) = %q, %+v, want %q", tt.locale, got, msg, tt.want)
+		}
+		var le *greetings.LocaleError
+		switch {
+		case !tt.unknown && err != nil:
+			t.Errorf("Resolve(%q) error = %v, want nil", tt.locale, err)
+		case tt.unknown && (!errors.Is(err, greetings.ErrUnknownLocale) || !errors.As(err, &le)):
+			t.Errorf("Resolve(%q) error = %v, want a *LocaleError", tt.locale, err)
+		case tt.unknown && le.Suggestion != tt.suggestion:
+			t.Errorf("Resolve(%q) suggests %q, want %q", tt.locale, le.Suggestion, tt.suggestion)
+		}
+	}
+}
+
+func TestGreetingLocale(t *testing.T) {
+	for _, tt := range []struct {
+		opts []greetings.Option
+		want string
+	}{
+		{[]greetings.Option{greetings.WithLocale("pt-BR")}, "pt"},
+		{[]greetings.Option{greetings.WithLocale("de-AT")}, "de"},
+		{[]greetings.Option{greetings.WithLocale("xx"), greetings.WithMode(greetings.Lenient)}, "en"},
+	} {
+		g, err := greetings.New(tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		greeting, err := g.Greet("Ana")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if greeting.Locale != tt.want || g.Locale() != tt.want {
+			t.Errorf("Greeting.Locale = %q, Locale() = %q, want %q", greeting.Locale, g.Locale(), tt.want)
+		}
+	}
+
+	_, err := greetings.New(greetings.WithLocale("xx"))
+	if !errors.Is(err, greetings.ErrUnknownLocale) || greetings.CodeOf(err) != greetings.UnknownLocale {
+		t.Errorf("New(WithLocale(xx)) error = %v, want an UnknownLocale error", err)
+	}
+}
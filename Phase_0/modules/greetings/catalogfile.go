@@ -0,0 +1,290 @@
+package greetings
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+// A catalog file is YAML, or JSON (which YAML parsers accept as is), with
+// one entry per locale under "locales":
+//
+//	locales:
+//	  en:
+//	    template: "Hi, %v. Welcome"
+//	    punctuation: "!"
+//	    casual: {template: "Hey %v", punctuation: "!"}
+//	    formal: {template: "Dear %v, welcome", punctuation: "."}
+//	  fr:
+//	    template: "Bonjour, %v. Bienvenue"
+//	    punctuation: " !"
+//	    conjunction: " et "
+//
+// Entry keys mirror the fields of Message in snake_case: template,
+// punctuation, casual, formal, title_format, separator, conjunction,
+// emoji, birthday, welcome_back and group. Only template is required.
+
+// CatalogError describes a problem at one place in a catalog file.
+type CatalogError struct {
+	File   string // name of the file, as passed to the loader
+	Line   int    // 1-based line, 0 when unknown
+	Column int    // 1-based column, 0 when unknown
+	Field  string // dotted path of the offending field, like "locales.fr.template"
+	Err    error
+}
+
+func (e *CatalogError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.File)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, ":%d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, ":%d", e.Column)
+		}
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&b, ": %s", e.Field)
+	}
+	fmt.Fprintf(&b, ": %v", e.Err)
+	return b.String()
+}
+
+func (e *CatalogError) Unwrap() error {
+	return e.Err
+}
+
+// LoadCatalog reads and validates the catalog file at path. Every problem
+// found is reported, each as a *CatalogError giving its line and field,
+// joined into one error.
+func LoadCatalog(path string) (Catalog, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+
+	return ParseCatalog(filepath.Base(path), data)
+}
+
+// LoadCatalogFS is like LoadCatalog but reads the file from fsys, which
+// suits catalogs embedded with go:embed or shipped in an archive.
+func LoadCatalogFS(fsys fs.FS, path string) (Catalog, error) {
+
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+
+	return ParseCatalog(path, data)
+}
+
+// ParseCatalog parses and validates catalog file contents; name is used in
+// error messages.
+func ParseCatalog(name string, data []byte) (Catalog, error) {
+	c, err := parseCatalog(name, data, nil)
+	return c, coded(InvalidConfig, err)
+}
+
+// LoadCatalogOverlay reads a catalog file that shadows the built-in
+// catalog (see Catalog.Overlay) and returns the combined catalog, for
+// WithCatalog. Unlike a file for LoadCatalog, it needs only the locales
+// it changes or adds, not English.
+func LoadCatalogOverlay(path string) (Catalog, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+	c, err := parseCatalog(filepath.Base(path), data, builtin)
+
+	return c, coded(InvalidConfig, err)
+}
+
+// parseCatalog parses catalog file contents and validates them laid over
+// base, which may be nil.
+func parseCatalog(name string, data []byte, base Catalog) (Catalog, error) {
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, &CatalogError{File: name, Err: err}
+	}
+	if len(doc.Content) == 0 {
+		return nil, &CatalogError{File: name, Err: errors.New("empty catalog")}
+	}
+
+	p := &catalogParser{file: name}
+	c := p.catalog(doc.Content[0])
+	if len(p.errs) > 0 {
+		slices.SortStableFunc(p.errs, func(a, b error) int {
+			ea, eb := a.(*CatalogError), b.(*CatalogError)
+			return cmp.Or(cmp.Compare(ea.Line, eb.Line), cmp.Compare(ea.Column, eb.Column))
+		})
+		return nil, errors.Join(p.errs...)
+	}
+	if base != nil {
+		c = base.Overlay(c)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, &CatalogError{File: name, Err: err}
+	}
+
+	return c, nil
+}
+
+// catalogParser walks a catalog document, collecting every error it finds.
+type catalogParser struct {
+	file string
+	errs []error
+}
+
+func (p *catalogParser) fail(n *yaml.Node, field, format string, args ...any) {
+	p.errs = append(p.errs, &CatalogError{
+		File:   p.file,
+		Line:   n.Line,
+		Column: n.Column,
+		Field:  field,
+		Err:    fmt.Errorf(format, args...),
+	})
+}
+
+// mapping calls fn for every key and value of the mapping node n, or
+// reports that n is not a mapping.
+func (p *catalogParser) mapping(n *yaml.Node, field string, fn func(key string, value *yaml.Node)) {
+	if n.Kind != yaml.MappingNode {
+		p.fail(n, field, "expected a mapping")
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		fn(n.Content[i].Value, n.Content[i+1])
+	}
+}
+
+// str returns the string value of n, or reports that n is not a string.
+func (p *catalogParser) str(n *yaml.Node, field string) string {
+	if n.Kind != yaml.ScalarNode || n.Tag != "!!str" {
+		p.fail(n, field, "expected a string")
+		return ""
+	}
+	return n.Value
+}
+
+func (p *catalogParser) catalog(root *yaml.Node) Catalog {
+
+	c := make(Catalog)
+	var locales *yaml.Node
+	p.mapping(root, "", func(key string, value *yaml.Node) {
+		if key != "locales" {
+			p.fail(value, key, "unknown field")
+			return
+		}
+		locales = value
+	})
+	if locales == nil {
+		if root.Kind == yaml.MappingNode {
+			p.fail(root, "locales", "missing field")
+		}
+		return c
+	}
+
+	p.mapping(locales, "locales", func(locale string, value *yaml.Node) {
+		field := "locales." + locale
+		if _, err := language.Parse(locale); err != nil {
+			p.fail(value, field, "invalid locale: %v", err)
+		}
+		if _, dup := c[locale]; dup {
+			p.fail(value, field, "duplicate locale")
+		}
+		c[locale] = p.message(value, field)
+	})
+
+	return c
+}
+
+func (p *catalogParser) message(n *yaml.Node, field string) Message {
+
+	var msg Message
+	p.mapping(n, field, func(key string, value *yaml.Node) {
+		sub := subfield(field, key)
+		switch key {
+		case "template":
+			msg.Template = p.template(value, sub)
+		case "punctuation":
+			msg.Punctuation = p.str(value, sub)
+		case "casual":
+			msg.Casual = p.variant(value, sub)
+		case "formal":
+			msg.Formal = p.variant(value, sub)
+		case "title_format":
+			msg.TitleFormat = p.str(value, sub)
+		case "separator":
+			msg.Separator = p.str(value, sub)
+		case "conjunction":
+			msg.Conjunction = p.str(value, sub)
+		case "emoji":
+			msg.Emoji = p.str(value, sub)
+		case "birthday":
+			msg.Birthday = p.template(value, sub)
+		case "welcome_back":
+			msg.WelcomeBack = p.template(value, sub)
+		case "group":
+			msg.Group = p.str(value, sub)
+		default:
+			p.fail(value, sub, "unknown field")
+		}
+	})
+	if n.Kind == yaml.MappingNode && msg.Template == "" {
+		p.fail(n, subfield(field, "template"), "missing field")
+	}
+
+	return msg
+}
+
+func (p *catalogParser) variant(n *yaml.Node, field string) Variant {
+
+	var v Variant
+	p.mapping(n, field, func(key string, value *yaml.Node) {
+		sub := field + "." + key
+		switch key {
+		case "template":
+			v.Template = p.template(value, sub)
+		case "punctuation":
+			v.Punctuation = p.str(value, sub)
+		default:
+			p.fail(value, sub, "unknown field")
+		}
+	})
+	if n.Kind == yaml.MappingNode && v.Template == "" {
+		p.fail(n, field+".template", "missing field")
+	}
+
+	return v
+}
+
+// subfield returns the dotted path of key within field, which may be the
+// document root "".
+func subfield(field, key string) string {
+	if field == "" {
+		return key
+	}
+	return field + "." + key
+}
+
+// template returns the fmt template in n, reporting it if malformed.
+func (p *catalogParser) template(n *yaml.Node, field string) string {
+	s := p.str(n, field)
+	if s == "" {
+		return ""
+	}
+	if err := checkFormat(s); err != nil {
+		p.fail(n, field, "%v", err)
+	}
+	return s
+}
@@ -0,0 +1,84 @@
+// Greet-bench checks the output of the greetings benchmarks for
+// performance regressions.
+//
+// Usage:
+//
+//	go test -run '^$' -bench . -benchmem example.com/greetings | greet-bench [-baseline file] [-check]
+//
+// It reads "go test -bench" output from standard input and echoes the
+// benchmark lines: Greeter.Hello rendering catalog messages and
+// text/template greetings against fmt.Sprintf and the writer-based
+// Greeter.HelloTo, and sequential Greeter.Hellos against the worker-pool
+// mode at several pool sizes.
+//
+// Save the output of one run to compare a later one against it with
+// -baseline, which prints the two side by side and fails when any
+// benchmark allocates more than it used to. -check fails when a benchmark
+// breaks its allocation budget, such as HelloTo allocating at all.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"example.com/greetings/bench"
+)
+
+func main() {
+	log.SetPrefix("greet-bench: ")
+	log.SetFlags(0)
+
+	baseline := flag.String("baseline", "", "compare against the results saved in `file`")
+	check := flag.Bool("check", false, "fail when a benchmark exceeds its allocation budget")
+	flag.Parse()
+
+	var base []bench.Result
+	if *baseline != "" {
+		f, err := os.Open(*baseline)
+		if err != nil {
+			log.Fatal(err)
+		}
+		base, err = bench.ParseResults(f)
+		f.Close()
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	results, err := bench.ParseResults(os.Stdin)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(results) == 0 {
+		log.Fatal("no benchmark results on standard input")
+	}
+	if err := bench.WriteResults(os.Stdout, results); err != nil {
+		log.Fatal(err)
+	}
+
+	failed := false
+	if *baseline != "" {
+		fmt.Println()
+		changes := bench.Compare(base, results)
+		if err := bench.WriteChanges(os.Stdout, changes); err != nil {
+			log.Fatal(err)
+		}
+		for _, c := range changes {
+			if c.Regressed() {
+				log.Printf("%s: %d allocs/op, was %d", c.Name, c.New.AllocsPerOp, c.Old.AllocsPerOp)
+				failed = true
+			}
+		}
+	}
+	if *check {
+		if err := bench.Check(results, bench.Budgets); err != nil {
+			log.Print(err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,171 @@
+// Greet-preview renders the greetings of a catalog so translators can
+// review them, and compares two versions of a catalog side by side.
+//
+// Usage:
+//
+//	greet-preview [-name name] [-overlay] [-changed] catalog [new-catalog]
+//
+// With one catalog it prints every greeting it produces for name: each
+// locale in the casual, neutral and formal registers, its birthday and
+// group greetings, and the neutral greeting in every registered style.
+// With two it prints the greetings of both next to each other, marking
+// the rows that differ with "*"; -changed leaves out the rest.
+//
+// A catalog is a YAML or JSON catalog file, or "builtin" for the built-in
+// catalog. With -overlay files only need the locales they change, which
+// are laid over the built-in catalog (see greetings.LoadCatalogOverlay).
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"example.com/greetings"
+)
+
+// previewTime is the moment every greeting is rendered at, so previews of
+// the same catalog are identical from run to run.
+var previewTime = time.Date(2025, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("greet-preview", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	name := flags.String("name", "Gladys", "sample `name` to greet")
+	overlay := flags.Bool("overlay", false, "lay catalog files over the built-in catalog")
+	changed := flags.Bool("changed", false, "with two catalogs, only show greetings that differ")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		fmt.Fprintln(stderr, "usage: greet-preview [-name name] [-overlay] [-changed] catalog [new-catalog]")
+		return 2
+	}
+
+	catalogs := make([]greetings.Catalog, flags.NArg())
+	for i, path := range flags.Args() {
+		c, err := load(path, *overlay)
+		if err != nil {
+			fmt.Fprintln(stderr, "greet-preview:", err)
+			return 2
+		}
+		catalogs[i] = c
+	}
+
+	var locales []string
+	for _, c := range catalogs {
+		locales = append(locales, c.Locales()...)
+	}
+	slices.Sort(locales)
+	locales = slices.Compact(locales)
+
+	w := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+	if len(catalogs) == 1 {
+		fmt.Fprintln(w, "LOCALE\tGREETING\tMESSAGE")
+	} else {
+		fmt.Fprintln(w, "\tLOCALE\tGREETING\tOLD\tNEW")
+	}
+	for _, locale := range locales {
+		for _, s := range samples() {
+			if len(catalogs) == 1 {
+				fmt.Fprintf(w, "%s\t%s\t%s\n", locale, s.label, s.render(catalogs[0], locale, *name))
+				continue
+			}
+			old, new := s.render(catalogs[0], locale, *name), s.render(catalogs[1], locale, *name)
+			mark := " "
+			if old != new {
+				mark = "*"
+			} else if *changed {
+				continue
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", mark, locale, s.label, old, new)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintln(stderr, "greet-preview:", err)
+		return 1
+	}
+
+	return 0
+}
+
+// load reads the catalog at path, or returns the built-in one for
+// "builtin".
+func load(path string, overlay bool) (greetings.Catalog, error) {
+	switch {
+	case path == "builtin":
+		return greetings.Builtin(), nil
+	case overlay:
+		return greetings.LoadCatalogOverlay(path)
+	default:
+		return greetings.LoadCatalog(path)
+	}
+}
+
+// sample is one greeting of a locale to preview.
+type sample struct {
+	label string
+	opts  []greetings.Option
+	greet func(g *greetings.Greeter, name string) (string, error)
+}
+
+// samples returns the greetings previewed for every locale.
+func samples() []sample {
+
+	hello := func(g *greetings.Greeter, name string) (string, error) {
+		return g.Hello(name)
+	}
+	var s []sample
+	for _, f := range []greetings.Formality{greetings.Casual, greetings.Neutral, greetings.Formal} {
+		s = append(s, sample{label: f.String(), opts: []greetings.Option{greetings.WithFormality(f)}, greet: hello})
+	}
+	s = append(s,
+		sample{label: "birthday", greet: func(g *greetings.Greeter, name string) (string, error) {
+			greeting, err := g.GreetPerson(greetings.Person{Name: name, Birthday: previewTime})
+			return greeting.Message, err
+		}},
+		sample{label: "group", greet: func(g *greetings.Greeter, name string) (string, error) {
+			return g.HelloGroup([]string{name, "Bob", "Carol"})
+		}},
+	)
+	for _, style := range greetings.Styles() {
+		s = append(s, sample{label: "style " + style, opts: []greetings.Option{greetings.WithStyle(style)}, greet: hello})
+	}
+
+	return s
+}
+
+// render greets name with catalog c in locale, returning the message or a
+// note saying why there is none.
+func (s sample) render(c greetings.Catalog, locale, name string) string {
+
+	if _, ok := c[locale]; !ok {
+		return "(missing)"
+	}
+	opts := append([]greetings.Option{
+		greetings.WithCatalog(c),
+		greetings.WithLocale(locale),
+		greetings.WithClock(greetings.ClockFunc(func() time.Time { return previewTime })),
+	}, s.opts...)
+	g, err := greetings.New(opts...)
+	if err != nil {
+		return "(error: " + err.Error() + ")"
+	}
+	message, err := s.greet(g, name)
+	if err != nil {
+		return "(error: " + err.Error() + ")"
+	}
+
+	return strings.ReplaceAll(message, "\t", " ")
+}
@@ -0,0 +1,81 @@
+// Greet-server serves greetings over HTTP until interrupted.
+//
+// Usage:
+//
+//	greet-server [-addr host:port] [-push-every duration]
+//
+// It answers GET /greet?name=Alice&locale=fr with a JSON greeting, serves
+// the REST API under /v1/ (see /v1/openapi.yaml), keeps WebSocket clients
+// of /push?name=Alice&locale=fr connected to push them greetings, every
+// -push-every if set, and shuts down gracefully on SIGINT or SIGTERM.
+// /healthz and /readyz serve liveness and readiness probes.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/httpserver"
+)
+
+func main() {
+	log.SetPrefix("greet-server: ")
+	log.SetFlags(0)
+
+	addr := flag.String("addr", "localhost:8080", "listen `address`")
+	every := flag.Duration("push-every", 0, "push a greeting to every WebSocket client at this `interval`")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.Handle("/greet", httpserver.NewHandler())
+	mux.Handle("/v1/", httpserver.NewAPI())
+	g, err := greetings.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	health := &httpserver.Health{}
+	health.Add("catalog", httpserver.CatalogCheck(greetings.Builtin))
+	health.Add("provider", httpserver.ProviderCheck(g))
+	mux.HandleFunc("/healthz", health.Healthz)
+	mux.HandleFunc("/readyz", health.Readyz)
+	hub := httpserver.NewHub()
+	mux.Handle("/push", hub)
+	go func() {
+		<-ctx.Done()
+		hub.Close()
+	}()
+	if *every > 0 {
+		go pushEvery(ctx, hub, *every)
+	}
+
+	log.Printf("listening on %s", *addr)
+	if err := httpserver.Serve(ctx, *addr, mux); err != nil {
+		log.Fatal(err)
+	}
+	log.Print("stopped")
+}
+
+// pushEvery greets every client of hub at each tick until ctx is done.
+func pushEvery(ctx context.Context, hub *httpserver.Hub, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := hub.GreetAll(ctx); err != nil {
+				log.Print(err)
+			}
+		}
+	}
+}
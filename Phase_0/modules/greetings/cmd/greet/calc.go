@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"example.com/calculator"
+)
+
+// calc runs the calc subcommand: it evaluates the expression made of args,
+// or each line of stdin when there are no args, and prints the results.
+func calc(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+
+	eval := func(expr string) error {
+		v, err := calculator.Eval(expr)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(stdout, strconv.FormatFloat(v, 'g', -1, 64))
+		return err
+	}
+
+	if len(args) > 0 {
+		if err := eval(strings.Join(args, " ")); err != nil {
+			fmt.Fprintln(stderr, "greet calc:", err)
+			return 1
+		}
+		return 0
+	}
+
+	status := 0
+	scanner := bufio.NewScanner(stdin)
+	for line := 1; scanner.Scan(); line++ {
+		expr := strings.TrimSpace(scanner.Text())
+		if expr == "" {
+			continue
+		}
+		if err := eval(expr); err != nil {
+			fmt.Fprintf(stderr, "greet calc: line %d: %v\n", line, err)
+			status = 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(stderr, "greet calc: reading stdin:", err)
+		return 1
+	}
+
+	return status
+}
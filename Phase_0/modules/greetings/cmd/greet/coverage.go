@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"example.com/greetings"
+)
+
+// coverage runs the coverage subcommand: it prints how much of the
+// English catalog entry every other locale translates, as a table or as
+// JSON. It exits with status 1 under -check when a locale is incomplete.
+func coverage(args []string, stdout, stderr io.Writer) int {
+
+	flags := flag.NewFlagSet("greet coverage", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	catalog := flags.String("catalog", "", "report on the YAML or JSON catalog `file` instead of the built-in one")
+	format := flags.String("format", "table", "output `format`: table or json")
+	check := flags.Bool("check", false, "exit with status 1 if any locale is incomplete")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "greet coverage: unexpected arguments: %v\n", flags.Args())
+		return 2
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "greet coverage: unknown format %q\n", *format)
+		return 2
+	}
+
+	report := greetings.CoverageReport()
+	if *catalog != "" {
+		c, err := greetings.LoadCatalog(*catalog)
+		if err != nil {
+			fmt.Fprintln(stderr, "greet coverage:", err)
+			return 2
+		}
+		report = c.CoverageReport()
+	}
+
+	var err error
+	if *format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		w := tabwriter.NewWriter(stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(w, "LOCALE\tCOVERAGE\tMISSING")
+		for _, c := range report {
+			missing := strings.Join(c.Missing, ", ")
+			if missing == "" {
+				missing = "-"
+			}
+			fmt.Fprintf(w, "%s\t%d/%d (%.0f%%)\t%s\n", c.Locale, c.Translated, c.Total, c.Percent(), missing)
+		}
+		err = w.Flush()
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "greet coverage:", err)
+		return 1
+	}
+
+	if *check {
+		for _, c := range report {
+			if !c.Complete() {
+				return 1
+			}
+		}
+	}
+
+	return 0
+}
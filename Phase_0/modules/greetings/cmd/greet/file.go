@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"example.com/greetings"
+	"example.com/nameio"
+)
+
+// greetFile greets the names in the file at path as they are read,
+// calling emit for each greeting, and returns the exit status.
+func greetFile(greeter *greetings.Greeter, path string, emit func(greetings.Greeting) error, stderr io.Writer) int {
+
+	r, err := nameio.Open(path)
+	if err != nil {
+		fmt.Fprintln(stderr, "greet:", err)
+		return 1
+	}
+	defer r.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	status := 0
+	for res := range greeter.HelloStream(ctx, r.Stream(ctx)) {
+		if res.Err != nil {
+			fmt.Fprintf(stderr, "greet: %s: %q: %v\n", path, res.Name, res.Err)
+			status = 1
+			continue
+		}
+		if err := emit(res.Greeting); err != nil {
+			fmt.Fprintln(stderr, "greet:", err)
+			return 1
+		}
+	}
+	if err := r.Err(); err != nil {
+		fmt.Fprintf(stderr, "greet: %s: %v\n", path, err)
+		return 1
+	}
+
+	return status
+}
@@ -0,0 +1,169 @@
+// Greet prints greetings from the greetings package, for use in scripts.
+//
+// Usage:
+//
+//	greet [-name name] [-locale locale] [-formality f] [-style style]
+//	      [-catalog file] [-format text|json|banner] [-file names] [-i]
+//	greet calc [expression]
+//	greet coverage [-catalog file] [-format table|json] [-check]
+//
+// With -name it greets that one person. Otherwise it reads names from
+// standard input, one per line, and prints a greeting for each. Blank lines
+// are skipped; names that cannot be greeted are reported on standard error
+// and make greet exit with status 1.
+//
+// With -file it greets the names in a file instead of standard input,
+// reading it as it goes so files of any size work. Files ending in .csv
+// are CSV, with names in the "name" column or else the first one; .jsonl
+// files hold a JSON string or {"name": ...} object per line; anything
+// else has a name per line. UTF-8, UTF-16 with a byte order mark and
+// Latin-1 are all understood.
+//
+// Text output is colored when standard output is a terminal, unless the
+// NO_COLOR environment variable is set. Banner output prints greetings in
+// large letters, as wide as COLUMNS says the terminal is.
+//
+// With -i it starts an interactive playground instead: type names to greet
+// them and slash commands such as "/locale es" or "/style pirate" to change
+// the settings, which start from the flags. "/help" lists the commands.
+//
+// "greet calc" evaluates arithmetic such as "2 * (3 + 4)" with the
+// calculator package instead, from its arguments or else from standard
+// input, one expression per line.
+//
+// "greet coverage" reports which registers and messages of the English
+// catalog entry each other locale lacks, as a table or as JSON, for
+// keeping track of translations. With -check it exits with status 1 when
+// any locale is incomplete.
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"example.com/greetings"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command and returns its exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+
+	if len(args) > 0 && args[0] == "calc" {
+		return calc(args[1:], stdin, stdout, stderr)
+	}
+	if len(args) > 0 && args[0] == "coverage" {
+		return coverage(args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("greet", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	name := flags.String("name", "", "greet this `name` instead of reading names from stdin")
+	locale := flags.String("locale", "en", "greeting `locale`: "+strings.Join(greetings.Locales(), ", "))
+	formality := flags.String("formality", "neutral", "greeting `register`: casual, neutral or formal")
+	style := flags.String("style", "", "greeting `style`: "+strings.Join(greetings.Styles(), ", "))
+	catalog := flags.String("catalog", "", "load greetings from the YAML or JSON catalog `file`")
+	file := flags.String("file", "", "greet the names in `file` (text, .csv or .jsonl) instead of stdin")
+	format := flags.String("format", "text", "output `format`: text, json or banner")
+	interactive := flags.Bool("i", false, "start an interactive prompt")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "greet: unexpected arguments: %v\n", flags.Args())
+		return 2
+	}
+	if *name != "" && *file != "" {
+		fmt.Fprintln(stderr, "greet: -name and -file are mutually exclusive")
+		return 2
+	}
+	if *format != "text" && *format != "json" && *format != "banner" {
+		fmt.Fprintf(stderr, "greet: unknown format %q\n", *format)
+		return 2
+	}
+
+	register, err := greetings.ParseFormality(*formality)
+	if err != nil {
+		fmt.Fprintln(stderr, "greet:", err)
+		return 2
+	}
+	s := settings{locale: *locale, formality: register, style: *style, json: *format == "json", banner: *format == "banner"}
+	if *catalog != "" {
+		c, err := greetings.LoadCatalog(*catalog)
+		if err != nil {
+			fmt.Fprintln(stderr, "greet:", err)
+			return 2
+		}
+		s.catalog = c
+	}
+	if *interactive {
+		if err := repl(s, stdin, stdout); err != nil {
+			fmt.Fprintln(stderr, "greet:", err)
+			return 1
+		}
+		return 0
+	}
+	greeter, err := s.greeter()
+	if err != nil {
+		fmt.Fprintln(stderr, "greet:", err)
+		return 2
+	}
+
+	out := bufio.NewWriter(stdout)
+	defer out.Flush()
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+
+	emit := func(greeting greetings.Greeting) error {
+		if *format == "json" {
+			return enc.Encode(greeting)
+		}
+		_, err := fmt.Fprintln(out, greeting.Formatted)
+		return err
+	}
+	greet := func(name string) error {
+		greeting, err := greeter.Greet(name)
+		if err != nil {
+			return err
+		}
+		return emit(greeting)
+	}
+
+	if *name != "" {
+		if err := greet(*name); err != nil {
+			fmt.Fprintln(stderr, "greet:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *file != "" {
+		return greetFile(greeter, *file, emit, stderr)
+	}
+
+	status := 0
+	scanner := bufio.NewScanner(stdin)
+	for line := 1; scanner.Scan(); line++ {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "" {
+			continue
+		}
+		if err := greet(name); err != nil {
+			fmt.Fprintf(stderr, "greet: line %d: %v\n", line, err)
+			status = 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(stderr, "greet: reading stdin:", err)
+		return 1
+	}
+
+	return status
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"example.com/greetings"
+)
+
+// settings are what the REPL builds its Greeter from.
+type settings struct {
+	locale    string
+	formality greetings.Formality
+	style     string
+	emoji     bool
+	catalog   greetings.Catalog // nil for the built-in one
+	json      bool
+	banner    bool
+}
+
+// greeter returns a Greeter configured by s.
+func (s settings) greeter() (*greetings.Greeter, error) {
+	opts := []greetings.Option{
+		greetings.WithLocale(s.locale),
+		greetings.WithFormality(s.formality),
+		greetings.WithEmoji(s.emoji),
+	}
+	switch {
+	case s.banner:
+		opts = append(opts, greetings.WithFormat(greetings.FormatBanner))
+	case !s.json:
+		// Colors the terminal, and is plain text anywhere else.
+		opts = append(opts, greetings.WithFormat(greetings.FormatANSI))
+	}
+	if s.catalog != nil {
+		opts = append(opts, greetings.WithCatalog(s.catalog))
+	}
+	if s.style != "" {
+		opts = append(opts, greetings.WithStyle(s.style))
+	}
+	return greetings.New(opts...)
+}
+
+const replHelp = `Type a name to greet it, or a command:
+  /locale <locale>          switch locale (%s)
+  /formality <register>     casual, neutral or formal
+  /style <style>|none       use a registered style (%s)
+  /emoji on|off             add the locale's emoji
+  /format text|json|banner  print messages, JSON greetings or banners
+  /show                     show the current settings
+  /help                     show this help
+  /quit                     leave (so does end of input)
+`
+
+// repl runs the interactive mode: it prompts for names and slash commands
+// on in and answers on out until /quit or end of input.
+func repl(s settings, in io.Reader, out io.Writer) error {
+
+	greeter, err := s.greeter()
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	fmt.Fprintln(w, `greetings playground; /help lists commands.`)
+	scanner := bufio.NewScanner(in)
+	for {
+		fmt.Fprint(w, "greet> ")
+		w.Flush()
+		if !scanner.Scan() {
+			fmt.Fprintln(w)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "/") {
+			greeting, err := greeter.Greet(line)
+			switch {
+			case err != nil:
+				fmt.Fprintln(w, "error:", err)
+			case s.json:
+				enc.Encode(greeting)
+			default:
+				fmt.Fprintln(w, greeting.Formatted)
+			}
+			continue
+		}
+
+		cmd, arg, _ := strings.Cut(line[1:], " ")
+		arg = strings.TrimSpace(arg)
+		next := s
+		switch cmd {
+		case "quit", "exit":
+			return nil
+		case "help":
+			fmt.Fprintf(w, replHelp, strings.Join(greetings.Locales(), ", "), strings.Join(greetings.Styles(), ", "))
+			continue
+		case "show":
+			style := s.style
+			if style == "" {
+				style = "none"
+			}
+			format := "text"
+			switch {
+			case s.json:
+				format = "json"
+			case s.banner:
+				format = "banner"
+			}
+			fmt.Fprintf(w, "locale %s, formality %v, style %s, emoji %t, format %s\n",
+				greeter.Locale(), s.formality, style, s.emoji, format)
+			continue
+		case "locale":
+			next.locale = arg
+		case "formality":
+			f, err := greetings.ParseFormality(arg)
+			if err != nil {
+				fmt.Fprintln(w, "error:", err)
+				continue
+			}
+			next.formality = f
+		case "style":
+			// Registers read naturally as styles, so "/style formal"
+			// selects the register rather than a registered style.
+			if f, err := greetings.ParseFormality(arg); err == nil {
+				next.formality, next.style = f, ""
+			} else if arg == "none" || arg == "" {
+				next.style = ""
+			} else {
+				next.style = arg
+			}
+		case "emoji":
+			switch arg {
+			case "on":
+				next.emoji = true
+			case "off":
+				next.emoji = false
+			default:
+				fmt.Fprintln(w, "error: /emoji takes on or off")
+				continue
+			}
+		case "format":
+			switch arg {
+			case "text":
+				next.json, next.banner = false, false
+			case "json":
+				next.json, next.banner = true, false
+			case "banner":
+				next.json, next.banner = false, true
+			default:
+				fmt.Fprintln(w, "error: /format takes text, json or banner")
+				continue
+			}
+		default:
+			fmt.Fprintf(w, "error: unknown command /%s; /help lists commands\n", cmd)
+			continue
+		}
+
+		g, err := next.greeter()
+		if err != nil {
+			fmt.Fprintln(w, "error:", err)
+			continue
+		}
+		s, greeter = next, g
+	}
+}
@@ -0,0 +1,206 @@
+package greetings
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// The environment variables FromEnv and LoadConfig read.
+const (
+	EnvLocale    = "GREET_LOCALE"
+	EnvStyle     = "GREET_STYLE"
+	EnvTheme     = "GREET_THEME"
+	EnvFormality = "GREET_FORMALITY"
+	EnvMaxLength = "GREET_MAX_LEN"
+)
+
+// A config file is TOML, YAML or JSON, told apart by its extension, with
+// the same settings as the environment variables:
+//
+//	locale = "es"
+//	style = "pirate"
+//	theme = "festive"
+//	formality = "formal"
+//	max_length = 80
+//
+// Every key is optional; unknown keys are errors.
+
+// ConfigError describes a bad setting in the environment or a config file.
+type ConfigError struct {
+	File string // name of the config file, "" for the environment
+	Key  string // the variable or file key, like "GREET_MAX_LEN" or "max_length"
+	Err  error
+}
+
+func (e *ConfigError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("%s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.File, e.Key, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// FromEnv returns a Greeter configured by the GREET_* environment variables
+// and then by opts, which take precedence. Unset or empty variables leave
+// the defaults alone. Every bad variable is reported, each as a
+// *ConfigError naming it, joined into one error.
+func FromEnv(opts ...Option) (*Greeter, error) {
+
+	env, err := envOptions(os.LookupEnv)
+	if err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+
+	return New(append(env, opts...)...)
+}
+
+// LoadConfig is like FromEnv but starts from the config file at path, so
+// explicit options override the environment, which overrides the file,
+// which overrides the defaults.
+func LoadConfig(path string, opts ...Option) (*Greeter, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+	file, err := fileOptions(filepath.Base(path), data)
+	if err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+	env, err := envOptions(os.LookupEnv)
+	if err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+
+	return New(append(append(file, env...), opts...)...)
+}
+
+// envOptions turns the GREET_* variables found by lookup into options.
+func envOptions(lookup func(string) (string, bool)) ([]Option, error) {
+
+	var opts []Option
+	var errs []error
+	for _, key := range []string{EnvLocale, EnvStyle, EnvTheme, EnvFormality, EnvMaxLength} {
+		value, ok := lookup(key)
+		if !ok || value == "" {
+			continue
+		}
+		opt, err := configOption("", key, key, value)
+		if err != nil {
+			errs = append(errs, &ConfigError{Key: key, Err: err})
+			continue
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts, errors.Join(errs...)
+}
+
+// configKeys maps config file keys to their environment variables.
+var configKeys = map[string]string{
+	"locale":     EnvLocale,
+	"style":      EnvStyle,
+	"theme":      EnvTheme,
+	"formality":  EnvFormality,
+	"max_length": EnvMaxLength,
+}
+
+// fileOptions parses config file contents into options; name picks the
+// syntax and is used in error messages.
+func fileOptions(name string, data []byte) ([]Option, error) {
+
+	settings := make(map[string]any)
+	var err error
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".toml":
+		_, err = toml.Decode(string(data), &settings)
+	case ".yaml", ".yml", ".json":
+		err = yaml.Unmarshal(data, &settings)
+	default:
+		return nil, &ConfigError{File: name, Err: errors.New("unknown config format; want .toml, .yaml or .json")}
+	}
+	if err != nil {
+		return nil, &ConfigError{File: name, Err: err}
+	}
+
+	var opts []Option
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		env, ok := configKeys[key]
+		if !ok {
+			errs = append(errs, &ConfigError{File: name, Key: key, Err: errors.New("unknown key")})
+			continue
+		}
+		var value string
+		switch v := settings[key].(type) {
+		case string:
+			value = v
+		case int, int64:
+			value = fmt.Sprint(v)
+		default:
+			errs = append(errs, &ConfigError{File: name, Key: key, Err: fmt.Errorf("unexpected value %v", v)})
+			continue
+		}
+		opt, err := configOption(name, key, env, value)
+		if err != nil {
+			errs = append(errs, &ConfigError{File: name, Key: key, Err: err})
+			continue
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts, errors.Join(errs...)
+}
+
+// configOption returns the option for one setting, identified by its
+// environment variable, whose errors name file and key. It fails at once
+// for values that are malformed whatever the Greeter.
+func configOption(file, key, env, value string) (Option, error) {
+
+	var opt Option
+	switch env {
+	case EnvLocale:
+		// The locale can only be checked once New has its catalog, so
+		// leave New a note of where it came from.
+		return func(g *Greeter) error {
+			g.locale = value
+			g.localeConfig = &ConfigError{File: file, Key: key}
+			return nil
+		}, nil
+	case EnvStyle:
+		opt = WithStyle(value)
+	case EnvTheme:
+		opt = WithTheme(value)
+	case EnvFormality:
+		f, err := ParseFormality(value)
+		if err != nil {
+			return nil, err
+		}
+		opt = WithFormality(f)
+	case EnvMaxLength:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid length %q", value)
+		}
+		opt = WithMaxLength(n)
+	}
+
+	return func(g *Greeter) error {
+		if err := opt(g); err != nil {
+			return &ConfigError{File: file, Key: key, Err: err}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,124 @@
+package greetings_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"example.com/greetings"
+)
+
+// setEnv clears the GREET_* variables for the test, then sets env.
+func setEnv(t *testing.T, env map[string]string) {
+	t.Helper()
+	for _, key := range []string{greetings.EnvLocale, greetings.EnvStyle, greetings.EnvTheme, greetings.EnvFormality, greetings.EnvMaxLength} {
+		t.Setenv(key, env[key])
+	}
+}
+
+// writeConfig writes a config file called name into a temporary
+// directory and returns its path.
+func writeConfig(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		file string // YAML; "" for FromEnv
+		env  map[string]string
+		opts []greetings.Option
+		want string
+	}{
+		{"defaults", "", nil, nil, "Hi, Ann. Welcome!"},
+		{"env", "", map[string]string{greetings.EnvLocale: "es"}, nil, "Hola, Ann. Te damos la bienvenida."},
+		{"option over env", "", map[string]string{greetings.EnvLocale: "es"}, []greetings.Option{greetings.WithLocale("en")}, "Hi, Ann. Welcome!"},
+		{"file", "locale: es\nformality: casual\n", nil, nil, "¡Hola, Ann!"},
+		{"env over file", "locale: es\nformality: casual\n", map[string]string{greetings.EnvLocale: "en"}, nil, "Hey Ann!"},
+		{"option over env over file", "locale: es\nformality: casual\n", map[string]string{greetings.EnvFormality: "formal"}, []greetings.Option{greetings.WithLocale("en")}, "Dear Ann, welcome."},
+		{"max length", "max_length: 12\n", nil, nil, "Hi, Ann. We…"},
+		{"max length from env", "max_length: 12\n", map[string]string{greetings.EnvMaxLength: "0"}, nil, "Hi, Ann. Welcome!"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			var g *greetings.Greeter
+			var err error
+			if tt.file == "" {
+				g, err = greetings.FromEnv(tt.opts...)
+			} else {
+				g, err = greetings.LoadConfig(writeConfig(t, "greet.yaml", tt.file), tt.opts...)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := g.Hello("Ann"); err != nil || got != tt.want {
+				t.Errorf("Hello(Ann) = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigFormats(t *testing.T) {
+	for name, data := range map[string]string{
+		"greet.toml": "locale = \"es\"\nmax_length = 80\n",
+		"greet.yml":  "locale: es\nmax_length: 80\n",
+		"greet.json": `{"locale": "es", "max_length": 80}`,
+	} {
+		setEnv(t, nil)
+		g, err := greetings.LoadConfig(writeConfig(t, name, data))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if g.Locale() != "es" {
+			t.Errorf("%s: Locale() = %q, want %q", name, g.Locale(), "es")
+		}
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name, file, data string
+		env              map[string]string
+		want             []string // keys the error names
+		wantFile         bool     // whether it names the file too
+	}{
+		{"format", "greet.ini", "locale=es", nil, nil, true},
+		{"syntax", "greet.toml", "locale = ", nil, nil, true},
+		{"unknown key", "greet.yaml", "colour: red\n", nil, []string{"colour"}, true},
+		{"bad values", "greet.yaml", "max_length: -1\nformality: rude\nstyle: [a]\n", nil, []string{"max_length", "formality", "style"}, true},
+		{"unknown locale", "greet.yaml", "locale: xx\n", nil, []string{"locale"}, true},
+		{"unknown style", "greet.toml", "style = \"nope\"\n", nil, []string{"style"}, true},
+		{"env", "", "", map[string]string{greetings.EnvMaxLength: "abc", greetings.EnvFormality: "rude"}, []string{greetings.EnvMaxLength, greetings.EnvFormality}, false},
+		{"unknown locale in env", "", "", map[string]string{greetings.EnvLocale: "xx"}, []string{greetings.EnvLocale}, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.env)
+			var err error
+			if tt.file == "" {
+				_, err = greetings.FromEnv()
+			} else {
+				_, err = greetings.LoadConfig(writeConfig(t, tt.file, tt.data))
+			}
+			var ce *greetings.ConfigError
+			if !errors.As(err, &ce) {
+				t.Fatalf("error = %v, want a *ConfigError", err)
+			}
+			for _, key := range tt.want {
+				if !strings.Contains(err.Error(), key+": ") {
+					t.Errorf("error %q does not name %s", err, key)
+				}
+			}
+			if named := strings.Contains(err.Error(), tt.file+": "); tt.wantFile && !named {
+				t.Errorf("error %q does not name the file %s", err, tt.file)
+			}
+		})
+	}
+}
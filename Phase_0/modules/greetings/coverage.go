@@ -0,0 +1,91 @@
+package greetings
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Coverage is how much of the reference catalog entry, English, one
+// locale's entry has its own wording for. Missing parts fall back to the
+// locale's neutral template, or are left out, as with a missing birthday
+// greeting.
+type Coverage struct {
+	Locale string `json:"locale"`
+
+	// Translated and Total count the parts of the reference entry the
+	// locale has and the parts there are.
+	Translated int `json:"translated"`
+	Total      int `json:"total"`
+
+	// Missing names the parts the locale lacks: "neutral", "casual" and
+	// "formal" for the registers, "birthday", "welcome_back" and "group"
+	// for the other messages.
+	Missing []string `json:"missing"`
+}
+
+// Complete reports whether the locale has every part of the reference
+// entry.
+func (c Coverage) Complete() bool {
+	return len(c.Missing) == 0
+}
+
+// Percent returns the share of the reference entry the locale has, from
+// 0 to 100.
+func (c Coverage) Percent() float64 {
+	if c.Total == 0 {
+		return 100
+	}
+	return 100 * float64(c.Translated) / float64(c.Total)
+}
+
+// coverageParts are the parts of a catalog entry Coverage counts, and
+// the text of each.
+var coverageParts = []struct {
+	name string
+	text func(Message) string
+}{
+	{"neutral", func(m Message) string { return m.Template }},
+	{"casual", func(m Message) string { return m.Casual.Template }},
+	{"formal", func(m Message) string { return m.Formal.Template }},
+	{"birthday", func(m Message) string { return m.Birthday }},
+	{"welcome_back", func(m Message) string { return m.WelcomeBack }},
+	{"group", func(m Message) string { return m.Group }},
+}
+
+// CoverageReport returns the Coverage of every locale of the built-in
+// catalog but English, the least complete first.
+func CoverageReport() []Coverage {
+	return builtin.CoverageReport()
+}
+
+// CoverageReport returns the Coverage of every locale of c but English,
+// measured against c's English entry, the least complete first and in
+// locale order among equals.
+func (c Catalog) CoverageReport() []Coverage {
+
+	ref := c[defaultLocale]
+	var report []Coverage
+	for _, locale := range c.Locales() {
+		if locale == defaultLocale {
+			continue
+		}
+		cov := Coverage{Locale: locale, Missing: []string{}}
+		for _, part := range coverageParts {
+			if part.text(ref) == "" {
+				continue
+			}
+			cov.Total++
+			if part.text(c[locale]) == "" {
+				cov.Missing = append(cov.Missing, part.name)
+			} else {
+				cov.Translated++
+			}
+		}
+		report = append(report, cov)
+	}
+	slices.SortStableFunc(report, func(a, b Coverage) int {
+		return cmp.Compare(a.Percent(), b.Percent())
+	})
+
+	return report
+}
@@ -0,0 +1,92 @@
+package greetings_test
+
+import (
+	"slices"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestCatalogCoverageReport(t *testing.T) {
+
+	c := greetings.Catalog{
+		"en": {
+			Template: "Hi, %v.",
+			Casual:   greetings.Variant{Template: "Hey %v"},
+			Formal:   greetings.Variant{Template: "Dear %v,"},
+			Birthday: "Happy birthday, %v!",
+			Group:    "Hi, {names}.",
+		},
+		"de": {Template: "Hallo, %v."},
+		"es": {
+			Template: "Hola, %v.",
+			Casual:   greetings.Variant{Template: "Ey %v"},
+			Formal:   greetings.Variant{Template: "Estimado %v,"},
+			Birthday: "¡Feliz cumpleaños, %v!",
+			Group:    "Hola, {names}.",
+			// Parts the reference lacks are not counted.
+			WelcomeBack: "Hola de nuevo, %v.",
+		},
+		"fr": {Template: "Bonjour, %v.", Birthday: "Joyeux anniversaire, %v\u00a0!"},
+		"pt": {Template: "Olá, %v."},
+	}
+	got := c.CoverageReport()
+
+	want := []greetings.Coverage{
+		{Locale: "de", Translated: 1, Total: 5, Missing: []string{"casual", "formal", "birthday", "group"}},
+		{Locale: "pt", Translated: 1, Total: 5, Missing: []string{"casual", "formal", "birthday", "group"}},
+		{Locale: "fr", Translated: 2, Total: 5, Missing: []string{"casual", "formal", "group"}},
+		{Locale: "es", Translated: 5, Total: 5, Missing: []string{}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CoverageReport() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		g, w := got[i], want[i]
+		if g.Locale != w.Locale || g.Translated != w.Translated || g.Total != w.Total || !slices.Equal(g.Missing, w.Missing) {
+			t.Errorf("CoverageReport()[%d] = %+v, want %+v", i, g, w)
+		}
+	}
+	if got[3].Missing == nil {
+		t.Error("a complete locale's Missing is nil, want an empty list for JSON")
+	}
+}
+
+func TestCoveragePercentAndComplete(t *testing.T) {
+	for _, tt := range []struct {
+		c        greetings.Coverage
+		percent  float64
+		complete bool
+	}{
+		{greetings.Coverage{Translated: 5, Total: 5, Missing: []string{}}, 100, true},
+		{greetings.Coverage{Translated: 1, Total: 4, Missing: []string{"casual", "formal", "group"}}, 25, false},
+		{greetings.Coverage{}, 100, true},
+	} {
+		if got := tt.c.Percent(); got != tt.percent {
+			t.Errorf("%+v.Percent() = %v, want %v", tt.c, got, tt.percent)
+		}
+		if got := tt.c.Complete(); got != tt.complete {
+			t.Errorf("%+v.Complete() = %v, want %v", tt.c, got, tt.complete)
+		}
+	}
+}
+
+func TestBuiltinCoverageReport(t *testing.T) {
+
+	report := greetings.CoverageReport()
+	locales := make([]string, len(report))
+	for i, c := range report {
+		locales[i] = c.Locale
+		if c.Translated+len(c.Missing) != c.Total {
+			t.Errorf("%s: %d translated and %d missing of %d parts", c.Locale, c.Translated, len(c.Missing), c.Total)
+		}
+		if i > 0 && report[i-1].Percent() > c.Percent() {
+			t.Errorf("%s (%v%%) comes after %s (%v%%), want the least complete first", c.Locale, c.Percent(), report[i-1].Locale, report[i-1].Percent())
+		}
+	}
+	slices.Sort(locales)
+	want := slices.DeleteFunc(greetings.Builtin().Locales(), func(l string) bool { return l == "en" })
+	if !slices.Equal(locales, want) {
+		t.Errorf("CoverageReport() covers %q, want every built-in locale but en: %q", locales, want)
+	}
+}
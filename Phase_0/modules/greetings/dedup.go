@@ -0,0 +1,104 @@
+package greetings
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LastSeenFunc reports when name was last greeted, with false if it never
+// was. It lets a Dedup consult a shared record such as a history store.
+type LastSeenFunc func(ctx context.Context, name string) (time.Time, bool, error)
+
+// Dedup keeps a Greeter from greeting the same person more than once
+// within a window. Repeat greetings come back with Skipped set and no
+// message instead; group greetings leave out the recipients greeted
+// recently and are skipped only when that leaves nobody. Install it with
+// WithDedup or Use(d.Middleware), outside any Cache. A Dedup is safe for
+// concurrent use and may be shared by several Greeters.
+type Dedup struct {
+	window time.Duration
+	last   LastSeenFunc // nil to remember names in seen
+
+	mu    sync.Mutex
+	seen  map[string]time.Time
+	swept time.Time
+}
+
+// NewDedup returns a Dedup that remembers who it greeted in memory,
+// forgetting each name once window has passed since its greeting.
+func NewDedup(window time.Duration) *Dedup {
+	return &Dedup{window: window, seen: make(map[string]time.Time)}
+}
+
+// NewDedupFunc returns a Dedup that asks last when each recipient was
+// greeted instead of remembering, for records shared between processes.
+// It does not record greetings itself: whatever last reads from must be
+// written inside the Dedup, as history.Dedup arranges.
+func NewDedupFunc(window time.Duration, last LastSeenFunc) *Dedup {
+	return &Dedup{window: window, last: last}
+}
+
+// WithDedup skips greetings for anyone greeted within window, as
+// remembered by a NewDedup of the Greeter's own.
+func WithDedup(window time.Duration) Option {
+	return Use(NewDedup(window).Middleware)
+}
+
+// Middleware drops the recipients greeted within the window, skips the
+// greeting if none are left and otherwise asks next, noting the recipients
+// as greeted once it succeeds.
+func (d *Dedup) Middleware(next Provider) Provider {
+	return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+
+		fresh := req.Recipients[:0:0]
+		for _, p := range req.Recipients {
+			recent, err := d.recent(ctx, p.Name, req.Time)
+			if err != nil {
+				return Greeting{}, err
+			}
+			if !recent {
+				fresh = append(fresh, p)
+			}
+		}
+		if len(fresh) == 0 && len(req.Recipients) > 0 {
+			return Greeting{Name: req.Recipients[0].Name, Locale: req.Locale, GeneratedAt: req.Time, Skipped: true}, nil
+		}
+		req.Recipients = fresh
+
+		greeting, err := next.Greet(ctx, req)
+		if err != nil || d.last != nil {
+			return greeting, err
+		}
+		d.mu.Lock()
+		for _, p := range fresh {
+			d.seen[p.Name] = req.Time
+		}
+		d.mu.Unlock()
+
+		return greeting, nil
+	})
+}
+
+// recent reports whether name was greeted within the window before now.
+func (d *Dedup) recent(ctx context.Context, name string, now time.Time) (bool, error) {
+
+	if d.last != nil {
+		at, ok, err := d.last(ctx, name)
+		return ok && now.Sub(at) < d.window, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if now.Sub(d.swept) >= d.window {
+		for n, at := range d.seen {
+			if now.Sub(at) >= d.window {
+				delete(d.seen, n)
+			}
+		}
+		d.swept = now
+	}
+	at, ok := d.seen[name]
+
+	return ok && now.Sub(at) < d.window, nil
+}
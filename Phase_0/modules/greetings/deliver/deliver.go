@@ -0,0 +1,27 @@
+// Package deliver sends greetings to where people read them. Each
+// destination is a Sender, which a Greeter can greet into in one call:
+//
+//	slack := deliver.NewSlack(webhookURL)
+//	greeting, err := greeter.GreetAndSend(ctx, "Ada", slack)
+//
+// Slack posts to an incoming webhook; Email sends multipart mail over
+// SMTP.
+package deliver
+
+import (
+	"context"
+
+	"example.com/greetings"
+)
+
+// Sender delivers a greeting; it is greetings.Sender, repeated here so
+// implementations need only import this package.
+type Sender = greetings.Sender
+
+// SenderFunc adapts an ordinary function to the Sender interface.
+type SenderFunc func(ctx context.Context, greeting greetings.Greeting) error
+
+// Send returns f(ctx, greeting).
+func (f SenderFunc) Send(ctx context.Context, greeting greetings.Greeting) error {
+	return f(ctx, greeting)
+}
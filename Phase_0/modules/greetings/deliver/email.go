@@ -0,0 +1,240 @@
+package deliver
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"text/template"
+	"time"
+
+	"example.com/greetings"
+)
+
+// DefaultSubject is the subject template of a new Email.
+const DefaultSubject = "A greeting for {{.Name}}"
+
+// Email sends greetings as email through an SMTP server: a multipart
+// message with the plain-text greeting and an HTML part rendered like
+// greetings.FormatHTML. The From and Subject headers are text/template
+// templates executed with the greetings.Greeting. Set the exported fields
+// before the first Send; an Email is then safe for concurrent use.
+type Email struct {
+	// Addr is the SMTP server, "host:port".
+	Addr string
+
+	// To lists the recipients of every greeting. Recipients, when set,
+	// chooses them per greeting instead.
+	To         []string
+	Recipients func(ctx context.Context, greeting greetings.Greeting) ([]string, error)
+
+	// Auth authenticates to the server, after STARTTLS when the server
+	// offers it; nil skips authentication.
+	Auth smtp.Auth
+
+	// TLSConfig configures STARTTLS; nil means a default config for the
+	// server's host. RequireTLS fails sends to servers that do not offer
+	// STARTTLS instead of falling back to plain text.
+	TLSConfig  *tls.Config
+	RequireTLS bool
+
+	// Retry says how to retry sends that fail before the server accepted
+	// the message: connection errors and temporary SMTP replies (4xx).
+	Retry greetings.RetryPolicy
+
+	from    *template.Template
+	subject *template.Template
+}
+
+// NewEmail returns an Email sending through the SMTP server at addr, with
+// from and subject as the templates of the From and Subject headers; an
+// empty subject means DefaultSubject. It fails if a template does not
+// parse.
+func NewEmail(addr, from, subject string) (*Email, error) {
+
+	if subject == "" {
+		subject = DefaultSubject
+	}
+	e := &Email{Addr: addr}
+	var err error
+	if e.from, err = template.New("from").Option("missingkey=error").Parse(from); err != nil {
+		return nil, fmt.Errorf("deliver: email from: %w", err)
+	}
+	if e.subject, err = template.New("subject").Option("missingkey=error").Parse(subject); err != nil {
+		return nil, fmt.Errorf("deliver: email subject: %w", err)
+	}
+
+	return e, nil
+}
+
+// Send emails greeting to its recipients.
+func (e *Email) Send(ctx context.Context, greeting greetings.Greeting) error {
+
+	to := e.To
+	if e.Recipients != nil {
+		var err error
+		if to, err = e.Recipients(ctx, greeting); err != nil {
+			return err
+		}
+	}
+	if len(to) == 0 {
+		return errors.New("deliver: email: no recipients")
+	}
+	from, msg, err := e.message(greeting, to)
+	if err != nil {
+		return err
+	}
+
+	return e.Retry.Do(ctx, func(ctx context.Context) error {
+		return e.send(ctx, from, to, msg)
+	})
+}
+
+// message returns the envelope sender and the complete message.
+func (e *Email) message(greeting greetings.Greeting, to []string) (string, []byte, error) {
+
+	var header bytes.Buffer
+	if err := e.from.Execute(&header, greeting); err != nil {
+		return "", nil, fmt.Errorf("deliver: email from: %w", err)
+	}
+	from, err := mail.ParseAddress(header.String())
+	if err != nil {
+		return "", nil, fmt.Errorf("deliver: email from %q: %w", header.String(), err)
+	}
+	var subject strings.Builder
+	if err := e.subject.Execute(&subject, greeting); err != nil {
+		return "", nil, fmt.Errorf("deliver: email subject: %w", err)
+	}
+	recipients := make([]string, len(to))
+	for i, addr := range to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return "", nil, fmt.Errorf("deliver: email to %q: %w", addr, err)
+		}
+		recipients[i] = a.String()
+	}
+
+	var b bytes.Buffer
+	body := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject.String()))
+	date := greeting.GeneratedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	if greeting.Locale != "" {
+		fmt.Fprintf(&b, "Content-Language: %s\r\n", greeting.Locale)
+	}
+	fmt.Fprintf(&b, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", body.Boundary())
+
+	page := fmt.Sprintf("<!DOCTYPE html>\r\n<html lang=\"%s\">\r\n<body>\r\n<p>%s</p>\r\n</body>\r\n</html>\r\n",
+		html.EscapeString(greeting.Locale), greetings.HTML(greeting))
+	for _, part := range []struct{ contentType, text string }{
+		{"text/plain; charset=utf-8", greeting.Message + "\r\n"},
+		{"text/html; charset=utf-8", page},
+	} {
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		qp.Write([]byte(part.text))
+		qp.Close()
+	}
+	body.Close()
+
+	return from.Address, b.Bytes(), nil
+}
+
+// send delivers msg in one SMTP session.
+func (e *Email) send(ctx context.Context, from string, to []string, msg []byte) error {
+
+	host, _, err := net.SplitHostPort(e.Addr)
+	if err != nil {
+		return fmt.Errorf("deliver: email: %w", err)
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", e.Addr)
+	if err != nil {
+		return greetings.Transient(err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return smtpError(err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		config := e.TLSConfig
+		if config == nil {
+			config = &tls.Config{ServerName: host}
+		}
+		if err := c.StartTLS(config); err != nil {
+			return fmt.Errorf("deliver: email: starttls: %w", err)
+		}
+	} else if e.RequireTLS {
+		return fmt.Errorf("deliver: email: %s does not support STARTTLS", e.Addr)
+	}
+	if e.Auth != nil {
+		if err := c.Auth(e.Auth); err != nil {
+			return fmt.Errorf("deliver: email: auth: %w", err)
+		}
+	}
+	if err := c.Mail(from); err != nil {
+		return smtpError(err)
+	}
+	for _, addr := range to {
+		a, _ := mail.ParseAddress(addr) // already checked by message
+		if err := c.Rcpt(a.Address); err != nil {
+			return smtpError(err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return smtpError(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		// The server may or may not have taken the message; retrying
+		// could deliver it twice.
+		return fmt.Errorf("deliver: email: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// smtpError wraps an error from before the message was accepted, marking
+// temporary replies and connection failures as transient.
+func smtpError(err error) error {
+	var reply *textproto.Error
+	if errors.As(err, &reply) {
+		err = fmt.Errorf("deliver: email: %w", err)
+		if reply.Code >= 400 && reply.Code < 500 {
+			return greetings.Transient(err)
+		}
+		return err
+	}
+	return greetings.Transient(fmt.Errorf("deliver: email: %w", err))
+}
@@ -0,0 +1,293 @@
+package deliver_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/deliver"
+)
+
+// smtpServer is a minimal SMTP server without STARTTLS. Its sessions
+// answer RCPT with the replies in rcpt in order, the last one from then
+// on, and it keeps the messages it accepts.
+type smtpServer struct {
+	ln   net.Listener
+	rcpt []string
+
+	mu       sync.Mutex
+	sessions int
+	rcpts    int
+	envelope []string
+	messages []string
+}
+
+func newSMTPServer(t *testing.T, rcpt ...string) *smtpServer {
+
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rcpt) == 0 {
+		rcpt = []string{"250 OK"}
+	}
+	s := &smtpServer{ln: ln, rcpt: rcpt}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { ln.Close() })
+
+	return s
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+
+	defer conn.Close()
+	s.mu.Lock()
+	s.sessions++
+	s.mu.Unlock()
+	c := textproto.NewConn(conn)
+	c.PrintfLine("220 localhost ESMTP")
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			c.PrintfLine("250-localhost\r\n250 8BITMIME")
+		case "MAIL":
+			s.mu.Lock()
+			from, _, _ := strings.Cut(arg, " ") // drop parameters like BODY=8BITMIME
+			s.envelope = append(s.envelope, from)
+			s.mu.Unlock()
+			c.PrintfLine("250 OK")
+		case "RCPT":
+			s.mu.Lock()
+			reply := s.rcpt[min(s.rcpts, len(s.rcpt)-1)]
+			s.rcpts++
+			if strings.HasPrefix(reply, "2") {
+				s.envelope = append(s.envelope, arg)
+			}
+			s.mu.Unlock()
+			c.PrintfLine("%s", reply)
+		case "DATA":
+			c.PrintfLine("354 Go ahead")
+			data, err := io.ReadAll(c.DotReader())
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			c.PrintfLine("250 Queued")
+		case "QUIT":
+			c.PrintfLine("221 Bye")
+			return
+		default:
+			c.PrintfLine("250 OK")
+		}
+	}
+}
+
+func (s *smtpServer) stats() (sessions int, envelope, messages []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sessions, s.envelope, s.messages
+}
+
+var greeting = greetings.Greeting{
+	Name:        "Zoë",
+	Message:     "Hi, Zoë. Welcome!",
+	Locale:      "en",
+	GeneratedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestEmailMessage(t *testing.T) {
+
+	srv := newSMTPServer(t)
+	e, err := deliver.NewEmail(srv.ln.Addr().String(), "Greeter <greeter@example.com>", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.To = []string{"Zoë <zoe@example.com>", "ops@example.com"}
+	if err := e.Send(context.Background(), greeting); err != nil {
+		t.Fatal(err)
+	}
+
+	_, envelope, messages := srv.stats()
+	if want := []string{"FROM:<greeter@example.com>", "TO:<zoe@example.com>", "TO:<ops@example.com>"}; strings.Join(envelope, " ") != strings.Join(want, " ") {
+		t.Errorf("envelope = %q, want %q", envelope, want)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("server got %d messages, want 1", len(messages))
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(messages[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "A greeting for Zoë" {
+		t.Errorf("Subject = %q, %v, want %q", subject, err, "A greeting for Zoë")
+	}
+	for key, want := range map[string]string{
+		"From":             `"Greeter" <greeter@example.com>`,
+		"Date":             "Wed, 01 May 2024 12:00:00 +0000",
+		"Content-Language": "en",
+	} {
+		if got := msg.Header.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 2 || to[0].Name != "Zoë" || to[1].Address != "ops@example.com" {
+		t.Errorf("To = %v, %v, want Zoë and ops", to, err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, %v, want multipart/alternative", mediaType, err)
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for _, want := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Hi, Zoë. Welcome!"},
+		{"text/html; charset=utf-8", "<p>" + string(greetings.HTML(greeting)) + "</p>"},
+	} {
+		p, err := parts.NextPart()
+		if err != nil {
+			t.Fatalf("reading %s part: %v", want.contentType, err)
+		}
+		body, _ := io.ReadAll(p)
+		if got := p.Header.Get("Content-Type"); got != want.contentType {
+			t.Errorf("part Content-Type = %q, want %q", got, want.contentType)
+		}
+		if !strings.Contains(string(body), want.body) {
+			t.Errorf("%s part = %q, want it to contain %q", want.contentType, body, want.body)
+		}
+	}
+}
+
+func TestEmailTemplatesAndRecipients(t *testing.T) {
+
+	srv := newSMTPServer(t)
+	e, err := deliver.NewEmail(srv.ln.Addr().String(), "{{.Locale}}@example.com", "Hello {{.Name}} ({{.Locale}})")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Recipients = func(_ context.Context, g greetings.Greeting) ([]string, error) {
+		return []string{strings.ToLower(g.Locale) + "-team@example.com"}, nil
+	}
+	if err := e.Send(context.Background(), greeting); err != nil {
+		t.Fatal(err)
+	}
+	_, envelope, messages := srv.stats()
+	if strings.Join(envelope, " ") != "FROM:<en@example.com> TO:<en-team@example.com>" {
+		t.Errorf("envelope = %q, want en@ to en-team@", envelope)
+	}
+	if len(messages) != 1 || !strings.Contains(messages[0], "Subject: =?utf-8?q?Hello_Zo=C3=AB_(en)?=") {
+		t.Errorf("messages = %q, want one with a Q-encoded subject", messages)
+	}
+}
+
+func TestEmailRejects(t *testing.T) {
+
+	if _, err := deliver.NewEmail("localhost:25", "{{.Name", ""); err == nil || !strings.Contains(err.Error(), "deliver: email from") {
+		t.Errorf("NewEmail with a broken From template = %v, want an error", err)
+	}
+	if _, err := deliver.NewEmail("localhost:25", "a@example.com", "{{end}}"); err == nil || !strings.Contains(err.Error(), "deliver: email subject") {
+		t.Errorf("NewEmail with a broken Subject template = %v, want an error", err)
+	}
+
+	srv := newSMTPServer(t)
+	addr := srv.ln.Addr().String()
+	for _, tt := range []struct {
+		name, from, subject string
+		to                  []string
+		requireTLS          bool
+		want                string
+	}{
+		{"no recipients", "a@example.com", "", nil, false, "no recipients"},
+		{"bad from", "not an address", "", []string{"b@example.com"}, false, "deliver: email from"},
+		{"bad to", "a@example.com", "", []string{"b@"}, false, `deliver: email to "b@"`},
+		{"unknown field", "a@example.com", "{{.Nope}}", []string{"b@example.com"}, false, "deliver: email subject"},
+		{"no STARTTLS", "a@example.com", "", []string{"b@example.com"}, true, "does not support STARTTLS"},
+	} {
+		e, err := deliver.NewEmail(addr, tt.from, tt.subject)
+		if err != nil {
+			t.Fatalf("%s: NewEmail: %v", tt.name, err)
+		}
+		e.To, e.RequireTLS = tt.to, tt.requireTLS
+		if err := e.Send(context.Background(), greeting); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: Send = %v, want an error containing %q", tt.name, err, tt.want)
+		}
+	}
+	if _, _, messages := srv.stats(); len(messages) != 0 {
+		t.Errorf("server got %d messages, want none", len(messages))
+	}
+}
+
+func TestEmailRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		rcpt     []string
+		sessions int
+		messages int
+		err      string
+	}{
+		{"temporary then ok", []string{"451 Try again later", "250 OK"}, 2, 1, ""},
+		{"always temporary", []string{"452 Mailbox full"}, 3, 0, `452 "Mailbox full"`},
+		{"permanent", []string{"550 No such user"}, 1, 0, `550 "No such user"`},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newSMTPServer(t, tt.rcpt...)
+			e, err := deliver.NewEmail(srv.ln.Addr().String(), "a@example.com", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.To, e.Retry = []string{"b@example.com"}, fastRetry
+			err = e.Send(context.Background(), greeting)
+			if tt.err == "" && err != nil {
+				t.Errorf("Send: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Send = %v, want an error containing %q", err, tt.err)
+			}
+			sessions, _, messages := srv.stats()
+			if sessions != tt.sessions || len(messages) != tt.messages {
+				t.Errorf("server saw %d sessions and %d messages, want %d and %d", sessions, len(messages), tt.sessions, tt.messages)
+			}
+		})
+	}
+}
+
+func TestEmailUnreachableIsTransient(t *testing.T) {
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	e, _ := deliver.NewEmail(addr, "a@example.com", "")
+	e.To, e.Retry = []string{"b@example.com"}, greetings.RetryPolicy{Attempts: 1}
+	if err := e.Send(context.Background(), greeting); !greetings.IsTransient(err) {
+		t.Errorf("Send to a closed port = %v, want a transient error", err)
+	}
+}
@@ -0,0 +1,114 @@
+package deliver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"example.com/greetings"
+)
+
+// Slack posts greetings to a Slack incoming webhook, which delivers them
+// to the channel it was created for. Failed posts are retried as Retry
+// says when Slack throttles them or is unavailable. Set the fields before
+// the first Send; a Slack is then safe for concurrent use.
+type Slack struct {
+	// URL is the webhook URL, https://hooks.slack.com/services/...
+	URL string
+
+	// Client makes the requests; nil means http.DefaultClient.
+	Client *http.Client
+
+	// Retry says how to retry failed posts.
+	Retry greetings.RetryPolicy
+
+	// Username and IconEmoji, like ":wave:", override the webhook's
+	// defaults where the workspace allows it.
+	Username  string
+	IconEmoji string
+}
+
+// NewSlack returns a Slack posting to the incoming webhook at url with
+// the default retry policy.
+func NewSlack(url string) *Slack {
+	return &Slack{URL: url}
+}
+
+// slackMessage is the webhook payload.
+type slackMessage struct {
+	Text      string       `json:"text"`
+	Blocks    []slackBlock `json:"blocks,omitempty"`
+	Username  string       `json:"username,omitempty"`
+	IconEmoji string       `json:"icon_emoji,omitempty"`
+}
+
+type slackBlock struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Send posts greeting to the webhook: the plain message for
+// notifications, and a section with the recipient's name in bold.
+func (s *Slack) Send(ctx context.Context, greeting greetings.Greeting) error {
+
+	body, err := json.Marshal(s.payload(greeting))
+	if err != nil {
+		return err
+	}
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return s.Retry.Do(ctx, func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return greetings.Transient(err)
+		}
+		defer resp.Body.Close()
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+
+		if resp.StatusCode == http.StatusOK {
+			return nil
+		}
+		err = fmt.Errorf("deliver: slack: %s: %s", resp.Status, bytes.TrimSpace(reply))
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			return greetings.Transient(err)
+		}
+		return err
+	})
+}
+
+// payload formats greeting for Slack.
+func (s *Slack) payload(greeting greetings.Greeting) slackMessage {
+
+	text := slackEscape(greeting.Message)
+	if name := slackEscape(greeting.Name); name != "" {
+		text = strings.Replace(text, name, "*"+name+"*", 1)
+	}
+
+	return slackMessage{
+		Text:      slackEscape(greeting.Message),
+		Blocks:    []slackBlock{{Type: "section", Text: slackText{Type: "mrkdwn", Text: text}}},
+		Username:  s.Username,
+		IconEmoji: s.IconEmoji,
+	}
+}
+
+// slackEscape escapes the characters Slack's mrkdwn treats as control
+// sequences.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace
@@ -0,0 +1,148 @@
+package deliver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/deliver"
+)
+
+// fastRetry retries at once, so tests of Slack's retries do not wait.
+var fastRetry = greetings.RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond, Jitter: -1}
+
+// webhook serves a Slack webhook answering with the statuses in order,
+// the last one from then on, and records the payloads it receives.
+func webhook(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32, chan map[string]any) {
+
+	t.Helper()
+	var calls atomic.Int32
+	payloads := make(chan map[string]any, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", got)
+		}
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		payloads <- payload
+		n := int(calls.Add(1)) - 1
+		status := statuses[min(n, len(statuses)-1)]
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			w.Write([]byte("invalid_payload\n"))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls, payloads
+}
+
+func TestSlackPayload(t *testing.T) {
+
+	srv, _, payloads := webhook(t, http.StatusOK)
+	s := deliver.NewSlack(srv.URL)
+	s.Username, s.IconEmoji = "greeter", ":wave:"
+	greeting := greetings.Greeting{Name: "Ada <admin>", Message: "Hi, Ada <admin> & co!"}
+	if err := s.Send(context.Background(), greeting); err != nil {
+		t.Fatal(err)
+	}
+
+	got := <-payloads
+	if got["text"] != "Hi, Ada &lt;admin&gt; &amp; co!" {
+		t.Errorf("text = %q, want the escaped message", got["text"])
+	}
+	if got["username"] != "greeter" || got["icon_emoji"] != ":wave:" {
+		t.Errorf("username, icon_emoji = %q, %q, want greeter, :wave:", got["username"], got["icon_emoji"])
+	}
+	blocks, _ := got["blocks"].([]any)
+	if len(blocks) != 1 {
+		t.Fatalf("blocks = %v, want one section", got["blocks"])
+	}
+	text, _ := blocks[0].(map[string]any)["text"].(map[string]any)
+	if text["type"] != "mrkdwn" || text["text"] != "Hi, *Ada &lt;admin&gt;* &amp; co!" {
+		t.Errorf("section text = %v, want mrkdwn with the name in bold", text)
+	}
+}
+
+func TestSlackOmitsUnsetOverrides(t *testing.T) {
+	srv, _, payloads := webhook(t, http.StatusOK)
+	if err := deliver.NewSlack(srv.URL).Send(context.Background(), greetings.Greeting{Message: "Hi!"}); err != nil {
+		t.Fatal(err)
+	}
+	got := <-payloads
+	for _, key := range []string{"username", "icon_emoji"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("payload has %s, want it left out", key)
+		}
+	}
+}
+
+func TestSlackRetries(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []int
+		calls    int32
+		err      string
+	}{
+		{"ok", []int{http.StatusOK}, 1, ""},
+		{"unavailable then ok", []int{http.StatusServiceUnavailable, http.StatusOK}, 2, ""},
+		{"throttled then ok", []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusOK}, 3, ""},
+		{"always unavailable", []int{http.StatusBadGateway}, 3, "deliver: slack: 502 Bad Gateway: invalid_payload"},
+		{"bad request", []int{http.StatusBadRequest}, 1, "deliver: slack: 400 Bad Request: invalid_payload"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls, _ := webhook(t, tt.statuses...)
+			s := deliver.NewSlack(srv.URL)
+			s.Retry = fastRetry
+			err := s.Send(context.Background(), greetings.Greeting{Message: "Hi!"})
+			if tt.err == "" && err != nil {
+				t.Errorf("Send: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Errorf("Send = %v, want an error containing %q", err, tt.err)
+			}
+			if got := calls.Load(); got != tt.calls {
+				t.Errorf("webhook called %d times, want %d", got, tt.calls)
+			}
+		})
+	}
+}
+
+func TestGreetAndSend(t *testing.T) {
+
+	g, err := greetings.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	boom := errors.New("boom")
+	var sent greetings.Greeting
+	s := deliver.SenderFunc(func(_ context.Context, greeting greetings.Greeting) error {
+		sent = greeting
+		return boom
+	})
+
+	got, err := g.GreetAndSend(context.Background(), "Ada", s)
+	if !errors.Is(err, boom) {
+		t.Errorf("GreetAndSend = %v, want the Sender's error", err)
+	}
+	if got.Message != "Hi, Ada. Welcome!" || sent.Message != got.Message {
+		t.Errorf("returned %q and sent %q, want the greeting both times", got.Message, sent.Message)
+	}
+
+	sent = greetings.Greeting{}
+	if _, err := g.GreetAndSend(context.Background(), "", s); err == nil || errors.Is(err, boom) {
+		t.Errorf("GreetAndSend of an empty name = %v, want the greeting error", err)
+	}
+	if sent.Message != "" {
+		t.Errorf("sent %q for a failed greeting, want nothing sent", sent.Message)
+	}
+}
@@ -0,0 +1,285 @@
+// Package dispatch hands greeting delivery off to background workers, so
+// a web handler can queue a greeting and answer its request at once. A
+// Dispatcher keeps a bounded in-memory queue, drained by workers that
+// greet with a Greeter and deliver with a greetings.Sender; its Policy
+// says what Enqueue does when the queue is full.
+//
+//	d := &dispatch.Dispatcher{Greeter: g, Sender: slack, Capacity: 1000, Workers: 4}
+//	d.Start()
+//	defer d.Close(ctx)
+//	err := d.Enqueue(ctx, dispatch.Request{Person: greetings.Person{Name: "Ann"}})
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"example.com/greetings"
+)
+
+var (
+	// ErrQueueFull is returned by Enqueue under the Reject policy when the
+	// queue has no room.
+	ErrQueueFull = errors.New("dispatch: queue full")
+
+	// ErrDropped is passed to the Done callback of a request pushed out of
+	// the queue under the DropOldest policy.
+	ErrDropped = errors.New("dispatch: dropped from queue")
+
+	// ErrClosed is returned by Enqueue once Close has been called.
+	ErrClosed = errors.New("dispatch: dispatcher closed")
+)
+
+// Policy is what Enqueue does when the queue is full.
+type Policy int
+
+const (
+	// Block waits for room, or for the context to be done. It is the
+	// default.
+	Block Policy = iota
+
+	// DropOldest makes room by discarding the request that has waited
+	// longest, so the newest greetings get through under load.
+	DropOldest
+
+	// Reject fails with ErrQueueFull.
+	Reject
+)
+
+var policyNames = [...]string{Block: "block", DropOldest: "drop-oldest", Reject: "reject"}
+
+// String returns the lower-case name of p.
+func (p Policy) String() string {
+	if p < 0 || int(p) >= len(policyNames) {
+		return fmt.Sprintf("Policy(%d)", int(p))
+	}
+	return policyNames[p]
+}
+
+// Request is a greeting to deliver.
+type Request struct {
+	Person greetings.Person
+
+	// Done, if set, is called once the request is settled: with the
+	// greeting and the Sender's error after delivery, or with the error
+	// that kept it from being greeted or delivered.
+	Done func(greetings.Greeting, error)
+}
+
+func (r Request) done(g greetings.Greeting, err error) {
+	if r.Done != nil {
+		r.Done(g, err)
+	}
+}
+
+// DefaultCapacity is the queue length of a Dispatcher that leaves
+// Capacity zero.
+const DefaultCapacity = 100
+
+// Dispatcher queues greetings and delivers them in the background. Set
+// its fields, then call Start; it is safe for concurrent use after that.
+type Dispatcher struct {
+	Greeter *greetings.Greeter
+	Sender  greetings.Sender
+
+	// Capacity bounds the queue; zero means DefaultCapacity.
+	Capacity int
+
+	// Workers is the number of concurrent deliveries; zero means one.
+	Workers int
+
+	Policy Policy
+
+	// OnError, if set, is called with every request that fails, in
+	// addition to its Done callback.
+	OnError func(Request, error)
+
+	queue   chan Request
+	done    chan struct{} // closed by Close, to turn away waiting Enqueues
+	mu      sync.RWMutex  // guards closed
+	closed  bool
+	pending sync.WaitGroup // Enqueues under way, which Close waits out
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	wg      sync.WaitGroup
+
+	enqueued, dropped, rejected, sent, failed atomic.Int64
+}
+
+// Start starts the workers.
+func (d *Dispatcher) Start() {
+
+	capacity := d.Capacity
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	workers := max(d.Workers, 1)
+	d.queue = make(chan Request, capacity)
+	d.done = make(chan struct{})
+	d.ctx, d.cancel = context.WithCancelCause(context.Background())
+
+	d.wg.Add(workers)
+	for range workers {
+		go d.work()
+	}
+}
+
+// Enqueue adds r to the queue, handling a full queue as the Policy says.
+// ctx only bounds the wait for room under Block; the delivery itself
+// outlives the caller's request. An Enqueue still waiting when Close is
+// called fails with ErrClosed.
+func (d *Dispatcher) Enqueue(ctx context.Context, r Request) error {
+
+	d.mu.RLock()
+	if d.closed {
+		d.mu.RUnlock()
+		return ErrClosed
+	}
+	d.pending.Add(1)
+	d.mu.RUnlock()
+	defer d.pending.Done()
+
+	select {
+	case d.queue <- r:
+		d.enqueued.Add(1)
+		return nil
+	default:
+	}
+
+	switch d.Policy {
+	case DropOldest:
+		for {
+			select {
+			case d.queue <- r:
+				d.enqueued.Add(1)
+				return nil
+			case old := <-d.queue:
+				d.dropped.Add(1)
+				d.fail(old, ErrDropped)
+			case <-d.done:
+				return ErrClosed
+			}
+		}
+	case Reject:
+		d.rejected.Add(1)
+		return ErrQueueFull
+	default:
+		select {
+		case d.queue <- r:
+			d.enqueued.Add(1)
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-d.done:
+			return ErrClosed
+		}
+	}
+}
+
+// work delivers queued requests until the queue is closed and empty.
+func (d *Dispatcher) work() {
+
+	defer d.wg.Done()
+	for r := range d.queue {
+		if d.ctx.Err() != nil {
+			d.fail(r, context.Cause(d.ctx))
+			continue
+		}
+		greeting, err := d.Greeter.GreetCtx(d.ctx, r.Person)
+		if err != nil {
+			d.fail(r, err)
+			continue
+		}
+		if err := d.Sender.Send(d.ctx, greeting); err != nil {
+			d.failed.Add(1)
+			if d.OnError != nil {
+				d.OnError(r, err)
+			}
+			r.done(greeting, err)
+			continue
+		}
+		d.sent.Add(1)
+		r.done(greeting, nil)
+	}
+}
+
+// fail settles r with err.
+func (d *Dispatcher) fail(r Request, err error) {
+	if !errors.Is(err, ErrDropped) {
+		d.failed.Add(1)
+	}
+	if d.OnError != nil {
+		d.OnError(r, err)
+	}
+	r.done(greetings.Greeting{}, err)
+}
+
+// Close stops accepting requests and waits for the queued ones to be
+// delivered. If ctx is done first, deliveries under way are canceled, the
+// rest of the queue is failed with ctx's error, and Close returns it.
+// Closing a Dispatcher that was never started only turns away later
+// Enqueues.
+func (d *Dispatcher) Close(ctx context.Context) error {
+
+	d.mu.Lock()
+	first := !d.closed
+	d.closed = true
+	d.mu.Unlock()
+	if d.queue == nil {
+		return nil
+	}
+	if first {
+		close(d.done)
+		d.pending.Wait()
+		close(d.queue)
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		d.cancel(nil)
+		return nil
+	case <-ctx.Done():
+		d.cancel(ctx.Err())
+		<-drained
+		return ctx.Err()
+	}
+}
+
+// Stats are a Dispatcher's counters.
+type Stats struct {
+	Depth    int   `json:"depth"`    // requests waiting in the queue
+	Capacity int   `json:"capacity"` // room in the queue
+	Enqueued int64 `json:"enqueued"` // requests accepted
+	Dropped  int64 `json:"dropped"`  // requests pushed out under DropOldest
+	Rejected int64 `json:"rejected"` // requests refused under Reject
+	Sent     int64 `json:"sent"`     // requests delivered
+	Failed   int64 `json:"failed"`   // requests that failed to greet or send
+}
+
+// Stats returns the current counters.
+func (d *Dispatcher) Stats() Stats {
+	return Stats{
+		Depth:    len(d.queue),
+		Capacity: cap(d.queue),
+		Enqueued: d.enqueued.Load(),
+		Dropped:  d.dropped.Load(),
+		Rejected: d.rejected.Load(),
+		Sent:     d.sent.Load(),
+		Failed:   d.failed.Load(),
+	}
+}
+
+// Publish serves d's Stats as the expvar variable name, on /debug/vars.
+// Like expvar.Publish, it panics if name is already in use.
+func (d *Dispatcher) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return d.Stats() }))
+}
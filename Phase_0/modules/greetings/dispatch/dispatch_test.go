@@ -0,0 +1,140 @@
+package dispatch_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/dispatch"
+)
+
+// stuckSender reports every greeting it is sent on started, then waits
+// for its context to be done.
+type stuckSender struct {
+	started chan string
+}
+
+func (s stuckSender) Send(ctx context.Context, g greetings.Greeting) error {
+	s.started <- g.Message
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// start returns a started Dispatcher with one worker and room for one
+// request, whose worker is busy sending a first greeting until the
+// Dispatcher is closed.
+func start(t *testing.T, policy dispatch.Policy) *dispatch.Dispatcher {
+	t.Helper()
+	g, err := greetings.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := stuckSender{started: make(chan string, 10)}
+	d := &dispatch.Dispatcher{Greeter: g, Sender: sender, Capacity: 1, Policy: policy}
+	d.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		d.Close(ctx)
+	})
+	if err := d.Enqueue(context.Background(), dispatch.Request{Person: greetings.Person{Name: "Ann"}}); err != nil {
+		t.Fatal(err)
+	}
+	<-sender.started
+	return d
+}
+
+// settled returns a Request for name whose Done callback reports on the
+// returned channel.
+func settled(name string) (dispatch.Request, chan error) {
+	c := make(chan error, 1)
+	return dispatch.Request{
+		Person: greetings.Person{Name: name},
+		Done:   func(_ greetings.Greeting, err error) { c <- err },
+	}, c
+}
+
+func TestPolicies(t *testing.T) {
+	for _, tt := range []struct {
+		policy      dispatch.Policy
+		wantEnqueue error // from enqueueing into the full queue
+		wantOldest  error // settling the request it was full of
+		wantStats   dispatch.Stats
+	}{
+		{dispatch.Reject, dispatch.ErrQueueFull, nil, dispatch.Stats{Depth: 1, Capacity: 1, Enqueued: 2, Rejected: 1}},
+		{dispatch.DropOldest, nil, dispatch.ErrDropped, dispatch.Stats{Depth: 1, Capacity: 1, Enqueued: 3, Dropped: 1}},
+		{dispatch.Block, context.DeadlineExceeded, nil, dispatch.Stats{Depth: 1, Capacity: 1, Enqueued: 2}},
+	} {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			d := start(t, tt.policy)
+			oldest, settledOldest := settled("Bob")
+			if err := d.Enqueue(context.Background(), oldest); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			defer cancel()
+			if err := d.Enqueue(ctx, dispatch.Request{Person: greetings.Person{Name: "Carol"}}); !errors.Is(err, tt.wantEnqueue) {
+				t.Errorf("Enqueue into a full queue = %v, want %v", err, tt.wantEnqueue)
+			}
+			if tt.wantOldest != nil {
+				if err := <-settledOldest; !errors.Is(err, tt.wantOldest) {
+					t.Errorf("oldest request settled with %v, want %v", err, tt.wantOldest)
+				}
+			}
+			if got := d.Stats(); got != tt.wantStats {
+				t.Errorf("Stats() = %+v, want %+v", got, tt.wantStats)
+			}
+		})
+	}
+}
+
+func TestCloseWhileEnqueueWaits(t *testing.T) {
+	d := start(t, dispatch.Block)
+	queued, settledQueued := settled("Bob")
+	if err := d.Enqueue(context.Background(), queued); err != nil {
+		t.Fatal(err)
+	}
+	waiting := make(chan error)
+	go func() {
+		waiting <- d.Enqueue(context.Background(), dispatch.Request{Person: greetings.Person{Name: "Carol"}})
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	closed := make(chan error)
+	go func() { closed <- d.Close(ctx) }()
+	select {
+	case err := <-closed:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Close = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not return once its context was done")
+	}
+
+	if err := <-waiting; !errors.Is(err, dispatch.ErrClosed) {
+		t.Errorf("waiting Enqueue = %v, want %v", err, dispatch.ErrClosed)
+	}
+	if err := <-settledQueued; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("queued request settled with %v, want Close's %v", err, context.DeadlineExceeded)
+	}
+	if err := d.Enqueue(context.Background(), queued); !errors.Is(err, dispatch.ErrClosed) {
+		t.Errorf("Enqueue after Close = %v, want %v", err, dispatch.ErrClosed)
+	}
+}
+
+func TestCloseBeforeStart(t *testing.T) {
+	var d dispatch.Dispatcher
+	for range 2 {
+		if err := d.Close(context.Background()); err != nil {
+			t.Errorf("Close before Start = %v, want nil", err)
+		}
+	}
+	if err := d.Enqueue(context.Background(), dispatch.Request{Person: greetings.Person{Name: "Ann"}}); !errors.Is(err, dispatch.ErrClosed) {
+		t.Errorf("Enqueue after Close = %v, want %v", err, dispatch.ErrClosed)
+	}
+}
@@ -0,0 +1,80 @@
+package greetings
+
+// EmojiMode controls whether and how greetings carry an emoji.
+type EmojiMode int
+
+const (
+	// EmojiNone leaves emoji out. It is the default.
+	EmojiNone EmojiMode = iota
+
+	// EmojiUnicode adds the locale's emoji, such as 👋 or 🎉.
+	EmojiUnicode
+
+	// EmojiText adds a plain-text stand-in, such as "o/" for 👋, for
+	// terminals and channels that cannot render emoji.
+	EmojiText
+)
+
+// defaultEmoji is used for catalog entries that do not name one.
+const defaultEmoji = "👋"
+
+// emojiText maps the emoji greetings use to plain-text stand-ins.
+var emojiText = map[string]string{
+	"👋":              "o/",
+	"🎉":              `\o/`,
+	"🙇":              "m(_ _)m",
+	"👾":              ">_",
+	"🏴\u200d☠\ufe0f": "o==[]::::>",
+}
+
+// WithEmoji turns the locale's emoji on or off. With it on, catalog
+// greetings end in the emoji ("Hi, Ann. Welcome! 👋") and text templates
+// can place it themselves with {{.Emoji}}.
+func WithEmoji(on bool) Option {
+	if on {
+		return WithEmojiMode(EmojiUnicode)
+	}
+	return WithEmojiMode(EmojiNone)
+}
+
+// WithEmojiMode selects how emoji are rendered; see EmojiMode.
+func WithEmojiMode(mode EmojiMode) Option {
+	return func(g *Greeter) error {
+		g.emojiMode = mode
+		g.emojiSet = true
+		return nil
+	}
+}
+
+// WithEmojiAllowlist restricts greetings to the given emoji. An emoji the
+// locale would use that is not on the list is left out.
+func WithEmojiAllowlist(emoji ...string) Option {
+	return func(g *Greeter) error {
+		g.emojiAllowed = make(map[string]bool, len(emoji))
+		for _, e := range emoji {
+			g.emojiAllowed[e] = true
+		}
+		return nil
+	}
+}
+
+// emoji returns the decoration for the Greeter's locale according to its
+// emoji settings, or "" for none.
+func (g *Greeter) emoji() string {
+
+	if g.emojiMode == EmojiNone {
+		return ""
+	}
+	e := g.message.Emoji
+	if e == "" {
+		e = defaultEmoji
+	}
+	if g.emojiAllowed != nil && !g.emojiAllowed[e] {
+		return ""
+	}
+	if g.emojiMode == EmojiText {
+		return emojiText[e]
+	}
+
+	return e
+}
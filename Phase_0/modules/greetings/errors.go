@@ -0,0 +1,155 @@
+package greetings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrEmptyName is returned when a greeting is requested for an empty name.
+	ErrEmptyName = newError(InvalidName, "greetings: empty name")
+
+	// ErrNameTooLong is returned when a name exceeds the length limit.
+	ErrNameTooLong = newError(InvalidName, "greetings: name too long")
+
+	// ErrInvalidUTF8 is returned when a name is not valid UTF-8.
+	ErrInvalidUTF8 = newError(InvalidName, "greetings: name is not valid UTF-8")
+
+	// ErrUnsafeName is returned by a Greeter in SanitizeReject mode for a
+	// name holding control characters or terminal escape sequences.
+	ErrUnsafeName = newError(InvalidName, "greetings: name contains control characters")
+
+	// ErrFilteredName is returned by a Greeter whose Filter rejects a name.
+	ErrFilteredName = newError(InvalidName, "greetings: name rejected by filter")
+
+	// ErrDuplicateName is reported by Hellos, Greeter.Hellos and
+	// Greeter.HellosCtx when a name appears more than once.
+	ErrDuplicateName = newError(InvalidName, "greetings: duplicate name")
+
+	// ErrNoNames is returned when a group greeting is requested for nobody.
+	ErrNoNames = newError(InvalidName, "greetings: no names")
+
+	// ErrRateLimited is returned by TryGreet when the Greeter's rate limit
+	// has no tokens left.
+	ErrRateLimited = newError(ProviderUnavailable, "greetings: rate limited")
+
+	// ErrUnknownLocale is reported when no catalog exists for a locale.
+	ErrUnknownLocale = newError(UnknownLocale, "greetings: unknown locale")
+
+	// ErrUnknownStyle is reported when no style is registered under a name.
+	ErrUnknownStyle = newError(InvalidConfig, "greetings: unknown style")
+
+	// ErrUnknownTheme is reported when no theme is registered under a name.
+	ErrUnknownTheme = newError(InvalidConfig, "greetings: unknown theme")
+)
+
+// Code classifies the errors of the package, so that servers can map them
+// to protocol status codes without matching every sentinel error.
+type Code int
+
+const (
+	// Internal is an error the package cannot classify, such as one
+	// returned by a custom Provider.
+	Internal Code = iota
+
+	// InvalidName means the name or names to greet were rejected: empty,
+	// too long, not UTF-8, unsafe, filtered, duplicated or missing.
+	InvalidName
+
+	// UnknownLocale means no catalog entry serves the locale.
+	UnknownLocale
+
+	// TemplateError means a template or message format did not parse or
+	// failed to render.
+	TemplateError
+
+	// ProviderUnavailable means the provider cannot greet right now: it
+	// was rate limited or failed transiently (see IsTransient), so trying
+	// again later may succeed.
+	ProviderUnavailable
+
+	// InvalidConfig means the Greeter was configured wrongly, such as with
+	// an unknown style or an invalid option value.
+	InvalidConfig
+
+	// Canceled means the caller's context was done before the greeting
+	// was; errors.Is tells context.Canceled from
+	// context.DeadlineExceeded.
+	Canceled
+)
+
+var codeNames = [...]string{
+	Internal:            "internal",
+	InvalidName:         "invalid_name",
+	UnknownLocale:       "unknown_locale",
+	TemplateError:       "template_error",
+	ProviderUnavailable: "provider_unavailable",
+	InvalidConfig:       "invalid_config",
+	Canceled:            "canceled",
+}
+
+// String returns the snake_case name of c, as used in API error bodies.
+func (c Code) String() string {
+	if c < 0 || int(c) >= len(codeNames) {
+		return fmt.Sprintf("Code(%d)", int(c))
+	}
+	return codeNames[c]
+}
+
+// Error is an error of the package together with its Code. Every error a
+// Greeter returns has one in its chain, so
+//
+//	var e *greetings.Error
+//	if errors.As(err, &e) && e.Code == greetings.InvalidName { ... }
+//
+// always finds it; CodeOf is the shorthand. The sentinel errors such as
+// ErrEmptyName are *Error values themselves, so errors.Is keeps working.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// CodeOf returns the Code of the first *Error in err's chain. Errors from
+// elsewhere are Canceled when they are context errors, ProviderUnavailable
+// when they are transient and Internal otherwise.
+func CodeOf(err error) Code {
+	var e *Error
+	switch {
+	case errors.As(err, &e):
+		return e.Code
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return Canceled
+	case IsTransient(err):
+		return ProviderUnavailable
+	default:
+		return Internal
+	}
+}
+
+// coded returns err with an *Error in its chain: err itself if it has one
+// already, or err wrapped in one with the code CodeOf gives it, or c when
+// that is Internal. coded(c, nil) is nil.
+func coded(c Code, err error) error {
+	var e *Error
+	if err == nil || errors.As(err, &e) {
+		return err
+	}
+	if code := CodeOf(err); code != Internal {
+		c = code
+	}
+	return &Error{Code: c, Err: err}
+}
+
+// newError returns a sentinel *Error with code c and message msg.
+func newError(c Code, msg string) error {
+	return &Error{Code: c, Err: errors.New(msg)}
+}
@@ -0,0 +1,202 @@
+// Package experiments splits greeting traffic between variants for A/B
+// tests. An Experiment assigns every request to a bucket by hashing a key,
+// the recipients' names by default or a user ID carried in the context,
+// so each person keeps seeing the same variant. The served arm is
+// reported in the Greeting's Experiment and Variant fields:
+//
+//	e, err := experiments.New("welcome-copy", nil,
+//		experiments.Variant{Name: "control", Percent: 50},
+//		experiments.Variant{Name: "warm", Percent: 50, Template: "So glad you're here, %v!"},
+//	)
+//	...
+//	g, err := greetings.New(experiments.With(e))
+package experiments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"example.com/greetings"
+)
+
+// Variant is one arm of an Experiment.
+type Variant struct {
+	// Name identifies the variant in the Greeting and in analytics.
+	Name string
+
+	// Percent is the share of traffic, 0 to 100, the variant receives.
+	Percent int
+
+	// Template, when set, replaces the message with a complete fmt format
+	// holding one %v verb for the name. Provider, when set instead, renders
+	// the greeting itself. A variant with neither is a control: the
+	// greeting is served unchanged but still reported.
+	Template string
+	Provider greetings.Provider
+}
+
+// KeyFunc returns the key that buckets a request. Requests with the same
+// key always get the same variant.
+type KeyFunc func(ctx context.Context, req greetings.Request) string
+
+// ByName buckets requests by their recipients' names, ignoring case. It
+// is the default KeyFunc.
+func ByName(_ context.Context, req greetings.Request) string {
+	names := make([]string, len(req.Recipients))
+	for i, p := range req.Recipients {
+		names[i] = strings.ToLower(p.Name)
+	}
+	return strings.Join(names, "\x00")
+}
+
+// ByUserID buckets requests by the user ID in their context (see
+// WithUserID), falling back to ByName for requests without one.
+func ByUserID(ctx context.Context, req greetings.Request) string {
+	if id, ok := UserID(ctx); ok {
+		return "id\x00" + id
+	}
+	return ByName(ctx, req)
+}
+
+type userIDKey struct{}
+
+// WithUserID returns a copy of ctx carrying the user ID id, for ByUserID.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// UserID returns the user ID stored in ctx by WithUserID.
+func UserID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(userIDKey{}).(string)
+	return id, ok
+}
+
+// Experiment splits traffic between its variants. Requests that fall in
+// no variant, when the percentages add up to less than 100, are served
+// unchanged and unreported. An Experiment is safe for concurrent use.
+type Experiment struct {
+	name     string
+	key      KeyFunc
+	variants []Variant
+}
+
+// New returns the experiment called name, bucketing requests with key
+// (ByName when nil) into variants. It fails for an empty or duplicate
+// variant name, a percentage outside 0 to 100, a total over 100, or a
+// template without exactly one %v verb.
+func New(name string, key KeyFunc, variants ...Variant) (*Experiment, error) {
+
+	if name == "" {
+		return nil, errors.New("experiments: empty experiment name")
+	}
+	if key == nil {
+		key = ByName
+	}
+	var errs []error
+	seen := make(map[string]bool)
+	total := 0
+	for i, v := range variants {
+		switch {
+		case v.Name == "":
+			errs = append(errs, fmt.Errorf("experiments: %s: variants[%d]: empty name", name, i))
+		case seen[v.Name]:
+			errs = append(errs, fmt.Errorf("experiments: %s: duplicate variant %q", name, v.Name))
+		}
+		seen[v.Name] = true
+		if v.Percent < 0 || v.Percent > 100 {
+			errs = append(errs, fmt.Errorf("experiments: %s: variant %q: percent %d out of range", name, v.Name, v.Percent))
+		}
+		total += v.Percent
+		if verbs := strings.ReplaceAll(v.Template, "%%", ""); v.Template != "" &&
+			(strings.Count(verbs, "%v") != 1 || strings.Count(verbs, "%") != 1) {
+			errs = append(errs, fmt.Errorf("experiments: %s: variant %q: template %q must contain exactly one %%v verb", name, v.Name, v.Template))
+		}
+	}
+	if total > 100 {
+		errs = append(errs, fmt.Errorf("experiments: %s: percentages add up to %d", name, total))
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return &Experiment{name: name, key: key, variants: variants}, nil
+}
+
+// Name returns the experiment's name.
+func (e *Experiment) Name() string {
+	return e.name
+}
+
+// Assign returns the variant key buckets into, or false when it falls
+// outside the experiment. The bucket depends on the experiment's name as
+// well as key, so concurrent experiments split traffic independently.
+func (e *Experiment) Assign(key string) (Variant, bool) {
+
+	h := fnv.New64a()
+	h.Write([]byte(e.name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	bucket := int(h.Sum64() % 100)
+
+	for _, v := range e.variants {
+		if bucket < v.Percent {
+			return v, true
+		}
+		bucket -= v.Percent
+	}
+
+	return Variant{}, false
+}
+
+// With enrolls the Greeter's greetings in e; it is a shorthand for
+// greetings.Use(e.Middleware).
+func With(e *Experiment) greetings.Option {
+	return greetings.Use(e.Middleware)
+}
+
+// Middleware serves each request with the variant it is assigned to,
+// asking next for control variants and template variants, and records
+// the variant in the Greeting.
+func (e *Experiment) Middleware(next greetings.Provider) greetings.Provider {
+	return greetings.ProviderFunc(func(ctx context.Context, req greetings.Request) (greetings.Greeting, error) {
+
+		v, ok := e.Assign(e.key(ctx, req))
+		if !ok {
+			return next.Greet(ctx, req)
+		}
+
+		p := next
+		if v.Provider != nil {
+			p = v.Provider
+		}
+		greeting, err := p.Greet(ctx, req)
+		if err != nil {
+			return greeting, err
+		}
+		if v.Template != "" {
+			name := greeting.Name
+			if name == "" {
+				name = joinNames(req.Recipients)
+			}
+			before, _, _ := strings.Cut(v.Template, "%v")
+			greeting.Salutation = strings.TrimRight(before, " ,、")
+			greeting.Message = fmt.Sprintf(v.Template, name)
+		}
+		greeting.Experiment = e.name
+		greeting.Variant = v.Name
+
+		return greeting, nil
+	})
+}
+
+// joinNames lists the recipients' names for providers that did not.
+func joinNames(people []greetings.Person) string {
+	names := make([]string, len(people))
+	for i, p := range people {
+		names[i] = p.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,147 @@
+package experiments_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"example.com/greetings"
+	"example.com/greetings/experiments"
+)
+
+func TestNewRejectsBadVariants(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		variants []experiments.Variant
+		want     string
+	}{
+		{"", nil, "empty experiment name"},
+		{"x", []experiments.Variant{{Percent: 10}}, "variants[0]: empty name"},
+		{"x", []experiments.Variant{{Name: "a"}, {Name: "a"}}, `duplicate variant "a"`},
+		{"x", []experiments.Variant{{Name: "a", Percent: -1}}, "percent -1 out of range"},
+		{"x", []experiments.Variant{{Name: "a", Percent: 101}}, "percent 101 out of range"},
+		{"x", []experiments.Variant{{Name: "a", Percent: 60}, {Name: "b", Percent: 50}}, "add up to 110"},
+		{"x", []experiments.Variant{{Name: "a", Template: "Hi!"}}, "exactly one %v verb"},
+		{"x", []experiments.Variant{{Name: "a", Template: "Hi %v and %v"}}, "exactly one %v verb"},
+		{"x", []experiments.Variant{{Name: "a", Template: "Hi %s"}}, "exactly one %v verb"},
+	} {
+		_, err := experiments.New(tt.name, nil, tt.variants...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("New(%q, %v) = %v, want an error containing %q", tt.name, tt.variants, err, tt.want)
+		}
+	}
+}
+
+func TestNewAcceptsEscapedPercent(t *testing.T) {
+	if _, err := experiments.New("x", nil, experiments.Variant{Name: "a", Percent: 100, Template: "100%% glad, %v!"}); err != nil {
+		t.Errorf("New with %%%% in the template: %v", err)
+	}
+}
+
+func TestAssign(t *testing.T) {
+
+	all, _ := experiments.New("all", nil, experiments.Variant{Name: "only", Percent: 100})
+	none, _ := experiments.New("none", nil, experiments.Variant{Name: "never", Percent: 0})
+	split, _ := experiments.New("split", nil,
+		experiments.Variant{Name: "a", Percent: 50},
+		experiments.Variant{Name: "b", Percent: 25},
+	)
+	counts := make(map[string]int)
+	for i := range 2000 {
+		key := fmt.Sprint("user-", i)
+		if v, ok := all.Assign(key); !ok || v.Name != "only" {
+			t.Fatalf("all.Assign(%q) = %v, %v, want only", key, v.Name, ok)
+		}
+		if v, ok := none.Assign(key); ok {
+			t.Fatalf("none.Assign(%q) = %v, want no variant", key, v.Name)
+		}
+		v, ok := split.Assign(key)
+		if again, _ := split.Assign(key); again.Name != v.Name {
+			t.Fatalf("split.Assign(%q) gave %q then %q", key, v.Name, again.Name)
+		}
+		if !ok {
+			v.Name = "out"
+		}
+		counts[v.Name]++
+	}
+	for name, want := range map[string]int{"a": 1000, "b": 500, "out": 500} {
+		if got := counts[name]; got < want*8/10 || got > want*12/10 {
+			t.Errorf("%s got %d of 2000 keys, want about %d", name, got, want)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+
+	req := greetings.Request{Recipients: []greetings.Person{{Name: "Ada"}, {Name: "Cy"}}}
+	lower := greetings.Request{Recipients: []greetings.Person{{Name: "ADA"}, {Name: "cy"}}}
+	ctx := context.Background()
+	if experiments.ByName(ctx, req) != experiments.ByName(ctx, lower) {
+		t.Error("ByName depends on case")
+	}
+	if id, ok := experiments.UserID(ctx); ok {
+		t.Errorf("UserID of a bare context = %q, want none", id)
+	}
+	if experiments.ByUserID(ctx, req) != experiments.ByName(ctx, req) {
+		t.Error("ByUserID without a user ID differs from ByName")
+	}
+	uctx := experiments.WithUserID(ctx, "u1")
+	if id, ok := experiments.UserID(uctx); !ok || id != "u1" {
+		t.Errorf("UserID = %q, %v, want u1", id, ok)
+	}
+	other := greetings.Request{Recipients: []greetings.Person{{Name: "Bo"}}}
+	if experiments.ByUserID(uctx, req) != experiments.ByUserID(uctx, other) {
+		t.Error("ByUserID depends on the names when a user ID is set")
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+
+	custom := greetings.ProviderFunc(func(_ context.Context, req greetings.Request) (greetings.Greeting, error) {
+		return greetings.Greeting{Message: "Yo " + req.Recipients[0].Name}, nil
+	})
+	for _, tt := range []struct {
+		name    string
+		variant experiments.Variant
+		message string
+		salut   string
+	}{
+		{"control", experiments.Variant{Name: "control", Percent: 100}, "Hi, Ada. Welcome!", "Hi"},
+		{"template", experiments.Variant{Name: "warm", Percent: 100, Template: "So glad you're here, %v!"}, "So glad you're here, Ada!", "So glad you're here"},
+		{"provider", experiments.Variant{Name: "custom", Percent: 100, Provider: custom}, "Yo Ada", ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := experiments.New("welcome-copy", nil, tt.variant)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g, err := greetings.New(experiments.With(e))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := g.GreetCtx(context.Background(), greetings.Person{Name: "Ada"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Message != tt.message || got.Salutation != tt.salut {
+				t.Errorf("Message, Salutation = %q, %q, want %q, %q", got.Message, got.Salutation, tt.message, tt.salut)
+			}
+			if got.Experiment != "welcome-copy" || got.Variant != tt.variant.Name {
+				t.Errorf("Experiment, Variant = %q, %q, want welcome-copy, %q", got.Experiment, got.Variant, tt.variant.Name)
+			}
+		})
+	}
+}
+
+func TestMiddlewareOutsideExperiment(t *testing.T) {
+	e, _ := experiments.New("held-out", nil, experiments.Variant{Name: "warm", Template: "So glad, %v!"})
+	g, _ := greetings.New(experiments.With(e))
+	got, err := g.GreetCtx(context.Background(), greetings.Person{Name: "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Message != "Hi, Ada. Welcome!" || got.Experiment != "" || got.Variant != "" {
+		t.Errorf("got %q in %q/%q, want the plain, unreported greeting", got.Message, got.Experiment, got.Variant)
+	}
+}
@@ -0,0 +1,82 @@
+package greetings
+
+import (
+	"io"
+	"strings"
+)
+
+// fastPath is a catalog greeting split around the name, for HelloTo to
+// write without formatting: prefix + name + suffix is the message.
+type fastPath struct {
+	prefix, suffix string
+}
+
+// compileFast returns the fast path of a Greeter that renders with the
+// default provider, or nil when any setting could make the result differ
+// from plain catalog formatting. Lenient mode repairs names, so it takes
+// the ordinary path too.
+func (g *Greeter) compileFast() *fastPath {
+
+	simple := len(g.middleware) == 0 && g.textTemplate == nil && g.mode == Strict &&
+		g.honorific == "" && !g.normalize && !g.firstNameOnly &&
+		g.sanitize == SanitizeOff && g.transliterator == nil &&
+		g.filter == nil && g.maxLength <= 0 && !g.isolate
+	if !simple || strings.Contains(g.template, "%%") {
+		return nil
+	}
+	prefix, suffix, ok := strings.Cut(g.template, "%v")
+	if !ok {
+		return nil
+	}
+	suffix += g.punctuation
+	if emoji := g.emoji(); emoji != "" {
+		suffix += " " + emoji
+	}
+
+	return &fastPath{prefix: prefix, suffix: suffix}
+}
+
+// HelloTo writes the same greeting as Hello to w and returns the number of
+// bytes written. For Greeters rendering plain catalog messages, with no
+// middleware, templates, name processing or aliases, it writes the pieces
+// of the message directly and does not allocate when w is an
+// io.StringWriter such as *bufio.Writer or *bytes.Buffer. Other Greeters
+// and writers take the ordinary path.
+func (g *Greeter) HelloTo(w io.Writer, name string) (int, error) {
+
+	sw, ok := w.(io.StringWriter)
+	if g.fast == nil || !ok || g.aliases != nil && g.aliases.Len() > 0 {
+		message, err := g.Hello(name)
+		if err != nil {
+			return 0, err
+		}
+		return io.WriteString(w, message)
+	}
+	if blankName(name) {
+		return 0, ErrEmptyName
+	}
+	if err := ValidateMax(name, g.maxNameLength); err != nil {
+		return 0, err
+	}
+
+	suffix := g.fast.suffix
+	if strings.HasSuffix(name, ".") && strings.HasPrefix(suffix, ".") {
+		suffix = suffix[1:] // as the provider does for "Jr."
+	}
+	var written int
+	for _, s := range [...]string{g.fast.prefix, name, suffix} {
+		n, err := sw.WriteString(s)
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+// HelloTo writes the greeting HelloE would return to w; see
+// Greeter.HelloTo.
+func HelloTo(w io.Writer, name string) (int, error) {
+	return std.HelloTo(w, name)
+}
@@ -0,0 +1,57 @@
+package greetings_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestHelloToMatchesHello(t *testing.T) {
+	greeters := map[string][]greetings.Option{
+		"strict":  nil,
+		"lenient": {greetings.WithMode(greetings.Lenient)},
+		"es":      {greetings.WithLocale("es")},
+		"emoji":   {greetings.WithEmoji(true)},
+	}
+	names := []string{"Alice", "", "   ", "\t", "Alice M.", "José", strings.Repeat("a", 300)}
+
+	for label, opts := range greeters {
+		g, err := greetings.New(opts...)
+		if err != nil {
+			t.Fatalf("%s: New: %v", label, err)
+		}
+		for _, name := range names {
+			want, wantErr := g.Hello(name)
+			var buf bytes.Buffer
+			n, err := g.HelloTo(&buf, name)
+			if fmt.Sprint(err) != fmt.Sprint(wantErr) || greetings.CodeOf(err) != greetings.CodeOf(wantErr) {
+				t.Errorf("%s: HelloTo(%q) error = %v, Hello error = %v", label, name, err, wantErr)
+				continue
+			}
+			if got := buf.String(); got != want || n != len(want) {
+				t.Errorf("%s: HelloTo(%q) = %q (%d bytes), Hello = %q", label, name, got, n, want)
+			}
+		}
+	}
+}
+
+func TestHelloToDoesNotAllocate(t *testing.T) {
+	g, err := greetings.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	buf.Grow(64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		if _, err := g.HelloTo(&buf, "Gladys"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("HelloTo allocates %v times per greeting, want 0", allocs)
+	}
+}
@@ -0,0 +1,185 @@
+package greetings
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Finding is a part of a name a Filter objects to: the bytes
+// name[Start:End], and why.
+type Finding struct {
+	Start, End int
+	Reason     string
+}
+
+// Filter inspects names, typically from signup forms, for content that must
+// not be repeated back, such as slurs or links. Implementations must be
+// safe for concurrent use.
+type Filter interface {
+	Find(name string) []Finding
+}
+
+// FilterFunc adapts an ordinary function to the Filter interface.
+type FilterFunc func(name string) []Finding
+
+// Find returns f(name).
+func (f FilterFunc) Find(name string) []Finding {
+	return f(name)
+}
+
+// FilterAction is what a Greeter does with a name its Filter objects to.
+type FilterAction int
+
+const (
+	// FilterReject fails the greeting with ErrFilteredName.
+	FilterReject FilterAction = iota
+
+	// FilterMask replaces each offending part with asterisks and greets
+	// the rest: "Hi, ****. Welcome!".
+	FilterMask
+)
+
+// WithFilter runs every name through f after normalization and either
+// rejects or masks what it finds. DefaultFilter is a reasonable start.
+func WithFilter(f Filter, action FilterAction) Option {
+	return func(g *Greeter) error {
+		if action != FilterReject && action != FilterMask {
+			return fmt.Errorf("greetings: invalid filter action %d", int(action))
+		}
+		g.filter = f
+		g.filterAction = action
+		return nil
+	}
+}
+
+// Filters returns a Filter reporting the findings of every f.
+func Filters(f ...Filter) Filter {
+	return FilterFunc(func(name string) []Finding {
+		var out []Finding
+		for _, filter := range f {
+			out = append(out, filter.Find(name)...)
+		}
+		return out
+	})
+}
+
+// WordFilter returns a Filter that finds whole words of a name equal to
+// one of words, ignoring case and common digit-for-letter substitutions,
+// so "sh1t" is caught but "Dickens" is not flagged by "dick".
+func WordFilter(words ...string) Filter {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[unleet(strings.ToLower(w))] = true
+	}
+	return FilterFunc(func(name string) []Finding {
+		var out []Finding
+		for _, span := range wordSpans(name) {
+			if set[unleet(strings.ToLower(name[span[0]:span[1]]))] {
+				out = append(out, Finding{span[0], span[1], "offensive word"})
+			}
+		}
+		return out
+	})
+}
+
+// wordSpans returns the start and end byte offsets of the words of s. Word
+// characters are letters, digits and the symbols unleet reads as letters.
+func wordSpans(s string) [][2]int {
+	var spans [][2]int
+	start := -1
+	for i, r := range s + " " {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || r == '@' || r == '$'
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	return spans
+}
+
+// leet undoes common digit-for-letter substitutions.
+var leet = strings.NewReplacer("0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s")
+
+func unleet(s string) string {
+	return leet.Replace(s)
+}
+
+// PatternFilter returns a Filter that finds every match of re and reports
+// it with reason.
+func PatternFilter(re *regexp.Regexp, reason string) Filter {
+	return FilterFunc(func(name string) []Finding {
+		var out []Finding
+		for _, m := range re.FindAllStringIndex(name, -1) {
+			out = append(out, Finding{m[0], m[1], reason})
+		}
+		return out
+	})
+}
+
+// Patterns used by DefaultFilter.
+var (
+	urlPattern   = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.-]*://|www\.)\S+|\b[a-z0-9-]+\.(?:com|net|org|info|biz|io|co|ru|xyz|top|ly)\b`)
+	emailPattern = regexp.MustCompile(`[^\s@]+@[^\s@]+\.[^\s@]+`)
+	phonePattern = regexp.MustCompile(`\+?\d(?:[\s().-]*\d){7,}`)
+)
+
+// defaultWords is a deliberately small list of common English profanity.
+// Services with stricter needs should supply their own list.
+var defaultWords = []string{
+	"fuck", "fucker", "fucking", "motherfucker", "shit", "bullshit",
+	"bitch", "cunt", "asshole", "arsehole", "wanker", "twat", "bollocks",
+	"piss", "cock", "slut", "whore",
+}
+
+// DefaultFilter finds the words of a small built-in profanity list, URLs,
+// email addresses and phone numbers.
+var DefaultFilter = Filters(
+	WordFilter(defaultWords...),
+	PatternFilter(urlPattern, "URL"),
+	PatternFilter(emailPattern, "email address"),
+	PatternFilter(phonePattern, "phone number"),
+)
+
+// applyFilter runs name through the Greeter's filter, returning the name
+// to greet or an error wrapping ErrFilteredName. The error gives the
+// reasons but not the name, which is not fit to repeat.
+func (g *Greeter) applyFilter(name string) (string, error) {
+
+	findings := g.filter.Find(name)
+	if len(findings) == 0 {
+		return name, nil
+	}
+
+	if g.filterAction == FilterReject {
+		var reasons []string
+		for _, f := range findings {
+			if !slices.Contains(reasons, f.Reason) {
+				reasons = append(reasons, f.Reason)
+			}
+		}
+		return "", fmt.Errorf("%w: %s", ErrFilteredName, strings.Join(reasons, ", "))
+	}
+
+	masked := make([]bool, len(name))
+	for _, f := range findings {
+		for i := max(f.Start, 0); i < min(f.End, len(name)); i++ {
+			masked[i] = true
+		}
+	}
+	var b strings.Builder
+	for i, r := range name {
+		if masked[i] {
+			b.WriteByte('*')
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String(), nil
+}
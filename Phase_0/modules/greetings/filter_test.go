@@ -0,0 +1,82 @@
+package greetings_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestDefaultFilterMask(t *testing.T) {
+	g, err := greetings.New(greetings.WithFilter(greetings.DefaultFilter, greetings.FilterMask))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name, want string
+	}{
+		{"Ann Dickens", "Ann Dickens"},
+		{"Cockburn", "Cockburn"},
+		{"shit", "****"},
+		{"Ann Sh1t", "Ann ****"},
+		{"Ann $h!t", "Ann $h!t"},
+		{"Bob BULLSHIT Smith", "Bob ******** Smith"},
+		{"Ann www.spam.example", "Ann ****************"},
+		{"Ann https://x.io/a", "Ann **************"},
+		{"buy-now.com", "***********"},
+		{"ann@example.org", "***************"},
+		{"Ann +1 (555) 123-4567", "Ann *****************"},
+		{"Ann 1234", "Ann 1234"},
+		{"Zoë shït", "Zoë shït"},
+		{"Zoé@exämple.com", "***************"},
+	} {
+		got, err := g.Hello(tt.name)
+		if err != nil {
+			t.Errorf("Hello(%q): %v", tt.name, err)
+			continue
+		}
+		if want := "Hi, " + tt.want + ". Welcome!"; got != want {
+			t.Errorf("Hello(%q) = %q, want %q", tt.name, got, want)
+		}
+	}
+}
+
+func TestFilterReject(t *testing.T) {
+	g, err := greetings.New(greetings.WithFilter(greetings.DefaultFilter, greetings.FilterReject))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := g.Hello("Ann"); got != "Hi, Ann. Welcome!" || err != nil {
+		t.Errorf("Hello(Ann) = %q, %v", got, err)
+	}
+	_, err = g.Hello("shit ann@example.org")
+	if !errors.Is(err, greetings.ErrFilteredName) || greetings.CodeOf(err) != greetings.InvalidName {
+		t.Fatalf("Hello error = %v, want ErrFilteredName", err)
+	}
+	// The domain of the address is also a URL.
+	if msg := err.Error(); msg != "greetings: name rejected by filter: offensive word, URL, email address" {
+		t.Errorf("error %q should give the reasons, once each, and not the name", msg)
+	}
+}
+
+func TestCustomFilters(t *testing.T) {
+	f := greetings.Filters(
+		greetings.WordFilter("Voldemort"),
+		greetings.PatternFilter(regexp.MustCompile(`\d+`), "number"),
+		greetings.FilterFunc(func(name string) []greetings.Finding {
+			return []greetings.Finding{{Start: -5, End: 1, Reason: "clamped"}}
+		}),
+	)
+	g, err := greetings.New(greetings.WithFilter(f, greetings.FilterMask))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := g.Hello("Tom V0ldem0rt 42"); got != "Hi, *om ********* **. Welcome!" || err != nil {
+		t.Errorf("Hello = %q, %v", got, err)
+	}
+
+	if _, err := greetings.New(greetings.WithFilter(f, greetings.FilterAction(7))); greetings.CodeOf(err) != greetings.InvalidConfig {
+		t.Errorf("invalid action: New error = %v, want InvalidConfig", err)
+	}
+}
@@ -0,0 +1,57 @@
+package greetings
+
+import "fmt"
+
+// Formality is the register a greeting is written in. Every built-in
+// locale has all three.
+type Formality int
+
+const (
+	// Neutral is the everyday register: "Hi, Alice. Welcome!". It is the
+	// default.
+	Neutral Formality = iota
+
+	// Casual is for friends: "Hey Alice!".
+	Casual
+
+	// Formal is for letters and ceremonies: "Dear Ms. Alice, welcome."
+	Formal
+)
+
+var formalityNames = [...]string{Neutral: "neutral", Casual: "casual", Formal: "formal"}
+
+// String returns the lower-case name of f.
+func (f Formality) String() string {
+	if f < 0 || int(f) >= len(formalityNames) {
+		return fmt.Sprintf("Formality(%d)", int(f))
+	}
+	return formalityNames[f]
+}
+
+// ParseFormality returns the Formality named s ("casual", "neutral" or
+// "formal").
+func ParseFormality(s string) (Formality, error) {
+	for f, name := range formalityNames {
+		if s == name {
+			return Formality(f), nil
+		}
+	}
+	return Neutral, fmt.Errorf("greetings: unknown formality %q", s)
+}
+
+// WithFormality selects the register the Greeter takes from its catalog.
+// Catalog entries without that register use their neutral message.
+func WithFormality(f Formality) Option {
+	return func(g *Greeter) error {
+		if f < Neutral || f > Formal {
+			return fmt.Errorf("greetings: invalid formality %v", f)
+		}
+		g.formality = f
+		return nil
+	}
+}
+
+// Formality reports the register the Greeter was configured with.
+func (g *Greeter) Formality() Formality {
+	return g.formality
+}
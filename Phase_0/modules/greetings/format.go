@@ -0,0 +1,178 @@
+package greetings
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Format is the markup a Greeter renders greetings in, alongside the plain
+// text in Greeting.Message.
+type Format int
+
+const (
+	// FormatText is plain text only: Greeting.Formatted stays empty. It is
+	// the default.
+	FormatText Format = iota
+
+	// FormatSSML is Speech Synthesis Markup Language for text-to-speech
+	// engines: the name is emphasized and sentences are separated by a
+	// short pause, as in
+	// <speak xml:lang="en">Hi, <emphasis level="moderate">Alice</emphasis>.<break time="300ms"/>Welcome!</speak>.
+	FormatSSML
+
+	// FormatHTML is an HTML fragment safe to inject into web pages: the
+	// message is escaped and the name wrapped in an element set with
+	// WithHTMLName, as in Hi, <strong class="name">Alice</strong>. Welcome!
+	// Right-to-left greetings are wrapped in <span dir="rtl">.
+	FormatHTML
+
+	// FormatMarkdown is Markdown for chat bots: the sentence holding the
+	// name is emphasized as set by WithMarkdownEmphasis and the rest is in
+	// italics, as in **Hi, Alice.** _Welcome!_
+	FormatMarkdown
+
+	// FormatSMS is the message fitted to one text message: 160 GSM-7
+	// characters, or 70 UCS-2 ones when the text needs characters GSM-7
+	// lacks, shortened like WithMaxLength when it is longer. With
+	// WithSMSSplit long messages are instead split into numbered parts,
+	// one per line. Greeting.Segments reports the number of messages.
+	FormatSMS
+
+	// FormatANSI is text colored for terminals with ANSI escape codes: the
+	// salutation in cyan and the name in bold yellow. It is plain text
+	// when standard output is not a terminal or the NO_COLOR environment
+	// variable is set, unless WithColor says otherwise.
+	FormatANSI
+
+	// FormatBanner is the message in large ASCII-art letters for command
+	// line splash screens, five lines high and wrapped between words to
+	// the terminal's width or that set with WithBannerWidth. Letters lose
+	// their diacritics and emoji are dropped; a message the font cannot
+	// spell, such as one in Japanese, or with a word too wide for a line,
+	// stays plain text.
+	FormatBanner
+)
+
+var formatNames = [...]string{
+	FormatText:     "text",
+	FormatSSML:     "ssml",
+	FormatHTML:     "html",
+	FormatMarkdown: "markdown",
+	FormatSMS:      "sms",
+	FormatANSI:     "ansi",
+	FormatBanner:   "banner",
+}
+
+// String returns the lower-case name of f.
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+	return formatNames[f]
+}
+
+// ParseFormat returns the Format named s, such as "text", "ssml",
+// "html", "markdown", "sms", "ansi" or "banner".
+func ParseFormat(s string) (Format, error) {
+	for f, name := range formatNames {
+		if s == name {
+			return Format(f), nil
+		}
+	}
+	return FormatText, fmt.Errorf("greetings: unknown format %q", s)
+}
+
+// WithFormat makes the Greeter render every greeting in f as well as in
+// plain text, storing the result in Greeting.Formatted.
+func WithFormat(f Format) Option {
+	return func(g *Greeter) error {
+		if f < 0 || int(f) >= len(formatNames) {
+			return fmt.Errorf("greetings: invalid format %v", f)
+		}
+		g.format = f
+		return nil
+	}
+}
+
+// Format reports the markup the Greeter renders greetings in.
+func (g *Greeter) Format() Format {
+	return g.format
+}
+
+// render returns greeting's message in the Greeter's format, other than
+// FormatText and FormatSMS.
+func (g *Greeter) render(greeting Greeting) string {
+	switch g.format {
+	case FormatSSML:
+		return ssml(greeting)
+	case FormatHTML:
+		return htmlFragment(greeting, g.htmlElement, g.htmlClass)
+	case FormatMarkdown:
+		return markdown(greeting, g.markdownEmphasis)
+	case FormatANSI:
+		return ansi(greeting, g.color)
+	case FormatBanner:
+		return banner(greeting.Message, g.bannerWidth)
+	}
+	return ""
+}
+
+// segments is a message taken apart for markup: the first sentence is
+// before+name+rest, with name empty when the message does not contain the
+// greeted name, and tail is whatever follows it, leading space included.
+type segments struct {
+	before, name, rest, tail string
+}
+
+// split takes greeting's message apart into segments.
+func split(greeting Greeting) segments {
+
+	var s segments
+	msg := greeting.Message
+	if i := strings.Index(msg, greeting.Name); greeting.Name != "" && i >= 0 {
+		s.before, s.name, msg = msg[:i], greeting.Name, msg[i+len(greeting.Name):]
+	}
+	if i := strings.IndexAny(msg, ".!?。！？"); i >= 0 {
+		for _, r := range msg[i:] {
+			if !strings.ContainsRune(".!?。！？", r) {
+				break
+			}
+			i += len(string(r))
+		}
+		msg, s.tail = msg[:i], msg[i:]
+	}
+	if s.name == "" {
+		s.before = msg
+	} else {
+		s.rest = msg
+	}
+
+	return s
+}
+
+// ssml renders greeting as an SSML document. A pronunciation hint at the
+// end of the message is left out; the IPA, if any, is spoken instead.
+func ssml(greeting Greeting) string {
+
+	greeting.Message = strings.TrimSuffix(greeting.Message, pronunciationHint(greeting))
+	s := split(greeting)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<speak xml:lang="%s">`, html.EscapeString(greeting.Locale))
+	b.WriteString(html.EscapeString(s.before))
+	switch {
+	case s.name != "" && greeting.Pronunciation.IPA != "":
+		fmt.Fprintf(&b, `<emphasis level="moderate"><phoneme alphabet="ipa" ph="%s">%s</phoneme></emphasis>`,
+			html.EscapeString(greeting.Pronunciation.IPA), html.EscapeString(s.name))
+	case s.name != "":
+		fmt.Fprintf(&b, `<emphasis level="moderate">%s</emphasis>`, html.EscapeString(s.name))
+	}
+	b.WriteString(html.EscapeString(s.rest))
+	if tail := strings.TrimLeft(s.tail, " "); tail != "" {
+		b.WriteString(`<break time="300ms"/>`)
+		b.WriteString(html.EscapeString(tail))
+	}
+	b.WriteString("</speak>")
+
+	return b.String()
+}
@@ -1,3 +1,37 @@
 module example.com/greetings
 
 go 1.25.5
+
+require golang.org/x/text v0.40.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require golang.org/x/sync v0.22.0
+
+require github.com/coder/websocket v1.8.15
+
+require github.com/BurntSushi/toml v1.5.0
+
+require example.com/collections v0.0.0-00010101000000-000000000000
+
+require example.com/stringsx v0.0.0-00010101000000-000000000000
+
+require example.com/concurrent v0.0.0-00010101000000-000000000000
+
+require example.com/pipeline v0.0.0-00010101000000-000000000000
+
+require example.com/calculator v0.0.0-00010101000000-000000000000
+
+require example.com/nameio v0.0.0-00010101000000-000000000000
+
+replace example.com/collections => ./../../../Phase_1/modules/collections
+
+replace example.com/stringsx => ./../../../Phase_1/modules/stringsx
+
+replace example.com/concurrent => ./../../../Phase_2/modules/concurrent
+
+replace example.com/pipeline => ./../../../Phase_2/modules/pipeline
+
+replace example.com/calculator => ./../../../Phase_3/modules/calculator
+
+replace example.com/nameio => ./../../../Phase_4/modules/nameio
@@ -0,0 +1,430 @@
+package greetings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+)
+
+// Greeter produces greetings according to its configuration. Build one with
+// New and keep it around instead of relying on the package-level functions.
+// A Greeter is safe for concurrent use once constructed.
+type Greeter struct {
+	template    string
+	locale      string
+	punctuation string
+	clock       Clock
+	catalog     Catalog
+	message     Message
+	formality   Formality
+	honorific   string
+	normalize   bool
+	titleCase   bool
+	oxfordComma bool
+	duplicates  Duplicates
+
+	firstNameOnly bool
+	sanitize      SanitizeMode
+	aliases       *Aliases
+	format        Format
+	leapDay       LeapDay
+
+	// isolate wraps names in bidi isolates; isolateSet records an
+	// explicit WithBidiIsolation, without which New isolates names in
+	// right-to-left locales.
+	isolate    bool
+	isolateSet bool
+
+	transliterator Transliterator
+	filter         Filter
+	filterAction   FilterAction
+
+	// htmlElement and htmlClass wrap names in FormatHTML output.
+	htmlElement string
+	htmlClass   string
+
+	markdownEmphasis string
+	smsSplit         bool
+	pronunciation    bool
+
+	// color turns on FormatANSI colors; colorSet records an explicit
+	// WithColor, without which New detects the terminal.
+	color    bool
+	colorSet bool
+
+	// bannerWidth is the width FormatBanner wraps at; New reads the
+	// terminal's unless WithBannerWidth sets it.
+	bannerWidth int
+
+	maxNameLength int
+	maxLength     int
+	workers       int
+	errorPolicy   ErrorPolicy
+	mode          Mode
+	fallbackName  string
+	logRedactor   Redactor
+
+	// translator translates greetings to translateTo, the locale asked
+	// for, when the catalog has no entry for it (see WithTranslation).
+	translator  TranslationProvider
+	translateTo string
+
+	// ellipsis ends greetings cut short by maxLength; ellipsisSet records
+	// an explicit WithEllipsis, which may set it to "".
+	ellipsis    string
+	ellipsisSet bool
+
+	emojiMode    EmojiMode
+	emojiSet     bool
+	emojiAllowed map[string]bool
+
+	// rand picks RandomHello's template when set; see WithRand.
+	rand *lockedRand
+
+	// theme, when set, rewords the locale's catalog entry.
+	theme *Theme
+
+	// textTemplate, when set, replaces template and punctuation. style
+	// names it when it came from the style registry.
+	textTemplate *Template
+	style        string
+	vars         map[string]any
+
+	// templateSet and punctuationSet record explicit options, which take
+	// precedence over the locale's catalog entry.
+	templateSet    bool
+	punctuationSet bool
+
+	// provider renders the greetings; by default a templateProvider built
+	// from the settings above. New wraps it in middleware.
+	provider   Provider
+	middleware []Middleware
+
+	// breaker, when set, guards a provider from WithProvider and falls
+	// back to the templateProvider while open.
+	breaker *Breaker
+
+	// fast, when set, lets HelloTo skip the provider; see compileFast.
+	fast *fastPath
+
+	// localeConfig records the config setting locale came from, if any,
+	// so New can say which key named an unknown locale.
+	localeConfig *ConfigError
+}
+
+// Option configures a Greeter.
+type Option func(*Greeter) error
+
+const defaultLocale = "en"
+
+// New returns a Greeter configured by opts. Without options it greets like
+// HelloE: "Hi, Gladys. Welcome!".
+func New(opts ...Option) (*Greeter, error) {
+
+	g := newGreeter()
+	for _, opt := range opts {
+		if err := opt(g); err != nil {
+			return nil, coded(InvalidConfig, err)
+		}
+	}
+	if err := g.init(); err != nil {
+		return nil, coded(InvalidConfig, err)
+	}
+
+	return g, nil
+}
+
+// newGreeter returns a Greeter holding the defaults, for options to adjust.
+func newGreeter() *Greeter {
+	return &Greeter{
+		locale:        defaultLocale,
+		clock:         SystemClock,
+		aliases:       aliases,
+		catalog:       builtin,
+		maxNameLength: DefaultMaxNameLength,
+		workers:       1,
+		fallbackName:  DefaultFallbackName,
+		htmlElement:   defaultHTMLElement,
+		htmlClass:     defaultHTMLClass,
+
+		markdownEmphasis: defaultMarkdownEmphasis,
+	}
+}
+
+// init resolves the configured Greeter's locale and builds its provider.
+func (g *Greeter) init() error {
+
+	locale, msg, err := g.catalog.Resolve(g.locale)
+	if g.translatable(err) {
+		g.translateTo, err = g.locale, nil
+	}
+	if err != nil && !(g.mode == Lenient && errors.Is(err, ErrUnknownLocale)) {
+		if c := g.localeConfig; c != nil {
+			return &ConfigError{File: c.File, Key: c.Key, Err: err}
+		}
+		return err
+	}
+	g.locale = locale
+	if g.theme != nil {
+		msg = g.theme.message(msg)
+		if msg.Emoji != "" && !g.emojiSet {
+			g.emojiMode = EmojiUnicode
+		}
+	}
+	g.message = msg
+	if !g.isolateSet {
+		g.isolate = IsRTL(g.locale) || IsRTL(g.translateTo)
+	}
+	if g.format == FormatANSI && !g.colorSet {
+		g.color = colorTerminal()
+	}
+	if g.format == FormatBanner && g.bannerWidth == 0 {
+		g.bannerWidth = terminalWidth()
+	}
+	v := msg.variant(g.formality)
+	if !g.templateSet {
+		g.template = v.Template
+	}
+	if !g.punctuationSet {
+		g.punctuation = v.Punctuation
+	}
+	switch {
+	case g.provider == nil:
+		g.fast = g.compileFast()
+		if g.provider, err = g.templateProvider(); err != nil {
+			return err
+		}
+	case g.breaker != nil:
+		fallback, err := g.templateProvider()
+		if err != nil {
+			return err
+		}
+		g.provider = g.breaker.guard(g.provider, fallback)
+	}
+	g.provider = chain(g.provider, g.middleware)
+
+	return nil
+}
+
+// templateProvider returns the built-in provider for the Greeter's
+// resolved settings.
+func (g *Greeter) templateProvider() (*templateProvider, error) {
+
+	tp := &templateProvider{
+		message:      g.message,
+		template:     g.template,
+		punctuation:  g.punctuation,
+		textTemplate: g.textTemplate,
+		emoji:        g.emoji(),
+		oxfordComma:  g.oxfordComma,
+		leapDay:      g.leapDay,
+		isolate:      g.isolate,
+		lenient:      g.mode == Lenient,
+	}
+	// The catalog's whole-message greetings only stand in for its own
+	// neutral template.
+	if g.formality == Neutral && !g.templateSet && !g.punctuationSet {
+		tp.birthday, tp.welcomeBack = g.message.Birthday, g.message.WelcomeBack
+		if g.message.Group != "" {
+			var err error
+			if tp.group, err = ParseMessageFormat(g.locale, g.message.Group); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return tp, nil
+}
+
+// WithTemplate sets the fmt format used to build the message. It must contain
+// exactly one %v verb for the name and should not end in punctuation, which
+// is added separately (see WithPunctuation).
+func WithTemplate(template string) Option {
+	return func(g *Greeter) error {
+		if err := checkFormat(template); err != nil {
+			return fmt.Errorf("greetings: %w", err)
+		}
+		g.template = template
+		g.templateSet = true
+		return nil
+	}
+}
+
+// WithLocale sets the language the Greeter speaks, as a BCP 47 tag like "en".
+// The locale's catalog entry supplies the template and punctuation unless
+// WithTemplate or WithPunctuation override them. New walks the tag's
+// fallback chain (see Catalog.Resolve) and fails with ErrUnknownLocale
+// when nothing in it is in the catalog.
+func WithLocale(locale string) Option {
+	return func(g *Greeter) error {
+		if locale == "" {
+			return errors.New("greetings: empty locale")
+		}
+		g.locale = locale
+		g.localeConfig = nil
+		return nil
+	}
+}
+
+// WithCatalog replaces the built-in catalog the Greeter takes its messages
+// from. New fails if the catalog does not pass Catalog.Validate.
+func WithCatalog(c Catalog) Option {
+	return func(g *Greeter) error {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+		g.catalog = c
+		return nil
+	}
+}
+
+// WithPunctuation sets the text appended to every message, "!" in English.
+// An empty string leaves the message unterminated.
+func WithPunctuation(punctuation string) Option {
+	return func(g *Greeter) error {
+		g.punctuation = punctuation
+		g.punctuationSet = true
+		return nil
+	}
+}
+
+// Locale reports the catalog locale the Greeter speaks, which may be a
+// fallback of the one passed to WithLocale: "pt" for "pt-BR".
+func (g *Greeter) Locale() string {
+	return g.locale
+}
+
+// Hello returns a greeting for the named person. It fails when the name
+// does not pass validation (see Validate).
+func (g *Greeter) Hello(name string) (string, error) {
+	return g.HelloCtx(context.Background(), name)
+}
+
+// HelloCtx is like Hello but gives up once ctx is done.
+func (g *Greeter) HelloCtx(ctx context.Context, name string) (string, error) {
+
+	greeting, err := g.GreetCtx(ctx, Person{Name: name})
+	if err != nil {
+		return "", err
+	}
+
+	return greeting.Message, nil
+}
+
+// Greet returns a structured greeting for the named person.
+func (g *Greeter) Greet(name string) (Greeting, error) {
+	return g.GreetCtx(context.Background(), Person{Name: name})
+}
+
+// GreetPerson is like Greet but also addresses p by title, placed before or
+// after the name as the locale dictates: "Hi, Dr. Ada. Welcome!".
+func (g *Greeter) GreetPerson(p Person) (Greeting, error) {
+	return g.GreetCtx(context.Background(), p)
+}
+
+// GreetCtx is the method every other greeting method funnels into. It
+// returns ctx.Err() if ctx is done before the greeting is rendered, so
+// greetings that come to depend on remote services honor cancellation and
+// deadlines without changing the Greeter's API.
+func (g *Greeter) GreetCtx(ctx context.Context, p Person) (Greeting, error) {
+	return g.greetPerson(ctx, p, nil)
+}
+
+// greetPerson greets p with extra template variables vars on top of the
+// Greeter's.
+func (g *Greeter) greetPerson(ctx context.Context, p Person, vars map[string]any) (Greeting, error) {
+
+	if err := ctx.Err(); err != nil {
+		return Greeting{}, coded(Canceled, err)
+	}
+
+	p, err := g.normalizePerson(ctx, p)
+	if err != nil {
+		return Greeting{}, coded(InvalidName, err)
+	}
+	p, err = g.validatePerson(ctx, p)
+	if err != nil {
+		return Greeting{}, coded(InvalidName, err)
+	}
+
+	return g.greet(ctx, []Person{p}, vars)
+}
+
+// greet renders and finishes a greeting for the already validated
+// recipients.
+func (g *Greeter) greet(ctx context.Context, recipients []Person, vars map[string]any) (Greeting, error) {
+
+	greeting, err := g.provide(ctx, recipients, vars)
+	if err != nil {
+		return Greeting{}, err
+	}
+
+	return g.finish(greeting), nil
+}
+
+// provide asks the Greeter's provider to greet the already validated
+// recipients, and fills in whatever the provider left out of the result:
+// the render stage of a greeting.
+func (g *Greeter) provide(ctx context.Context, recipients []Person, vars map[string]any) (Greeting, error) {
+
+	req := Request{
+		Recipients: recipients,
+		Locale:     g.locale,
+		Formality:  g.formality,
+		Style:      g.style,
+		Time:       g.clock.Now(),
+		Vars:       g.vars,
+	}
+	if len(vars) > 0 {
+		req.Vars = make(map[string]any, len(g.vars)+len(vars))
+		maps.Copy(req.Vars, g.vars)
+		maps.Copy(req.Vars, vars)
+	}
+	greeting, err := g.provider.Greet(ctx, req)
+	if err != nil {
+		return Greeting{}, coded(Internal, err)
+	}
+	if greeting.Name == "" {
+		greeting.Name = req.names(g.message, g.oxfordComma)
+	}
+	if greeting.Locale == "" {
+		greeting.Locale = req.Locale
+	}
+	if greeting.GeneratedAt.IsZero() {
+		greeting.GeneratedAt = req.Time
+	}
+	if g.pronunciation && len(recipients) == 1 {
+		greeting.Pronunciation = recipients[0].Pronunciation
+	}
+
+	return greeting, nil
+}
+
+// finish shortens and formats a rendered greeting according to the
+// Greeter's settings: the format stage of a greeting.
+func (g *Greeter) finish(greeting Greeting) Greeting {
+
+	if greeting.Skipped {
+		return greeting
+	}
+	if hint := pronunciationHint(greeting); g.pronunciation && hint != "" {
+		greeting.Message += hint
+	}
+	if g.maxLength > 0 {
+		ellipsis := defaultEllipsis
+		if g.ellipsisSet {
+			ellipsis = g.ellipsis
+		}
+		greeting.Message = truncate(greeting.Message, greeting.Name, g.maxLength, ellipsis)
+	}
+	switch g.format {
+	case FormatText:
+	case FormatSMS:
+		greeting.Formatted, greeting.Segments = g.sms(greeting)
+	default:
+		greeting.Formatted = g.render(greeting)
+	}
+
+	return greeting
+}
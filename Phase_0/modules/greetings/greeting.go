@@ -1,7 +1,16 @@
 package greetings
 
-import "fmt"
+import (
+	"context"
+	"errors"
+	"fmt"
+)
 
+// std is the Greeter behind the package-level functions.
+var std, _ = New()
+
+// Hello returns a greeting for the named person. An empty name falls back
+// to a generic welcome; use HelloE to treat it as an error instead.
 func Hello(name string) string {
 
 	nameText := ""
@@ -18,3 +27,39 @@ func Hello(name string) string {
 	message := fmt.Sprintf(template, nameText)
 	return message
 }
+
+// HelloE returns a greeting for the named person, or ErrEmptyName when
+// name is empty so the caller can decide how to handle missing input.
+func HelloE(name string) (string, error) {
+	return std.Hello(name)
+}
+
+// HelloCtx is like HelloE but gives up once ctx is done, returning
+// ctx.Err().
+func HelloCtx(ctx context.Context, name string) (string, error) {
+	return std.HelloCtx(ctx, name)
+}
+
+// Hellos returns a map that associates each of the named people with a
+// greeting message. Bad entries (empty or repeated names) do not stop the
+// batch: they are left out of the map and reported together in the
+// returned error, which supports errors.Is for each cause.
+func Hellos(names []string) (map[string]string, error) {
+
+	messages := make(map[string]string, len(names))
+	var errs []error
+	for i, name := range names {
+		if _, seen := messages[name]; seen {
+			errs = append(errs, fmt.Errorf("names[%d] %q: %w", i, name, ErrDuplicateName))
+			continue
+		}
+		message, err := HelloE(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("names[%d]: %w", i, err))
+			continue
+		}
+		messages[name] = message
+	}
+
+	return messages, errors.Join(errs...)
+}
@@ -0,0 +1,209 @@
+package greetings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Greeting is a rendered greeting together with what went into it, for
+// services that consume greetings as data rather than parsing strings.
+type Greeting struct {
+	// Salutation is the opening of the message, such as "Hi" or "Bonjour".
+	// It is empty for text/template greetings, whose shape is unknown.
+	Salutation string
+
+	// Name is the name of the person greeted.
+	Name string
+
+	// Message is the complete greeting.
+	Message string
+
+	// Formatted is Message in the markup selected with WithFormat, such
+	// as SSML. It is empty for plain-text greetings.
+	Formatted string
+
+	// Segments is the number of text messages Formatted takes with
+	// FormatSMS, and zero otherwise.
+	Segments int
+
+	// Locale is the catalog locale the message was rendered in. After
+	// fallback it can differ from the requested one ("pt" for "pt-BR"),
+	// telling callers which catalog was actually used.
+	Locale string
+
+	// GeneratedAt is when the greeting was rendered.
+	GeneratedAt time.Time
+
+	// Pronunciation is how to say Name, when the Greeter was built with
+	// WithPronunciation and the recipient's pronunciation is known.
+	Pronunciation Pronunciation
+
+	// Experiment and Variant name the experiment arm that served the
+	// greeting, for analytics (see package experiments). Both are empty
+	// for greetings outside any experiment.
+	Experiment string
+	Variant    string
+
+	// Skipped reports that the greeting was not given because its
+	// recipient was greeted too recently (see Dedup). Message is empty.
+	Skipped bool
+
+	// MachineTranslated reports that Message was machine-translated
+	// because the catalog had no entry for Locale (see WithTranslation).
+	MachineTranslated bool
+}
+
+// String returns the greeting's message.
+func (g Greeting) String() string {
+	return g.Message
+}
+
+// Format implements fmt.Formatter. %v and %s print the message, with any
+// width and flags applied to it, and %q prints it quoted; %+v prints every
+// field by name and %#v the greeting as a Go literal, for logs and
+// debugging.
+func (g Greeting) Format(f fmt.State, verb rune) {
+
+	switch {
+	case verb == 'v' && f.Flag('+'):
+		fmt.Fprintf(f, "{Salutation:%q Name:%q Message:%q", g.Salutation, g.Name, g.Message)
+		if g.Formatted != "" {
+			fmt.Fprintf(f, " Formatted:%q", g.Formatted)
+		}
+		fmt.Fprintf(f, " Locale:%q GeneratedAt:%s", g.Locale, g.GeneratedAt.Format(time.RFC3339Nano))
+		if g.Experiment != "" || g.Variant != "" {
+			fmt.Fprintf(f, " Experiment:%q Variant:%q", g.Experiment, g.Variant)
+		}
+		if g.Skipped {
+			fmt.Fprint(f, " Skipped:true")
+		}
+		if g.MachineTranslated {
+			fmt.Fprint(f, " MachineTranslated:true")
+		}
+		fmt.Fprint(f, "}")
+	case verb == 'v' && f.Flag('#'):
+		// fields has Greeting's fields but not its methods, so printing
+		// it does not recurse into Format.
+		type fields Greeting
+		s := fmt.Sprintf("%#v", fields(g))
+		_, rest, _ := strings.Cut(s, "{")
+		fmt.Fprint(f, "greetings.Greeting{"+rest)
+	case verb == 'v' || verb == 's' || verb == 'q':
+		if verb == 'v' {
+			verb = 's'
+		}
+		fmt.Fprintf(f, fmt.FormatString(f, verb), g.Message)
+	default:
+		fmt.Fprintf(f, "%%!%c(greetings.Greeting=%s)", verb, g.Message)
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler. The text form of a
+// Greeting is its message, which suits flags, plain-text logs and map
+// keys; use JSON to keep the other fields.
+func (g Greeting) MarshalText() ([]byte, error) {
+	return []byte(g.Message), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, setting the message
+// and clearing the other fields. It fails for empty text, like
+// UnmarshalJSON does for a greeting without a message.
+func (g *Greeting) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		return errors.New("greetings: empty greeting text")
+	}
+	*g = Greeting{Message: string(text)}
+	return nil
+}
+
+// greetingJSON is the wire form of a Greeting.
+type greetingJSON struct {
+	Salutation  string     `json:"salutation,omitempty"`
+	Name        string     `json:"name"`
+	Message     string     `json:"message"`
+	Formatted   string     `json:"formatted,omitempty"`
+	Segments    int        `json:"segments,omitempty"`
+	Locale      string     `json:"locale"`
+	GeneratedAt *time.Time `json:"generated_at,omitempty"`
+	Experiment  string     `json:"experiment,omitempty"`
+	Variant     string     `json:"variant,omitempty"`
+
+	Pronunciation *Pronunciation `json:"pronunciation,omitempty"`
+	Skipped       bool           `json:"skipped,omitempty"`
+
+	MachineTranslated bool `json:"machine_translated,omitempty"`
+}
+
+// MarshalJSON encodes g as a JSON object with snake_case keys. GeneratedAt
+// is written in UTC as RFC 3339 and left out when it is the zero time.
+func (g Greeting) MarshalJSON() ([]byte, error) {
+
+	v := greetingJSON{
+		Salutation: g.Salutation,
+		Name:       g.Name,
+		Message:    g.Message,
+		Formatted:  g.Formatted,
+		Segments:   g.Segments,
+		Locale:     g.Locale,
+		Experiment: g.Experiment,
+		Variant:    g.Variant,
+		Skipped:    g.Skipped,
+
+		MachineTranslated: g.MachineTranslated,
+	}
+	if !g.GeneratedAt.IsZero() {
+		t := g.GeneratedAt.UTC()
+		v.GeneratedAt = &t
+	}
+	if !g.Pronunciation.IsZero() {
+		v.Pronunciation = &g.Pronunciation
+	}
+
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a Greeting written by MarshalJSON. It fails when
+// the message is missing, since a greeting without one is meaningless
+// unless it was skipped.
+func (g *Greeting) UnmarshalJSON(data []byte) error {
+
+	var v greetingJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if v.Message == "" && !v.Skipped {
+		return errors.New("greetings: greeting JSON has no message")
+	}
+
+	*g = Greeting{
+		Salutation: v.Salutation,
+		Name:       v.Name,
+		Message:    v.Message,
+		Formatted:  v.Formatted,
+		Segments:   v.Segments,
+		Locale:     v.Locale,
+		Experiment: v.Experiment,
+		Variant:    v.Variant,
+		Skipped:    v.Skipped,
+
+		MachineTranslated: v.MachineTranslated,
+	}
+	if v.GeneratedAt != nil {
+		g.GeneratedAt = *v.GeneratedAt
+	}
+	if v.Pronunciation != nil {
+		g.Pronunciation = *v.Pronunciation
+	}
+
+	return nil
+}
+
+// salutation returns the text of a fmt template before the name, without
+// trailing separators: "Hi" for "Hi, %v. Welcome".
+func salutation(template string) string {
+	before, _, _ := strings.Cut(template, "%v")
+	return strings.TrimRight(before, " ,、")
+}
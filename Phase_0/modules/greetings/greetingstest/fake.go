@@ -0,0 +1,193 @@
+// Package greetingstest provides helpers for testing code that greets
+// people, without building real Greeters and catalogs.
+//
+// A FakeGreeter satisfies greetings.Interface, records every call and
+// answers with canned greetings:
+//
+//	fake := &greetingstest.FakeGreeter{
+//		Greetings: map[string]greetings.Greeting{"Ada": {Message: "Welcome, Ada!"}},
+//		Errors:    map[string]error{"": greetings.ErrEmptyName},
+//	}
+//	svc := NewService(fake)
+//	...
+//	if calls := fake.Calls(); len(calls) != 1 || calls[0].Names[0] != "Ada" { ... }
+//
+// Golden checks a catalog's greetings against golden files, so changes to
+// translations show up as reviewable diffs.
+// FakeClock stands in for the wall clock.
+package greetingstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"example.com/greetings"
+)
+
+// Call is one recorded call to a FakeGreeter.
+type Call struct {
+	Method string   // the method called, like "Hello" or "GreetCtx"
+	Names  []string // the names it was asked to greet
+	Person greetings.Person
+}
+
+// FakeGreeter is a greetings.Interface that answers from its fields. Set
+// them before use; the zero FakeGreeter greets everyone with "Hello,
+// name!". A FakeGreeter is safe for concurrent use.
+type FakeGreeter struct {
+	// Greetings holds the canned greeting for each name, or for the names
+	// of a group joined with ", ". Empty fields are filled in as for
+	// names with no entry.
+	Greetings map[string]greetings.Greeting
+
+	// Errors holds the error to return for each name. It is consulted
+	// before Greetings.
+	Errors map[string]error
+
+	// Err, when set, is returned by every call.
+	Err error
+
+	// LocaleTag is what Locale reports and greetings carry; "en" when
+	// empty.
+	LocaleTag string
+
+	// Time is the GeneratedAt of every greeting; the zero time when
+	// unset, so results compare easily.
+	Time time.Time
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ greetings.Interface = (*FakeGreeter)(nil)
+
+// Calls returns the calls made so far, oldest first.
+func (f *FakeGreeter) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Call(nil), f.calls...)
+}
+
+// Reset forgets the recorded calls.
+func (f *FakeGreeter) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+func (f *FakeGreeter) record(c Call) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, c)
+}
+
+// answer returns the canned response for key, addressed to name.
+func (f *FakeGreeter) answer(key, name string) (greetings.Greeting, error) {
+
+	if f.Err != nil {
+		return greetings.Greeting{}, f.Err
+	}
+	if err, ok := f.Errors[key]; ok {
+		return greetings.Greeting{}, err
+	}
+
+	greeting := f.Greetings[key]
+	if greeting.Name == "" {
+		greeting.Name = name
+	}
+	if greeting.Message == "" {
+		greeting.Message = "Hello, " + name + "!"
+	}
+	if greeting.Locale == "" {
+		greeting.Locale = f.Locale()
+	}
+	if greeting.GeneratedAt.IsZero() {
+		greeting.GeneratedAt = f.Time
+	}
+
+	return greeting, nil
+}
+
+// Hello records the call and returns the canned message for name.
+func (f *FakeGreeter) Hello(name string) (string, error) {
+	f.record(Call{Method: "Hello", Names: []string{name}, Person: greetings.Person{Name: name}})
+	greeting, err := f.answer(name, name)
+	return greeting.Message, err
+}
+
+// HelloCtx is like Hello but fails with ctx.Err() once ctx is done.
+func (f *FakeGreeter) HelloCtx(ctx context.Context, name string) (string, error) {
+	f.record(Call{Method: "HelloCtx", Names: []string{name}, Person: greetings.Person{Name: name}})
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	greeting, err := f.answer(name, name)
+	return greeting.Message, err
+}
+
+// Hellos records one call for the whole batch and answers each name like
+// Hello, joining the errors the way Greeter.Hellos does.
+func (f *FakeGreeter) Hellos(names []string) ([]string, error) {
+
+	f.record(Call{Method: "Hellos", Names: append([]string(nil), names...)})
+	messages := make([]string, len(names))
+	var errs []error
+	for i, name := range names {
+		greeting, err := f.answer(name, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("names[%d]: %w", i, err))
+			continue
+		}
+		messages[i] = greeting.Message
+	}
+
+	return messages, errors.Join(errs...)
+}
+
+// HelloGroup records the call and returns the canned message for the
+// names joined with ", ". It fails with greetings.ErrNoNames for none.
+func (f *FakeGreeter) HelloGroup(names []string) (string, error) {
+
+	f.record(Call{Method: "HelloGroup", Names: append([]string(nil), names...)})
+	if len(names) == 0 {
+		return "", greetings.ErrNoNames
+	}
+	joined := strings.Join(names, ", ")
+	greeting, err := f.answer(joined, joined)
+
+	return greeting.Message, err
+}
+
+// Greet records the call and returns the canned greeting for name.
+func (f *FakeGreeter) Greet(name string) (greetings.Greeting, error) {
+	f.record(Call{Method: "Greet", Names: []string{name}, Person: greetings.Person{Name: name}})
+	return f.answer(name, name)
+}
+
+// GreetPerson records the call and returns the canned greeting for p's
+// name.
+func (f *FakeGreeter) GreetPerson(p greetings.Person) (greetings.Greeting, error) {
+	f.record(Call{Method: "GreetPerson", Names: []string{p.Name}, Person: p})
+	return f.answer(p.Name, p.Name)
+}
+
+// GreetCtx is like GreetPerson but fails with ctx.Err() once ctx is done.
+func (f *FakeGreeter) GreetCtx(ctx context.Context, p greetings.Person) (greetings.Greeting, error) {
+	f.record(Call{Method: "GreetCtx", Names: []string{p.Name}, Person: p})
+	if err := ctx.Err(); err != nil {
+		return greetings.Greeting{}, err
+	}
+	return f.answer(p.Name, p.Name)
+}
+
+// Locale reports LocaleTag, or "en" when it is empty.
+func (f *FakeGreeter) Locale() string {
+	if f.LocaleTag == "" {
+		return "en"
+	}
+	return f.LocaleTag
+}
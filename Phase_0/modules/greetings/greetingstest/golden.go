@@ -0,0 +1,140 @@
+package greetingstest
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+)
+
+// update is the -update flag of the test binary. Packages that call
+// Golden must not define their own.
+var update = flag.Bool("update", false, "rewrite greetingstest golden files instead of comparing against them")
+
+// GoldenTime is the time every greeting rendered by Golden is generated
+// at, so styles that print the time stay reproducible.
+var GoldenTime = time.Date(2025, time.March, 1, 9, 30, 0, 0, time.UTC)
+
+// Golden renders names with every locale of catalog, in every register,
+// as a group and in every registered style, and compares the result with
+// the golden files in dir, one <locale>.golden per locale. A nil catalog
+// means the built-in one, and no names means "Gladys". Each difference is
+// reported with t.Errorf. Run the tests with -update to write the files
+// instead, then review the diff:
+//
+//	func TestCatalog(t *testing.T) {
+//		greetingstest.Golden(t, "testdata", nil)
+//	}
+func Golden(t testing.TB, dir string, catalog greetings.Catalog, names ...string) {
+
+	t.Helper()
+	if len(names) == 0 {
+		names = []string{"Gladys"}
+	}
+	locales := greetings.Locales()
+	if catalog != nil {
+		locales = catalog.Locales()
+	}
+
+	for _, locale := range locales {
+		got, err := render(catalog, locale, names)
+		if err != nil {
+			t.Errorf("greetingstest: %s: %v", locale, err)
+			continue
+		}
+		path := filepath.Join(dir, locale+".golden")
+		if *update {
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Errorf("greetingstest: %v (run with -update to create it)", err)
+			continue
+		}
+		if line, g, w, ok := firstDiff(got, want); !ok {
+			t.Errorf("greetingstest: %s:%d:\n got: %s\nwant: %s\n(run with -update to accept the new output)", path, line, g, w)
+		}
+	}
+}
+
+// render returns the golden file contents for one locale.
+func render(catalog greetings.Catalog, locale string, names []string) ([]byte, error) {
+
+	base := []greetings.Option{
+		greetings.WithLocale(locale),
+		greetings.WithClock(NewFakeClock(GoldenTime)),
+	}
+	if catalog != nil {
+		base = append(base, greetings.WithCatalog(catalog))
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# locale %s\n", locale)
+	line := func(label string, opts ...greetings.Option) error {
+		g, err := greetings.New(append(base[:len(base):len(base)], opts...)...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		for _, name := range names {
+			message, err := g.Hello(name)
+			if err != nil {
+				return fmt.Errorf("%s: %q: %w", label, name, err)
+			}
+			fmt.Fprintf(&b, "%s: %s\n", label, message)
+		}
+		if len(names) > 1 {
+			message, err := g.HelloGroup(names)
+			if err != nil {
+				return fmt.Errorf("%s group: %w", label, err)
+			}
+			fmt.Fprintf(&b, "%s group: %s\n", label, message)
+		}
+		return nil
+	}
+
+	for _, f := range []greetings.Formality{greetings.Casual, greetings.Neutral, greetings.Formal} {
+		if err := line(f.String(), greetings.WithFormality(f)); err != nil {
+			return nil, err
+		}
+	}
+	for _, style := range greetings.Styles() {
+		if err := line("style "+style, greetings.WithStyle(style)); err != nil {
+			return nil, err
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// firstDiff compares got and want line by line and returns the first line
+// that differs, or ok when they are equal.
+func firstDiff(got, want []byte) (line int, g, w string, ok bool) {
+
+	if bytes.Equal(got, want) {
+		return 0, "", "", true
+	}
+	gl := strings.Split(string(got), "\n")
+	wl := strings.Split(string(want), "\n")
+	for i := 0; ; i++ {
+		switch {
+		case i >= len(gl):
+			return i + 1, "(end of output)", wl[i], false
+		case i >= len(wl):
+			return i + 1, gl[i], "(end of file)", false
+		case gl[i] != wl[i]:
+			return i + 1, gl[i], wl[i], false
+		}
+	}
+}
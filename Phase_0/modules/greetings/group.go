@@ -0,0 +1,200 @@
+package greetings
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"example.com/greetings/names"
+)
+
+// WithOxfordComma makes group greetings put a separator before the final
+// conjunction when there are three or more names: "Alice, Bob, and Carol".
+func WithOxfordComma() Option {
+	return func(g *Greeter) error {
+		g.oxfordComma = true
+		return nil
+	}
+}
+
+// Duplicates says what group greetings do when several recipients would be
+// greeted by the same name, which WithFirstNameOnly makes likely.
+type Duplicates int
+
+const (
+	// KeepDuplicates greets everyone as they are: "Alice and Alice". It is
+	// the default.
+	KeepDuplicates Duplicates = iota
+
+	// Disambiguate tells namesakes apart by what else their full names
+	// say: surname initials, then whole surnames, then suffixes, as in
+	// "Alice K. and Alice M." or "Alice Jr. and Alice Sr.". Namesakes
+	// whose full names do not differ are left alone.
+	Disambiguate
+
+	// Dedupe greets each name once: "Alice and Bob" for Alice, Bob and
+	// Alice.
+	Dedupe
+)
+
+// WithDuplicates selects how group greetings handle recipients greeted by
+// the same name.
+func WithDuplicates(d Duplicates) Option {
+	return func(g *Greeter) error {
+		if d < KeepDuplicates || d > Dedupe {
+			return fmt.Errorf("greetings: invalid duplicates mode %d", int(d))
+		}
+		g.duplicates = d
+		return nil
+	}
+}
+
+// joinNames lists names the way the message's language does: "Alice",
+// "Alice and Bob", "Alice, Bob and Carol".
+func (m Message) joinNames(names []string, oxfordComma bool) string {
+
+	sep, conj := m.Separator, m.Conjunction
+	if sep == "" {
+		sep = ", "
+	}
+	if conj == "" {
+		conj = " and "
+	}
+
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0]
+	case 2:
+		return names[0] + conj + names[1]
+	}
+	last := len(names) - 1
+	if oxfordComma {
+		conj = strings.TrimRight(sep, " ") + conj
+	}
+	return strings.Join(names[:last], sep) + conj + names[last]
+}
+
+// HelloGroup returns one greeting addressed to all the named people, such
+// as "Hi, Alice, Bob and Carol. Welcome!". It fails with ErrNoNames for an
+// empty slice, and with the validation error of the first bad name.
+func (g *Greeter) HelloGroup(names []string) (string, error) {
+
+	greeting, err := g.GreetGroupCtx(context.Background(), names)
+	if err != nil {
+		return "", err
+	}
+
+	return greeting.Message, nil
+}
+
+// GreetGroupCtx is the structured, context-aware form of HelloGroup. The
+// returned Greeting's Name is the joined list of names.
+func (g *Greeter) GreetGroupCtx(ctx context.Context, names []string) (Greeting, error) {
+
+	if err := ctx.Err(); err != nil {
+		return Greeting{}, coded(Canceled, err)
+	}
+	if len(names) == 0 {
+		return Greeting{}, ErrNoNames
+	}
+
+	recipients := make([]Person, len(names))
+	for i, name := range names {
+		name, err := g.prepareName(name)
+		if err != nil {
+			return Greeting{}, coded(InvalidName, fmt.Errorf("names[%d]: %w", i, err))
+		}
+		recipients[i] = Person{Name: name, Title: g.honorific}
+	}
+	switch g.duplicates {
+	case Disambiguate:
+		g.disambiguate(recipients, names)
+	case Dedupe:
+		recipients = dedupe(recipients)
+	}
+
+	return g.greet(ctx, recipients, nil)
+}
+
+// dedupe drops recipients greeted by the same name as an earlier one.
+func dedupe(recipients []Person) []Person {
+	seen := make(map[string]bool, len(recipients))
+	return slices.DeleteFunc(recipients, func(p Person) bool {
+		dup := seen[p.Name]
+		seen[p.Name] = true
+		return dup
+	})
+}
+
+// disambiguate renames recipients that share a name after what tells
+// their full names, given in the same order, apart.
+func (g *Greeter) disambiguate(recipients []Person, full []string) {
+
+	groups := make(map[string][]int)
+	for i, p := range recipients {
+		groups[p.Name] = append(groups[p.Name], i)
+	}
+
+	for name, group := range groups {
+		if len(group) < 2 {
+			continue
+		}
+		parsed := make([]names.Name, len(group))
+		for j, i := range group {
+			parsed[j] = names.Parse(g.normalizeSpace(full[i]))
+		}
+		for _, part := range []func(names.Name) string{surnameInitial, surname, surnameSuffix} {
+			labels := make([]string, len(group))
+			for j := range group {
+				labels[j] = strings.TrimSpace(name + " " + part(parsed[j]))
+			}
+			if len(slices.Compact(slices.Sorted(slices.Values(labels)))) == len(labels) {
+				for j, i := range group {
+					recipients[i].Name = labels[j]
+				}
+				break
+			}
+		}
+	}
+}
+
+// normalizeSpace prepares a full name for parsing the way the Greeter
+// prepares names: collapsed and title-cased when it normalizes them.
+func (g *Greeter) normalizeSpace(name string) string {
+	if !g.normalize {
+		return name
+	}
+	name = Normalize(name)
+	if g.titleCase {
+		name = TitleCase(name, g.locale)
+	}
+	return name
+}
+
+// surnameInitial returns "K." for the surname Kim, or for "van Kampen".
+func surnameInitial(n names.Name) string {
+	words := strings.Fields(n.Surname)
+	if len(words) == 0 {
+		return ""
+	}
+	r, _ := utf8.DecodeRuneInString(words[len(words)-1])
+	return string(unicode.ToUpper(r)) + "."
+}
+
+func surname(n names.Name) string {
+	return n.Surname
+}
+
+func surnameSuffix(n names.Name) string {
+	return strings.TrimSpace(n.Surname + " " + n.Suffix)
+}
+
+// HelloGroup greets all the named people at once with the default Greeter.
+func HelloGroup(names []string) (string, error) {
+	return std.HelloGroup(names)
+}
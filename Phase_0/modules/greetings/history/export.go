@@ -0,0 +1,100 @@
+package history
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Filter selects history entries. Its zero value selects them all.
+type Filter struct {
+	// Name, when set, selects entries for that name only.
+	Name string
+
+	// From and To, when set, select entries at or after From and before
+	// To.
+	From, To time.Time
+}
+
+// Match reports whether f selects e.
+func (f Filter) Match(e Entry) bool {
+	switch {
+	case f.Name != "" && e.Name != f.Name:
+		return false
+	case !f.From.IsZero() && e.Time.Before(f.From):
+		return false
+	case !f.To.IsZero() && !e.Time.Before(f.To):
+		return false
+	}
+	return true
+}
+
+// Query returns the entries of s that f selects, in the order they took
+// place.
+func Query(ctx context.Context, s Store, f Filter) ([]Entry, error) {
+
+	entries, err := s.Entries(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []Entry
+	for _, e := range entries {
+		if f.Match(e) {
+			selected = append(selected, e)
+		}
+	}
+
+	return selected, nil
+}
+
+// csvHeader names the columns ExportCSV writes.
+var csvHeader = []string{"time", "name", "locale", "message", "experiment", "variant"}
+
+// ExportCSV writes the entries of s that f selects to w as CSV, with a
+// header row naming the columns time, name, locale, message, experiment
+// and variant. Times are RFC 3339 with nanoseconds.
+func ExportCSV(ctx context.Context, w io.Writer, s Store, f Filter) error {
+
+	entries, err := Query(ctx, s, f)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		record := []string{e.Time.Format(time.RFC3339Nano), e.Name, e.Locale, e.Message, e.Experiment, e.Variant}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// ExportJSONL writes the entries of s that f selects to w as JSON Lines,
+// one Entry per line: the format of FileStore, so an export can be opened
+// as a history file of its own.
+func ExportJSONL(ctx context.Context, w io.Writer, s Store, f Filter) error {
+
+	entries, err := Query(ctx, s, f)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileStore is a Store that persists history to a file of JSON Lines, one
+// Entry per line, and keeps an index in memory for queries. Records are
+// appended, so the file survives crashes up to the last complete line.
+type FileStore struct {
+	mu   sync.Mutex
+	mem  MemoryStore
+	file *os.File
+	enc  *json.Encoder
+}
+
+// OpenFile opens the history file at path, creating it if needed, and
+// loads the entries it already holds.
+func OpenFile(path string) (*FileStore, error) {
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileStore{file: f, enc: json.NewEncoder(f)}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("history: %s:%d: %w", path, line, err)
+		}
+		s.mem.add(e)
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Record implements Store. The entry is written to the file before it is
+// visible to queries.
+func (s *FileStore) Record(ctx context.Context, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrClosed
+	}
+	if err := s.enc.Encode(e); err != nil {
+		return err
+	}
+	return s.mem.Record(ctx, e)
+}
+
+// LastGreeted implements Store.
+func (s *FileStore) LastGreeted(ctx context.Context, name string) (Entry, bool, error) {
+	return s.mem.LastGreeted(ctx, name)
+}
+
+// Count implements Counter.
+func (s *FileStore) Count(ctx context.Context, name string) (int, error) {
+	return s.mem.Count(ctx, name)
+}
+
+// Entries implements Store.
+func (s *FileStore) Entries(ctx context.Context) ([]Entry, error) {
+	return s.mem.Entries(ctx)
+}
+
+// Close closes the underlying file. Later Records fail with ErrClosed.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return ErrClosed
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
@@ -0,0 +1,194 @@
+// Package history records the greetings a Greeter issues so it can tell
+// first visits from repeat ones. A Store keeps the records; MemoryStore,
+// FileStore and SQLStore cover tests, single processes and shared
+// databases. A Recorder, installed with With or greetings.Use, writes to
+// the store and has returning visitors greeted with "Welcome back, Alice!"
+// or its translation.
+// ExportCSV and ExportJSONL write the records out for audits and data
+// pipelines, and Redacted keeps them free of names.
+package history
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"example.com/greetings"
+)
+
+// ErrClosed is returned by stores used after Close.
+var ErrClosed = errors.New("history: store closed")
+
+// Entry records one greeting.
+type Entry struct {
+	Name    string    `json:"name"`
+	Locale  string    `json:"locale"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+
+	// Experiment and Variant are the experiment arm that served the
+	// greeting, if any (see greetings.Greeting).
+	Experiment string `json:"experiment,omitempty"`
+	Variant    string `json:"variant,omitempty"`
+}
+
+// Store keeps greeting history. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Record adds e to the history.
+	Record(ctx context.Context, e Entry) error
+
+	// LastGreeted returns the most recent entry for name, with false if
+	// name was never greeted.
+	LastGreeted(ctx context.Context, name string) (Entry, bool, error)
+
+	// Entries returns every entry in the order they took place.
+	Entries(ctx context.Context) ([]Entry, error)
+}
+
+// Counter is implemented by stores that can count a name's entries
+// without reading the whole history; see Count.
+type Counter interface {
+	// Count returns the number of entries for name.
+	Count(ctx context.Context, name string) (int, error)
+}
+
+// Count returns the number of times s has name greeted. It asks s when s
+// is a Counter and otherwise counts s's entries.
+func Count(ctx context.Context, s Store, name string) (int, error) {
+	if c, ok := s.(Counter); ok {
+		return c.Count(ctx, name)
+	}
+	entries, err := Query(ctx, s, Filter{Name: name})
+	return len(entries), err
+}
+
+// DefaultWelcomeBack is the template Recorders used to greet returning
+// visitors with.
+//
+// Deprecated: Recorders now leave welcome-backs to the catalog, which
+// has them in every locale. Pass DefaultWelcomeBack to
+// Recorder.SetWelcomeBack for the old English-only greeting.
+const DefaultWelcomeBack = "Welcome back, {{.Name}}!"
+
+// Recorder is a greetings Middleware that records every greeting to a
+// Store. When a single recipient has been greeted before, it tells the
+// provider when in Request.LastSeen, so the Greeter welcomes them back
+// with their locale's catalog message (see greetings.Message.WelcomeBack),
+// in whatever language they are greeted in. It also counts the
+// recipient's visits for Request.VisitCount, this one included.
+//
+// Templates can branch on the visit themselves:
+//
+//	g, err := greetings.New(history.With(store), greetings.WithTextTemplate(
+//		`{{if .Returning}}Welcome back for the {{.Ordinal .VisitCount}} time{{else}}Welcome{{end}}, {{.Name}}!`+
+//			`{{if .DaysSince}} It's been {{.DaysSince}} {{plural .DaysSince "day" "days"}}.{{end}}`))
+type Recorder struct {
+	store   Store
+	welcome *greetings.Template
+}
+
+// NewRecorder returns a Recorder writing to s and leaving welcome-backs to
+// the catalog.
+func NewRecorder(s Store) *Recorder {
+	return &Recorder{store: s}
+}
+
+// With records the Greeter's greetings to s; it is a shorthand for
+// greetings.Use(NewRecorder(s).Middleware).
+func With(s Store) greetings.Option {
+	return greetings.Use(NewRecorder(s).Middleware)
+}
+
+// Dedup returns a greetings.Dedup that skips anyone s has a greeting for
+// within window, so the window holds across every process sharing s. It
+// only reads s; install it outside a Recorder writing to s:
+//
+//	greetings.Use(history.Dedup(store, time.Hour).Middleware, history.NewRecorder(store).Middleware)
+func Dedup(s Store, window time.Duration) *greetings.Dedup {
+	return greetings.NewDedupFunc(window, func(ctx context.Context, name string) (time.Time, bool, error) {
+		e, ok, err := s.LastGreeted(ctx, name)
+		return e.Time, ok, err
+	})
+}
+
+// SetWelcomeBack makes r greet returning visitors with a template of its
+// own instead of the catalog's message, in every locale; it accepts the
+// same fields as greetings.WithTextTemplate. An empty text goes back to
+// the catalog.
+func (r *Recorder) SetWelcomeBack(text string) error {
+	if text == "" {
+		r.welcome = nil
+		return nil
+	}
+	t, err := greetings.ParseTemplate("welcome-back", text)
+	if err != nil {
+		return err
+	}
+	r.welcome = t
+	return nil
+}
+
+// Middleware looks up the history of the request's recipient, asks next
+// for a greeting with LastSeen set, swaps in r's own welcome-back template
+// on repeat visits if it has one and records the result. Skipped greetings are not
+// recorded. Errors from the store fail the greeting.
+func (r *Recorder) Middleware(next greetings.Provider) greetings.Provider {
+	return greetings.ProviderFunc(func(ctx context.Context, req greetings.Request) (greetings.Greeting, error) {
+
+		returning := false
+		if len(req.Recipients) == 1 {
+			last, seen, err := r.store.LastGreeted(ctx, req.Recipients[0].Name)
+			if err != nil {
+				return greetings.Greeting{}, err
+			}
+			if seen {
+				returning = true
+				req.LastSeen = last.Time
+			}
+			count, err := Count(ctx, r.store, req.Recipients[0].Name)
+			if err != nil {
+				return greetings.Greeting{}, err
+			}
+			req.VisitCount = count + 1
+		}
+
+		greeting, err := next.Greet(ctx, req)
+		if err != nil || greeting.Skipped {
+			return greeting, err
+		}
+		if returning && r.welcome != nil {
+			p := req.Recipients[0]
+			pronouns := p.Pronouns
+			if pronouns == (greetings.Pronouns{}) {
+				pronouns = greetings.PronounsThey
+			}
+			message, err := r.welcome.Execute(greetings.TemplateData{
+				Name:      p.Name,
+				Title:     p.Title,
+				Pronouns:  pronouns,
+				Time:      req.Time,
+				Locale:    req.Locale,
+				Returning: true,
+				LastSeen:  req.LastSeen,
+				DaysSince: req.DaysSince(),
+
+				VisitCount: req.VisitCount,
+			})
+			if err != nil {
+				return greetings.Greeting{}, err
+			}
+			greeting.Message = message
+		}
+
+		for _, p := range req.Recipients {
+			e := Entry{Name: p.Name, Locale: req.Locale, Message: greeting.Message, Time: req.Time,
+				Experiment: greeting.Experiment, Variant: greeting.Variant}
+			if err := r.store.Record(ctx, e); err != nil {
+				return greetings.Greeting{}, err
+			}
+		}
+
+		return greeting, nil
+	})
+}
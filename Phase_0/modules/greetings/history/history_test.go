@@ -0,0 +1,117 @@
+package history_test
+
+import (
+	"context"
+	"testing"
+
+	"example.com/greetings"
+	"example.com/greetings/history"
+)
+
+func TestRecorderWelcomesBackInLocale(t *testing.T) {
+	for _, tt := range []struct {
+		locale      string
+		first, next string
+	}{
+		{"en", "Hi, Ada. Welcome!", "Welcome back, Ada!"},
+		{"fr", "Bonjour, Ada. Bienvenue\u00a0!", "Bon retour, Ada\u00a0!"},
+		{"es", "Hola, Ada. Te damos la bienvenida.", "¡Hola de nuevo, Ada!"},
+		{"pt-BR", "Olá, Ada. Boas-vindas!", "Olá de novo, Ada!"},
+	} {
+		g, err := greetings.New(greetings.WithLocale(tt.locale), history.With(history.NewMemoryStore()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range []string{tt.first, tt.next, tt.next} {
+			got, err := g.Hello("Ada")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("%s: greeting %d = %q, want %q", tt.locale, i+1, got, want)
+			}
+		}
+	}
+}
+
+func TestRecorderKeepsGreeterTemplate(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		opts        []greetings.Option
+		first, next string
+	}{
+		{"template", []greetings.Option{greetings.WithTemplate("Howdy %v")}, "Howdy Ada!", "Howdy Ada!"},
+		{"punctuation", []greetings.Option{greetings.WithPunctuation("?")}, "Hi, Ada. Welcome?", "Hi, Ada. Welcome?"},
+		{"formal", []greetings.Option{greetings.WithFormality(greetings.Formal)}, "Dear Ada, welcome.", "Dear Ada, welcome."},
+		{"all", []greetings.Option{
+			greetings.WithTemplate("Howdy %v"),
+			greetings.WithFormality(greetings.Formal),
+			greetings.WithEmoji(true),
+		}, "Howdy Ada. 👋", "Howdy Ada. 👋"},
+		{"emoji", []greetings.Option{greetings.WithEmoji(true)}, "Hi, Ada. Welcome! 👋", "Welcome back, Ada! 👋"},
+	} {
+		g, err := greetings.New(append(tt.opts, history.With(history.NewMemoryStore()))...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, want := range []string{tt.first, tt.next} {
+			got, err := g.Hello("Ada")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("%s: greeting %d = %q, want %q", tt.name, i+1, got, want)
+			}
+		}
+	}
+}
+
+func TestRecorderWelcomeBackTemplate(t *testing.T) {
+	store := history.NewMemoryStore()
+	r := history.NewRecorder(store)
+	if err := r.SetWelcomeBack(`{{.Ordinal .VisitCount}} visit for {{.Name}}; missed {{.Pronouns.Object}}.`); err != nil {
+		t.Fatal(err)
+	}
+	g, err := greetings.New(greetings.WithLocale("fr"), greetings.Use(r.Middleware))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []string{
+		"Bonjour, Ada. Bienvenue\u00a0!",
+		"2e visit for Ada; missed them.",
+		"3e visit for Ada; missed them.",
+	} {
+		got, err := g.Hello("Ada")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("greeting %d = %q, want %q", i+1, got, want)
+		}
+	}
+
+	entries, err := store.Entries(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[1].Message != "2e visit for Ada; missed them." {
+		t.Errorf("recorded %+v", entries)
+	}
+}
+
+func TestRecorderGroupIsNotWelcomedBack(t *testing.T) {
+	g, err := greetings.New(history.With(history.NewMemoryStore()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		greeting, err := g.GreetGroupCtx(context.Background(), []string{"Ada", "Bob"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if greeting.Message != "Hi, Ada and Bob. Welcome!" {
+			t.Errorf("GreetGroup = %q", greeting.Message)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package history
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps history in memory. The zero value is
+// an empty store ready to use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	entries []Entry
+	last    map[string]int // name to index in entries
+	counts  map[string]int // name to number of entries
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return new(MemoryStore)
+}
+
+// Record implements Store.
+func (s *MemoryStore) Record(_ context.Context, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.add(e)
+	return nil
+}
+
+// add appends e. s.mu must be held.
+func (s *MemoryStore) add(e Entry) {
+	if s.last == nil {
+		s.last = make(map[string]int)
+		s.counts = make(map[string]int)
+	}
+	s.entries = append(s.entries, e)
+	s.counts[e.Name]++
+	if i, ok := s.last[e.Name]; !ok || !e.Time.Before(s.entries[i].Time) {
+		s.last[e.Name] = len(s.entries) - 1
+	}
+}
+
+// LastGreeted implements Store.
+func (s *MemoryStore) LastGreeted(_ context.Context, name string) (Entry, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	i, ok := s.last[name]
+	if !ok {
+		return Entry{}, false, nil
+	}
+	return s.entries[i], true, nil
+}
+
+// Count implements Counter.
+func (s *MemoryStore) Count(_ context.Context, name string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.counts[name], nil
+}
+
+// Entries implements Store.
+func (s *MemoryStore) Entries(context.Context) ([]Entry, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.entries), nil
+}
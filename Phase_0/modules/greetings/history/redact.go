@@ -0,0 +1,63 @@
+package history
+
+import (
+	"context"
+	"strings"
+
+	"example.com/greetings"
+)
+
+// RedactedStore is a Store that keeps names only in redacted form, for
+// history of real users that must not hold personal data. Record replaces
+// the entry's name, in Name and wherever it appears in Message, with what
+// Redact makes of it; locale, time and experiment are kept as they are.
+// LastGreeted redacts the name it is asked about the same way, so with a
+// deterministic Redactor such as greetings.HashName, welcome-backs and
+// history.Dedup keep working. Filters and exports see only redacted
+// names.
+//
+// A group greeting is recorded once per recipient with the whole message,
+// so each entry redacts its own recipient's name but not the others'; nor
+// can names a template altered, say to upper case, be found. Set
+// DropMessages where that matters.
+type RedactedStore struct {
+	Store  Store
+	Redact greetings.Redactor
+
+	// DropMessages records entries without their message at all.
+	DropMessages bool
+}
+
+// Redacted returns a RedactedStore keeping the history in s and redacting
+// names with r.
+func Redacted(s Store, r greetings.Redactor) *RedactedStore {
+	return &RedactedStore{Store: s, Redact: r}
+}
+
+// Record implements Store.
+func (s *RedactedStore) Record(ctx context.Context, e Entry) error {
+	redacted := s.Redact(e.Name)
+	switch {
+	case s.DropMessages:
+		e.Message = ""
+	case e.Name != "":
+		e.Message = strings.ReplaceAll(e.Message, e.Name, redacted)
+	}
+	e.Name = redacted
+	return s.Store.Record(ctx, e)
+}
+
+// LastGreeted implements Store.
+func (s *RedactedStore) LastGreeted(ctx context.Context, name string) (Entry, bool, error) {
+	return s.Store.LastGreeted(ctx, s.Redact(name))
+}
+
+// Count implements Counter, counting the entries for the redacted name.
+func (s *RedactedStore) Count(ctx context.Context, name string) (int, error) {
+	return Count(ctx, s.Store, s.Redact(name))
+}
+
+// Entries implements Store.
+func (s *RedactedStore) Entries(ctx context.Context) ([]Entry, error) {
+	return s.Store.Entries(ctx)
+}
@@ -0,0 +1,125 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// SQLStore is a Store backed by a database/sql table, for history shared
+// between processes. It is written for SQLite and works with any driver
+// that accepts "?" placeholders; the caller picks and registers the
+// driver, for example:
+//
+//	db, err := sql.Open("sqlite", "history.db") // modernc.org/sqlite
+//	...
+//	store, err := history.NewSQLStore(ctx, db)
+type SQLStore struct {
+	db *sql.DB
+}
+
+const createTable = `CREATE TABLE IF NOT EXISTS greeting_history (
+	name       TEXT    NOT NULL,
+	locale     TEXT    NOT NULL,
+	message    TEXT    NOT NULL,
+	greeted_at INTEGER NOT NULL,
+	experiment TEXT    NOT NULL DEFAULT '',
+	variant    TEXT    NOT NULL DEFAULT ''
+)`
+
+// addExperiment upgrades tables created before entries had experiments.
+var addExperiment = []string{
+	`ALTER TABLE greeting_history ADD COLUMN experiment TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE greeting_history ADD COLUMN variant TEXT NOT NULL DEFAULT ''`,
+}
+
+const createIndex = `CREATE INDEX IF NOT EXISTS greeting_history_name
+	ON greeting_history (name, greeted_at)`
+
+// NewSQLStore returns an SQLStore using db, creating its table if it does
+// not exist yet and adding the experiment columns to tables from before
+// they existed. Times are stored as Unix nanoseconds in UTC.
+func NewSQLStore(ctx context.Context, db *sql.DB) (*SQLStore, error) {
+
+	for _, stmt := range []string{createTable, createIndex} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := db.ExecContext(ctx, `SELECT variant FROM greeting_history LIMIT 0`); err != nil {
+		for _, stmt := range addExperiment {
+			if _, err := db.ExecContext(ctx, stmt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &SQLStore{db: db}, nil
+}
+
+// Record implements Store.
+func (s *SQLStore) Record(ctx context.Context, e Entry) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO greeting_history (name, locale, message, greeted_at, experiment, variant) VALUES (?, ?, ?, ?, ?, ?)`,
+		e.Name, e.Locale, e.Message, e.Time.UnixNano(), e.Experiment, e.Variant)
+	return err
+}
+
+// LastGreeted implements Store.
+func (s *SQLStore) LastGreeted(ctx context.Context, name string) (Entry, bool, error) {
+
+	row := s.db.QueryRowContext(ctx,
+		`SELECT name, locale, message, greeted_at, experiment, variant FROM greeting_history
+		WHERE name = ? ORDER BY greeted_at DESC LIMIT 1`, name)
+	e, err := scanEntry(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Entry{}, false, nil
+	}
+	if err != nil {
+		return Entry{}, false, err
+	}
+
+	return e, true, nil
+}
+
+// Count implements Counter.
+func (s *SQLStore) Count(ctx context.Context, name string) (int, error) {
+	var n int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM greeting_history WHERE name = ?`, name).Scan(&n)
+	return n, err
+}
+
+// Entries implements Store.
+func (s *SQLStore) Entries(ctx context.Context) ([]Entry, error) {
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT name, locale, message, greeted_at, experiment, variant FROM greeting_history ORDER BY greeted_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
+
+// scanEntry reads an Entry from a row of name, locale, message,
+// greeted_at, experiment and variant.
+func scanEntry(row interface{ Scan(...any) error }) (Entry, error) {
+	var e Entry
+	var nanos int64
+	if err := row.Scan(&e.Name, &e.Locale, &e.Message, &nanos, &e.Experiment, &e.Variant); err != nil {
+		return Entry{}, err
+	}
+	e.Time = time.Unix(0, nanos).UTC()
+	return e, nil
+}
@@ -0,0 +1,77 @@
+package greetings
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Defaults for the element FormatHTML wraps names in.
+const (
+	defaultHTMLElement = "strong"
+	defaultHTMLClass   = "name"
+)
+
+// WithHTMLName sets the element FormatHTML wraps the name in and its class
+// attribute, which defaults to <strong class="name">. An empty class
+// leaves the attribute out. The element must be a plain tag name such as
+// "span" or "b".
+func WithHTMLName(element, class string) Option {
+	return func(g *Greeter) error {
+		if !isTagName(element) {
+			return fmt.Errorf("greetings: invalid HTML element %q", element)
+		}
+		g.htmlElement = element
+		g.htmlClass = class
+		return nil
+	}
+}
+
+// isTagName reports whether s is an ASCII letter followed by letters,
+// digits or hyphens.
+func isTagName(s string) bool {
+	for i, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z':
+		case i > 0 && ('0' <= r && r <= '9' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
+
+// HTML renders greeting as FormatHTML does with the default element, for
+// greetings produced without WithFormat: the escaped message with the
+// name in <strong class="name">.
+func HTML(greeting Greeting) string {
+	return htmlFragment(greeting, defaultHTMLElement, defaultHTMLClass)
+}
+
+// htmlFragment renders greeting as an HTML fragment safe to inject into a
+// page: all text is escaped and the name is wrapped in element. Greetings
+// in right-to-left locales are wrapped in <span dir="rtl"> too, so they
+// read right to left in a left-to-right page.
+func htmlFragment(greeting Greeting, element, class string) string {
+
+	s := split(greeting)
+	rtl := IsRTL(greeting.Locale)
+	var b strings.Builder
+	if rtl {
+		b.WriteString(`<span dir="rtl">`)
+	}
+	b.WriteString(html.EscapeString(s.before))
+	if s.name != "" {
+		b.WriteString("<" + element)
+		if class != "" {
+			fmt.Fprintf(&b, ` class="%s"`, html.EscapeString(class))
+		}
+		fmt.Fprintf(&b, ">%s</%s>", html.EscapeString(s.name), element)
+	}
+	b.WriteString(html.EscapeString(s.rest + s.tail))
+	if rtl {
+		b.WriteString("</span>")
+	}
+
+	return b.String()
+}
@@ -0,0 +1,237 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+
+	"example.com/greetings"
+)
+
+// MaxBodySize is the largest request body, in bytes, the API reads.
+const MaxBodySize = 64 << 10
+
+// Error codes of API responses, as listed in the Error schema of the
+// OpenAPI document.
+const (
+	codeInvalidJSON      = "invalid_json"
+	codeInvalidRequest   = "invalid_request"
+	codeInvalidName      = "invalid_name"
+	codeUnknownLocale    = "unknown_locale"
+	codeUnknownStyle     = "unknown_style"
+	codeUnsupportedMedia = "unsupported_media_type"
+	codeBodyTooLarge     = "body_too_large"
+	codeMethodNotAllowed = "method_not_allowed"
+	codeNotFound         = "not_found"
+	codeUnavailable      = "provider_unavailable"
+	codeInternal         = "internal"
+)
+
+// API is a small REST API for greetings, described by the OpenAPI
+// document it serves at /v1/openapi.yaml:
+//
+//	POST /v1/greetings  {"name":"Ada","locale":"fr","formality":"formal"}
+//	GET  /v1/locales    {"locales":["ar","de","en",...]}
+//	GET  /v1/styles     {"styles":["pirate",...]}
+//
+// POST bodies are checked against the document's GreetingRequest schema
+// before anything else. Failed requests are answered with a JSON error
+// whose code is one of the document's Error codes, and which names the
+// field at fault when there is one:
+//
+//	{"error":{"code":"invalid_request","message":"formality: must be one of [casual neutral formal]","field":"formality"}}
+//
+// Mount it at the root of a mux, or anywhere with http.StripPrefix. An API
+// is safe for concurrent use.
+type API struct {
+	mux *http.ServeMux
+
+	mu       sync.Mutex
+	greeters map[greeterKey]*greetings.Greeter
+}
+
+// greeterKey is the configuration of a Greeter the API has built.
+type greeterKey struct {
+	locale    string
+	formality greetings.Formality
+	style     string
+}
+
+// greetingRequest is the body of POST /v1/greetings.
+type greetingRequest struct {
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Locale    string `json:"locale"`
+	Formality string `json:"formality"`
+	Style     string `json:"style"`
+}
+
+// apiError is the error of a failed API request.
+type apiError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+// NewAPI returns an API over the built-in catalog and registered styles.
+func NewAPI() *API {
+
+	a := &API{mux: http.NewServeMux(), greeters: make(map[greeterKey]*greetings.Greeter)}
+	a.mux.HandleFunc("POST /v1/greetings", a.createGreeting)
+	a.mux.HandleFunc("GET /v1/locales", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string][]string{"locales": greetings.Locales()})
+	})
+	a.mux.HandleFunc("GET /v1/styles", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string][]string{"styles": greetings.Styles()})
+	})
+	a.mux.HandleFunc("GET /v1/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(openAPI)
+	})
+	for path, allow := range map[string]string{
+		"/v1/greetings":    "POST",
+		"/v1/locales":      "GET, HEAD",
+		"/v1/styles":       "GET, HEAD",
+		"/v1/openapi.yaml": "GET, HEAD",
+	} {
+		a.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Allow", allow)
+			writeAPIError(w, http.StatusMethodNotAllowed, apiError{Code: codeMethodNotAllowed, Message: fmt.Sprintf("method %s not allowed", r.Method)})
+		})
+	}
+	a.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, apiError{Code: codeNotFound, Message: fmt.Sprintf("no such endpoint %s", r.URL.Path)})
+	})
+
+	return a
+}
+
+// ServeHTTP implements http.Handler.
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// createGreeting serves POST /v1/greetings.
+func (a *API) createGreeting(w http.ResponseWriter, r *http.Request) {
+
+	if ct := r.Header.Get("Content-Type"); ct != "" {
+		if media, _, _ := mime.ParseMediaType(ct); media != "application/json" {
+			writeAPIError(w, http.StatusUnsupportedMediaType, apiError{Code: codeUnsupportedMedia, Message: fmt.Sprintf("content type %q is not application/json", ct)})
+			return
+		}
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			writeAPIError(w, http.StatusRequestEntityTooLarge, apiError{Code: codeBodyTooLarge, Message: fmt.Sprintf("body larger than %d bytes", maxErr.Limit)})
+			return
+		}
+		writeAPIError(w, http.StatusBadRequest, apiError{Code: codeInvalidJSON, Message: err.Error()})
+		return
+	}
+
+	req, apiErr := decodeGreetingRequest(body)
+	if apiErr != nil {
+		writeAPIError(w, http.StatusBadRequest, *apiErr)
+		return
+	}
+	if req.Locale == "" {
+		req.Locale = negotiateLocale(w, r)
+	}
+	g, apiErr := a.greeter(req)
+	if apiErr != nil {
+		writeAPIError(w, http.StatusBadRequest, *apiErr)
+		return
+	}
+
+	greeting, err := g.GreetCtx(r.Context(), greetings.Person{Name: req.Name, Title: req.Title})
+	switch code := greetings.CodeOf(err); {
+	case err == nil:
+		writeJSON(w, http.StatusOK, greeting)
+	case code == greetings.InvalidName:
+		writeAPIError(w, statusFor(err), apiError{Code: codeInvalidName, Message: err.Error(), Field: "name"})
+	case code == greetings.ProviderUnavailable:
+		writeAPIError(w, statusFor(err), apiError{Code: codeUnavailable, Message: err.Error()})
+	default:
+		writeAPIError(w, statusFor(err), apiError{Code: codeInternal, Message: err.Error()})
+	}
+}
+
+// decodeGreetingRequest parses body and checks it against the
+// GreetingRequest schema.
+func decodeGreetingRequest(body []byte) (greetingRequest, *apiError) {
+
+	var raw any
+	dec := json.NewDecoder(bytes.NewReader(body))
+	if err := dec.Decode(&raw); err != nil {
+		return greetingRequest{}, &apiError{Code: codeInvalidJSON, Message: fmt.Sprintf("body is not valid JSON: %v", err)}
+	}
+	if dec.More() {
+		return greetingRequest{}, &apiError{Code: codeInvalidJSON, Message: "body holds more than one JSON value"}
+	}
+	if err := schemas["GreetingRequest"].validate("", raw); err != nil {
+		fe := err.(*fieldError)
+		return greetingRequest{}, &apiError{Code: codeInvalidRequest, Message: fe.Error(), Field: fe.Field}
+	}
+
+	var req greetingRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return greetingRequest{}, &apiError{Code: codeInvalidJSON, Message: err.Error()}
+	}
+	if req.Formality == "" {
+		req.Formality = "neutral"
+	}
+
+	return req, nil
+}
+
+// greeter returns the Greeter for req's locale, formality and style,
+// building it on first use.
+func (a *API) greeter(req greetingRequest) (*greetings.Greeter, *apiError) {
+
+	formality, err := greetings.ParseFormality(req.Formality)
+	if err != nil {
+		return nil, &apiError{Code: codeInvalidRequest, Message: err.Error(), Field: "formality"}
+	}
+	locale, err := greetings.ResolveLocale(req.Locale)
+	if err != nil {
+		msg := fmt.Sprintf("unknown locale %q", req.Locale)
+		var le *greetings.LocaleError
+		if errors.As(err, &le) && le.Suggestion != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", le.Suggestion)
+		}
+		return nil, &apiError{Code: codeUnknownLocale, Message: msg, Field: "locale"}
+	}
+
+	key := greeterKey{locale: locale, formality: formality, style: req.Style}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if g, ok := a.greeters[key]; ok {
+		return g, nil
+	}
+	opts := []greetings.Option{greetings.WithLocale(locale), greetings.WithFormality(formality)}
+	if req.Style != "" {
+		opts = append(opts, greetings.WithStyle(req.Style))
+	}
+	g, err := greetings.New(opts...)
+	if errors.Is(err, greetings.ErrUnknownStyle) {
+		return nil, &apiError{Code: codeUnknownStyle, Message: fmt.Sprintf("unknown style %q", req.Style), Field: "style"}
+	}
+	if err != nil {
+		return nil, &apiError{Code: codeInternal, Message: err.Error()}
+	}
+	a.greeters[key] = g
+
+	return g, nil
+}
+
+// writeAPIError writes e as the JSON error response with the given status.
+func writeAPIError(w http.ResponseWriter, status int, e apiError) {
+	writeJSON(w, status, map[string]apiError{"error": e})
+}
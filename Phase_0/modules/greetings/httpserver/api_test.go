@@ -0,0 +1,116 @@
+package httpserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"example.com/greetings/httpserver"
+)
+
+// apiResponse is the union of the API's greeting and error bodies.
+type apiResponse struct {
+	Message string `json:"message"`
+	Locale  string `json:"locale"`
+	Error   struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Field   string `json:"field"`
+	} `json:"error"`
+}
+
+func TestCreateGreeting(t *testing.T) {
+	api := httpserver.NewAPI()
+	for _, tt := range []struct {
+		name, contentType, body string
+		status                  int
+		code, field             string
+		message                 string // the greeting, or a part of the error message
+	}{
+		{"valid", "application/json", `{"name":"Ada","locale":"fr","formality":"formal"}`, http.StatusOK, "", "", "Nous vous souhaitons la bienvenue, Ada."},
+		{"no content type", "", `{"name":"Ada"}`, http.StatusOK, "", "", "Hi, Ada. Welcome!"},
+		{"charset", "application/json; charset=utf-8", `{"name":"Ada","locale":"es"}`, http.StatusOK, "", "", "Hola, Ada. Te damos la bienvenida."},
+		{"unknown field", "application/json", `{"name":"Ada","nmae":"Ada"}`, http.StatusBadRequest, "invalid_request", "nmae", "is not a known field"},
+		{"bad enum", "application/json", `{"name":"Ada","formality":"posh"}`, http.StatusBadRequest, "invalid_request", "formality", "must be one of [casual neutral formal]"},
+		{"empty name", "application/json", `{"name":""}`, http.StatusBadRequest, "invalid_request", "name", "must not be empty"},
+		{"missing name", "application/json", `{}`, http.StatusBadRequest, "invalid_request", "name", "is required"},
+		{"wrong type", "application/json", `{"name":42}`, http.StatusBadRequest, "invalid_request", "name", "must be a string"},
+		{"long title", "application/json", `{"name":"Ada","title":"` + strings.Repeat("x", 21) + `"}`, http.StatusBadRequest, "invalid_request", "title", "at most 20 characters"},
+		{"not json", "application/json", `{"name":`, http.StatusBadRequest, "invalid_json", "", "not valid JSON"},
+		{"two values", "application/json", `{"name":"Ada"} {}`, http.StatusBadRequest, "invalid_json", "", "more than one JSON value"},
+		{"too large", "application/json", `{"name":"` + strings.Repeat("a", httpserver.MaxBodySize) + `"}`, http.StatusRequestEntityTooLarge, "body_too_large", "", "larger than 65536 bytes"},
+		{"wrong content type", "text/plain", `{"name":"Ada"}`, http.StatusUnsupportedMediaType, "unsupported_media_type", "", `"text/plain"`},
+		{"unknown locale", "application/json", `{"name":"Ada","locale":"fx"}`, http.StatusBadRequest, "unknown_locale", "locale", `unknown locale "fx"`},
+		{"locale typo", "application/json", `{"name":"Ada","locale":"enn-US"}`, http.StatusBadRequest, "unknown_locale", "locale", `did you mean "en-US"?`},
+		{"unknown style", "application/json", `{"name":"Ada","style":"klingon"}`, http.StatusBadRequest, "unknown_style", "style", `unknown style "klingon"`},
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/v1/greetings", strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			r.Header.Set("Content-Type", tt.contentType)
+		}
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, r)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.name, w.Code, tt.status)
+		}
+		var resp apiResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Errorf("%s: body %q: %v", tt.name, w.Body, err)
+			continue
+		}
+		if tt.code == "" {
+			if resp.Message != tt.message {
+				t.Errorf("%s: message = %q, want %q", tt.name, resp.Message, tt.message)
+			}
+			continue
+		}
+		if resp.Error.Code != tt.code || resp.Error.Field != tt.field || !strings.Contains(resp.Error.Message, tt.message) {
+			t.Errorf("%s: error = %+v, want code %q, field %q and a message containing %q",
+				tt.name, resp.Error, tt.code, tt.field, tt.message)
+		}
+	}
+}
+
+func TestCreateGreetingNegotiatesLocale(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/v1/greetings", strings.NewReader(`{"name":"Ada"}`))
+	r.Header.Set("Accept-Language", "de-AT;q=0.9, fr-CH;q=0.4")
+	w := httptest.NewRecorder()
+	httpserver.NewAPI().ServeHTTP(w, r)
+
+	var resp apiResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Locale != "de" {
+		t.Errorf("locale = %q, want %q", resp.Locale, "de")
+	}
+	if vary := w.Header().Get("Vary"); vary != "Accept-Language" {
+		t.Errorf("Vary = %q, want Accept-Language", vary)
+	}
+}
+
+func TestAPIRoutes(t *testing.T) {
+	api := httpserver.NewAPI()
+	for _, tt := range []struct {
+		method, path string
+		status       int
+		allow        string
+	}{
+		{http.MethodGet, "/v1/locales", http.StatusOK, ""},
+		{http.MethodGet, "/v1/styles", http.StatusOK, ""},
+		{http.MethodGet, "/v1/openapi.yaml", http.StatusOK, ""},
+		{http.MethodGet, "/v1/greetings", http.StatusMethodNotAllowed, "POST"},
+		{http.MethodDelete, "/v1/locales", http.StatusMethodNotAllowed, "GET, HEAD"},
+		{http.MethodGet, "/v2/greetings", http.StatusNotFound, ""},
+	} {
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.status || w.Header().Get("Allow") != tt.allow {
+			t.Errorf("%s %s: status %d, Allow %q; want %d, %q",
+				tt.method, tt.path, w.Code, w.Header().Get("Allow"), tt.status, tt.allow)
+		}
+	}
+}
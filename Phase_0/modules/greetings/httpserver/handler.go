@@ -0,0 +1,119 @@
+// Package httpserver serves greetings over HTTP as JSON.
+//
+// Mount a Handler in any mux:
+//
+//	mux.Handle("/greet", httpserver.NewHandler())
+//
+// and GET /greet?name=Alice&locale=fr answers with
+//
+//	{"salutation":"Bonjour","name":"Alice","message":"Bonjour, Alice. Bienvenue !",
+//	 "locale":"fr","generated_at":"2025-03-01T09:30:00Z"}
+//
+// An API offers the same greetings as a REST API with an OpenAPI
+// description, and a Hub serves them over WebSockets, pushed to clients as
+// events happen rather than on request.
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"example.com/greetings"
+)
+
+// MaxNameLength is the longest name, in runes, the handler accepts.
+const MaxNameLength = greetings.DefaultMaxNameLength
+
+// Handler answers greeting requests. The locale query parameter is
+// optional; without it the locale is negotiated from the Accept-Language
+// header, so browsers get their user's language, and defaults to English.
+type Handler struct {
+	greeters map[string]*greetings.Greeter
+}
+
+// NewHandler returns a Handler for every locale of the built-in catalog.
+func NewHandler() *Handler {
+
+	h := &Handler{greeters: make(map[string]*greetings.Greeter)}
+	for _, locale := range greetings.Locales() {
+		g, err := greetings.New(greetings.WithLocale(locale))
+		if err != nil {
+			panic(err) // the built-in catalog always has these locales
+		}
+		h.greeters[locale] = g
+	}
+
+	return h
+}
+
+// errorResponse is the JSON body of a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("name")
+	if err := greetings.ValidateMax(name, MaxNameLength); err != nil {
+		if errors.Is(err, greetings.ErrEmptyName) {
+			writeError(w, http.StatusBadRequest, "missing name parameter")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid name: %v", err)
+		return
+	}
+
+	locale := query.Get("locale")
+	if locale == "" {
+		locale = negotiateLocale(w, r)
+	}
+	resolved, err := greetings.ResolveLocale(locale)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unknown locale %q", locale)
+		return
+	}
+	greeter := h.greeters[resolved]
+
+	greeting, err := greeter.GreetCtx(r.Context(), greetings.Person{Name: name})
+	if err != nil {
+		writeError(w, statusFor(err), "%v", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, greeting)
+}
+
+// writeJSON writes v as the JSON response body with the given status.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// statusFor returns the HTTP status answering a request that failed with
+// err, by its greetings.Code.
+func statusFor(err error) int {
+	switch greetings.CodeOf(err) {
+	case greetings.InvalidName, greetings.UnknownLocale, greetings.InvalidConfig:
+		return http.StatusBadRequest
+	case greetings.ProviderUnavailable, greetings.Canceled:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError writes a JSON error response with the given status.
+func writeError(w http.ResponseWriter, status int, format string, args ...any) {
+	writeJSON(w, status, errorResponse{Error: fmt.Sprintf(format, args...)})
+}
@@ -0,0 +1,59 @@
+package httpserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"example.com/greetings/httpserver"
+)
+
+func TestHandler(t *testing.T) {
+	h := httpserver.NewHandler()
+	for _, tt := range []struct {
+		method string
+		query  url.Values
+		status int
+		want   string // the message, or the error
+	}{
+		{http.MethodGet, url.Values{"name": {"Alice"}}, http.StatusOK, "Hi, Alice. Welcome!"},
+		{http.MethodGet, url.Values{"name": {"Alice"}, "locale": {"fr"}}, http.StatusOK, "Bonjour, Alice. Bienvenue\u00a0!"},
+		{http.MethodGet, url.Values{"name": {"Alice"}, "locale": {"fr-CA"}}, http.StatusOK, "Bonjour, Alice. Bienvenue\u00a0!"},
+		{http.MethodGet, url.Values{}, http.StatusBadRequest, "missing name parameter"},
+		{http.MethodGet, url.Values{"name": {strings.Repeat("a", httpserver.MaxNameLength+1)}}, http.StatusBadRequest, "invalid name: "},
+		{http.MethodGet, url.Values{"name": {"Alice"}, "locale": {"xx"}}, http.StatusBadRequest, `unknown locale "xx"`},
+		{http.MethodPost, url.Values{"name": {"Alice"}}, http.StatusMethodNotAllowed, "method POST not allowed"},
+	} {
+		r := httptest.NewRequest(tt.method, "/greet?"+tt.query.Encode(), nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tt.status {
+			t.Errorf("%s %v: status = %d, want %d", tt.method, tt.query, w.Code, tt.status)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+			t.Errorf("%s %v: Content-Type = %q", tt.method, tt.query, ct)
+		}
+		var resp struct {
+			Message string `json:"message"`
+			Error   string `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %v: %v", tt.method, tt.query, err)
+		}
+		if got := resp.Message + resp.Error; !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s %v: got %q, want %q", tt.method, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestHandlerAllow(t *testing.T) {
+	w := httptest.NewRecorder()
+	httpserver.NewHandler().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/greet?name=Alice", nil))
+	if allow := w.Header().Get("Allow"); allow != "GET, HEAD" {
+		t.Errorf("Allow = %q, want %q", allow, "GET, HEAD")
+	}
+}
@@ -0,0 +1,146 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/dispatch"
+)
+
+// DefaultCheckTimeout bounds each readiness check of a Health that leaves
+// Timeout zero.
+const DefaultCheckTimeout = 2 * time.Second
+
+// Check reports whether one dependency of the service is usable; it
+// returns nil when it is.
+type Check func(ctx context.Context) error
+
+// Health answers container orchestrator probes. Mount its handlers as
+//
+//	mux.HandleFunc("/healthz", health.Healthz)
+//	mux.HandleFunc("/readyz", health.Readyz)
+//
+// Healthz reports that the process is up and runs no checks, so a slow
+// dependency never gets the process restarted. Readyz runs every added
+// Check at once and answers 503 Service Unavailable if any fails, so
+// traffic is held back until they all pass. Both answer with JSON:
+//
+//	{"status":"unavailable","checks":{"catalog":"ok","queue":"dispatch queue 95% full"}}
+//
+// Add the checks, then serve; a Health is safe for concurrent use.
+type Health struct {
+	// Timeout bounds each check; zero means DefaultCheckTimeout.
+	Timeout time.Duration
+
+	mu     sync.RWMutex
+	names  []string
+	checks map[string]Check
+}
+
+// healthResponse is the JSON body of Healthz and Readyz.
+type healthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Add makes Readyz run c under name, replacing any check of that name.
+func (h *Health) Add(name string, c Check) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.checks == nil {
+		h.checks = make(map[string]Check)
+	}
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = c
+}
+
+// Healthz serves the liveness probe.
+func (h *Health) Healthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, healthResponse{Status: "ok"})
+}
+
+// Readyz serves the readiness probe.
+func (h *Health) Readyz(w http.ResponseWriter, r *http.Request) {
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+
+	h.mu.RLock()
+	names := append([]string(nil), h.names...)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Go(func() {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			errs[i] = check(ctx)
+		})
+	}
+	wg.Wait()
+
+	resp := healthResponse{Status: "ok", Checks: make(map[string]string, len(names))}
+	status := http.StatusOK
+	for i, name := range names {
+		resp.Checks[name] = "ok"
+		if errs[i] != nil {
+			resp.Checks[name] = errs[i].Error()
+			resp.Status = "unavailable"
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, status, resp)
+}
+
+// CatalogCheck passes while catalog returns a catalog that has entries and
+// passes Catalog.Validate. Pass a CatalogWatcher's Catalog method to check
+// the catalog it last loaded.
+func CatalogCheck(catalog func() greetings.Catalog) Check {
+	return func(ctx context.Context) error {
+		c := catalog()
+		if len(c) == 0 {
+			return errors.New("no catalog loaded")
+		}
+		return c.Validate()
+	}
+}
+
+// ProviderCheck passes while g can greet, so a Greeter whose Provider
+// calls a remote service fails the check when the service is down. Each
+// check greets the name "readyz", which middleware such as analytics
+// will see like any other greeting.
+func ProviderCheck(g greetings.Interface) Check {
+	return func(ctx context.Context) error {
+		_, err := g.GreetCtx(ctx, greetings.Person{Name: "readyz"})
+		return err
+	}
+}
+
+// QueueCheck passes while d's queue is less than limit full, where limit
+// is a fraction such as 0.9.
+func QueueCheck(d *dispatch.Dispatcher, limit float64) Check {
+	return func(ctx context.Context) error {
+		s := d.Stats()
+		if s.Capacity == 0 {
+			return errors.New("dispatcher not started")
+		}
+		if full := float64(s.Depth) / float64(s.Capacity); full >= limit {
+			return fmt.Errorf("dispatch queue %.0f%% full", full*100)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/dispatch"
+	"example.com/greetings/httpserver"
+)
+
+// probe serves a GET request with handler and decodes its response.
+func probe(t *testing.T, handler http.HandlerFunc) (int, string, map[string]string) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	var resp struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	return w.Code, resp.Status, resp.Checks
+}
+
+func TestReadyz(t *testing.T) {
+	var h httpserver.Health
+	h.Add("catalog", httpserver.CatalogCheck(greetings.Builtin))
+	h.Add("queue", func(context.Context) error { return nil })
+
+	status, s, checks := probe(t, h.Readyz)
+	if status != http.StatusOK || s != "ok" || !reflect.DeepEqual(checks, map[string]string{"catalog": "ok", "queue": "ok"}) {
+		t.Errorf("ready: %d %q %v", status, s, checks)
+	}
+
+	h.Add("queue", func(context.Context) error { return errors.New("dispatch queue 95% full") })
+	status, s, checks = probe(t, h.Readyz)
+	if status != http.StatusServiceUnavailable || s != "unavailable" || checks["queue"] != "dispatch queue 95% full" || checks["catalog"] != "ok" {
+		t.Errorf("unready: %d %q %v", status, s, checks)
+	}
+	if len(checks) != 2 {
+		t.Errorf("Add replaced nothing: %v", checks)
+	}
+}
+
+func TestReadyzTimeout(t *testing.T) {
+	h := httpserver.Health{Timeout: time.Millisecond}
+	h.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	status, _, checks := probe(t, h.Readyz)
+	if status != http.StatusServiceUnavailable || checks["slow"] != context.DeadlineExceeded.Error() {
+		t.Errorf("Readyz = %d %v", status, checks)
+	}
+}
+
+func TestHealthzRunsNoChecks(t *testing.T) {
+	var h httpserver.Health
+	h.Add("broken", func(context.Context) error { return errors.New("broken") })
+	status, s, checks := probe(t, h.Healthz)
+	if status != http.StatusOK || s != "ok" || checks != nil {
+		t.Errorf("Healthz = %d %q %v", status, s, checks)
+	}
+}
+
+func TestCatalogCheck(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		catalog greetings.Catalog
+		ok      bool
+	}{
+		{"builtin", greetings.Builtin(), true},
+		{"empty", nil, false},
+		{"no english", greetings.Catalog{"fr": {Template: "Bonjour, %v."}}, false},
+	} {
+		err := httpserver.CatalogCheck(func() greetings.Catalog { return tt.catalog })(context.Background())
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: CatalogCheck = %v", tt.name, err)
+		}
+	}
+}
+
+// stuckSender holds every greeting until its context is done.
+type stuckSender struct{}
+
+func (stuckSender) Send(ctx context.Context, _ greetings.Greeting) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestQueueCheck(t *testing.T) {
+	g, err := greetings.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &dispatch.Dispatcher{Greeter: g, Sender: stuckSender{}, Capacity: 4}
+	check := httpserver.QueueCheck(d, 0.5)
+	if err := check(context.Background()); err == nil || err.Error() != "dispatcher not started" {
+		t.Errorf("before Start: %v", err)
+	}
+
+	d.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		d.Close(ctx)
+	})
+	for i, want := range []string{"<nil>", "<nil>", "<nil>", "dispatch queue 50% full", "dispatch queue 75% full"} {
+		if i > 0 {
+			if err := d.Enqueue(context.Background(), dispatch.Request{Person: greetings.Person{Name: "Ann"}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// The single worker takes the first request off the queue.
+		for i > 0 && d.Stats().Depth != i-1 {
+			time.Sleep(time.Millisecond)
+		}
+		if got := fmt.Sprint(check(context.Background())); got != want {
+			t.Errorf("after %d requests: %s, want %s", i, got, want)
+		}
+	}
+}
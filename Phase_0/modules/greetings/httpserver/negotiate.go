@@ -0,0 +1,51 @@
+package httpserver
+
+import (
+	"net/http"
+	"slices"
+
+	"golang.org/x/text/language"
+
+	"example.com/greetings"
+)
+
+// supported lists the built-in locales with English first, which makes it
+// the matcher's fallback.
+var supported = func() []string {
+	locales := greetings.Locales()
+	i := slices.Index(locales, "en")
+	return append([]string{"en"}, slices.Delete(locales, i, i+1)...)
+}()
+
+// matcher matches Accept-Language preferences against supported.
+var matcher = func() language.Matcher {
+	tags := make([]language.Tag, len(supported))
+	for i, locale := range supported {
+		tags[i] = language.MustParse(locale)
+	}
+	return language.NewMatcher(tags)
+}()
+
+// negotiateLocale returns the built-in locale that best serves the
+// preferences in r's Accept-Language header, q-values included, such as
+// "de" for "fr-CH;q=0.4, de-AT;q=0.9". Without the header, or without a
+// usable match, it returns "en". It adds Accept-Language to the Vary
+// header of w, since the response depends on it.
+func negotiateLocale(w http.ResponseWriter, r *http.Request) string {
+
+	w.Header().Add("Vary", "Accept-Language")
+	header := r.Header.Get("Accept-Language")
+	if header == "" {
+		return "en"
+	}
+	prefs, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(prefs) == 0 {
+		return "en"
+	}
+	_, i, confidence := matcher.Match(prefs...)
+	if confidence == language.No {
+		return "en"
+	}
+
+	return supported[i]
+}
@@ -0,0 +1,57 @@
+package httpserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"example.com/greetings/httpserver"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	h := httpserver.NewHandler()
+	for _, tt := range []struct {
+		header, want string
+	}{
+		{"", "en"},
+		{"fr", "fr"},
+		{"fr-CH;q=0.4, de-AT;q=0.9", "de"},
+		{"de-AT;q=0.9, fr-CH", "fr"},
+		{"es-MX, en;q=0.5", "es"},
+		{"pt-BR", "pt"},
+		{"tlh", "en"},
+		{"*", "en"},
+		{"not a;;header==", "en"},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/greet?name=Ada", nil)
+		if tt.header != "" {
+			r.Header.Set("Accept-Language", tt.header)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		var resp struct {
+			Locale string `json:"locale"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%q: %v", tt.header, err)
+		}
+		if resp.Locale != tt.want {
+			t.Errorf("Accept-Language %q: locale = %q, want %q", tt.header, resp.Locale, tt.want)
+		}
+		if vary := w.Header().Get("Vary"); vary != "Accept-Language" {
+			t.Errorf("Accept-Language %q: Vary = %q", tt.header, vary)
+		}
+	}
+}
+
+func TestLocaleParameterSkipsNegotiation(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/greet?name=Ada&locale=es", nil)
+	r.Header.Set("Accept-Language", "fr")
+	w := httptest.NewRecorder()
+	httpserver.NewHandler().ServeHTTP(w, r)
+	if vary := w.Header().Get("Vary"); vary != "" {
+		t.Errorf("Vary = %q, want none", vary)
+	}
+}
@@ -0,0 +1,254 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"example.com/greetings"
+)
+
+// PingInterval is how often a Hub pings idle clients; a client that does
+// not answer within the same interval is disconnected.
+const PingInterval = 30 * time.Second
+
+// writeTimeout bounds every message a Hub sends.
+const writeTimeout = 10 * time.Second
+
+// Hub keeps WebSocket connections open and pushes greetings to them. A
+// client connects with the name it should be greeted by and, optionally,
+// a locale, which is otherwise negotiated like Handler's:
+//
+//	ws://host/push?name=Alice&locale=fr
+//
+// and receives a JSON greeting, shaped like Handler's responses, at once
+// and whenever the application calls Greet or GreetAll, for example when
+// the user logs in elsewhere or on a schedule. Clients switch locale by
+// sending {"locale":"es"}. A Hub is safe for concurrent use.
+type Hub struct {
+	greeters map[string]*greetings.Greeter
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closed  bool
+}
+
+// client is one open connection.
+type client struct {
+	name string
+	conn *websocket.Conn
+
+	mu     sync.Mutex
+	locale string
+}
+
+// localeMessage is what clients send to change their locale.
+type localeMessage struct {
+	Locale string `json:"locale"`
+}
+
+// NewHub returns a Hub for every locale of the built-in catalog.
+func NewHub() *Hub {
+
+	h := &Hub{
+		greeters: make(map[string]*greetings.Greeter),
+		clients:  make(map[*client]struct{}),
+	}
+	for _, locale := range greetings.Locales() {
+		g, err := greetings.New(greetings.WithLocale(locale))
+		if err != nil {
+			panic(err) // the built-in catalog always has these locales
+		}
+		h.greeters[locale] = g
+	}
+
+	return h
+}
+
+// ServeHTTP implements http.Handler by upgrading the request to a
+// WebSocket and serving it until either side closes it.
+func (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+	name := query.Get("name")
+	if err := greetings.ValidateMax(name, MaxNameLength); err != nil {
+		if errors.Is(err, greetings.ErrEmptyName) {
+			writeError(w, http.StatusBadRequest, "missing name parameter")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "invalid name: %v", err)
+		return
+	}
+	locale := query.Get("locale")
+	if locale == "" {
+		locale = negotiateLocale(w, r)
+	}
+	resolved, err := greetings.ResolveLocale(locale)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "unknown locale %q", locale)
+		return
+	}
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		return // Accept has already answered the request
+	}
+	c := &client{name: name, conn: conn, locale: resolved}
+	if !h.add(c) {
+		conn.Close(websocket.StatusGoingAway, "server shutting down")
+		return
+	}
+	defer h.remove(c)
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	defer cancel()
+	go h.keepAlive(ctx, c)
+
+	if err := h.push(ctx, c); err != nil {
+		conn.CloseNow()
+		return
+	}
+	for {
+		var msg localeMessage
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			conn.CloseNow()
+			return
+		}
+		resolved, err := greetings.ResolveLocale(msg.Locale)
+		if err != nil {
+			h.write(ctx, c, errorResponse{Error: fmt.Sprintf("unknown locale %q", msg.Locale)})
+			continue
+		}
+		c.mu.Lock()
+		c.locale = resolved
+		c.mu.Unlock()
+	}
+}
+
+// keepAlive pings c every PingInterval until ctx is done, and drops the
+// connection when a ping goes unanswered.
+func (h *Hub) keepAlive(ctx context.Context, c *client) {
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		pingCtx, cancel := context.WithTimeout(ctx, PingInterval)
+		err := c.conn.Ping(pingCtx)
+		cancel()
+		if err != nil {
+			c.conn.CloseNow()
+			return
+		}
+	}
+}
+
+// push greets c in its current locale.
+func (h *Hub) push(ctx context.Context, c *client) error {
+	c.mu.Lock()
+	locale := c.locale
+	c.mu.Unlock()
+	greeting, err := h.greeters[locale].GreetCtx(ctx, greetings.Person{Name: c.name})
+	if err != nil {
+		return err
+	}
+	return h.write(ctx, c, greeting)
+}
+
+// write sends v to c as JSON.
+func (h *Hub) write(ctx context.Context, c *client, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+	return wsjson.Write(ctx, c.conn, v)
+}
+
+// Greet pushes a greeting to every connection opened for name and reports
+// how many received it.
+func (h *Hub) Greet(ctx context.Context, name string) (int, error) {
+	return h.pushTo(ctx, func(c *client) bool { return c.name == name })
+}
+
+// GreetAll pushes a greeting to every open connection and reports how
+// many received it.
+func (h *Hub) GreetAll(ctx context.Context) (int, error) {
+	return h.pushTo(ctx, func(*client) bool { return true })
+}
+
+// pushTo greets the clients match selects.
+func (h *Hub) pushTo(ctx context.Context, match func(*client) bool) (int, error) {
+
+	h.mu.Lock()
+	var targets []*client
+	for c := range h.clients {
+		if match(c) {
+			targets = append(targets, c)
+		}
+	}
+	h.mu.Unlock()
+
+	sent := 0
+	var errs []error
+	for _, c := range targets {
+		if err := h.push(ctx, c); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+			continue
+		}
+		sent++
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// Len reports how many connections are open.
+func (h *Hub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// Close disconnects every client and refuses new ones. Connections are
+// hijacked from the HTTP server, so its graceful shutdown does not wait
+// for them; call Close when shutting down.
+func (h *Hub) Close() error {
+
+	h.mu.Lock()
+	h.closed = true
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Go(func() { c.conn.Close(websocket.StatusGoingAway, "server shutting down") })
+	}
+	wg.Wait()
+
+	return nil
+}
+
+func (h *Hub) add(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.clients[c] = struct{}{}
+	return true
+}
+
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c)
+}
@@ -0,0 +1,143 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+
+	"example.com/greetings/httpserver"
+)
+
+// pushed is a message a Hub sends: a greeting or an error.
+type pushed struct {
+	Message string `json:"message"`
+	Locale  string `json:"locale"`
+	Error   string `json:"error"`
+}
+
+// dial opens a WebSocket to srv's /push with query.
+func dial(t *testing.T, ctx context.Context, srv *httptest.Server, query string) *websocket.Conn {
+	t.Helper()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/push?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+	return conn
+}
+
+// read decodes the next message on conn.
+func read(t *testing.T, ctx context.Context, conn *websocket.Conn) pushed {
+	t.Helper()
+	var msg pushed
+	if err := wsjson.Read(ctx, conn, &msg); err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
+
+func TestHub(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	hub := httpserver.NewHub()
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+	defer hub.Close()
+
+	ada := dial(t, ctx, srv, "name=Ada&locale=fr")
+	bob := dial(t, ctx, srv, "name=Bob")
+	if msg := read(t, ctx, ada); msg.Message != "Bonjour, Ada. Bienvenue\u00a0!" {
+		t.Errorf("Ada's first greeting = %+v", msg)
+	}
+	if msg := read(t, ctx, bob); msg.Message != "Hi, Bob. Welcome!" {
+		t.Errorf("Bob's first greeting = %+v", msg)
+	}
+	if n := hub.Len(); n != 2 {
+		t.Errorf("Len = %d, want 2", n)
+	}
+
+	if n, err := hub.Greet(ctx, "Ada"); n != 1 || err != nil {
+		t.Errorf("Greet(Ada) = %d, %v", n, err)
+	}
+	if msg := read(t, ctx, ada); msg.Locale != "fr" {
+		t.Errorf("Ada's pushed greeting = %+v", msg)
+	}
+
+	// The unknown locale's error shows the change to es has been read.
+	for _, locale := range []string{"es", "xx"} {
+		if err := wsjson.Write(ctx, ada, map[string]string{"locale": locale}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if msg := read(t, ctx, ada); msg.Error != `unknown locale "xx"` {
+		t.Errorf("after an unknown locale: %+v", msg)
+	}
+	if n, err := hub.GreetAll(ctx); n != 2 || err != nil {
+		t.Errorf("GreetAll = %d, %v", n, err)
+	}
+	if msg := read(t, ctx, ada); msg.Message != "Hola, Ada. Te damos la bienvenida." {
+		t.Errorf("Ada's greeting after switching to es = %+v", msg)
+	}
+	if msg := read(t, ctx, bob); msg.Locale != "en" {
+		t.Errorf("Bob's greeting = %+v", msg)
+	}
+
+	// Close waits for the clients to answer its close frames, which they
+	// do while reading.
+	closed := make(chan error, 1)
+	go func() { closed <- hub.Close() }()
+	for _, conn := range []*websocket.Conn{ada, bob} {
+		var msg pushed
+		if err := wsjson.Read(ctx, conn, &msg); websocket.CloseStatus(err) != websocket.StatusGoingAway {
+			t.Errorf("read after Close: %v", err)
+		}
+	}
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHubRejects(t *testing.T) {
+	hub := httpserver.NewHub()
+	for _, tt := range []struct {
+		query, want string
+	}{
+		{"", "missing name parameter"},
+		{"name=Ada&locale=xx", `unknown locale "xx"`},
+	} {
+		w := httptest.NewRecorder()
+		hub.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/push?"+tt.query, nil))
+		var msg pushed
+		if err := json.Unmarshal(w.Body.Bytes(), &msg); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusBadRequest || msg.Error != tt.want {
+			t.Errorf("%q: %d %q, want 400 %q", tt.query, w.Code, msg.Error, tt.want)
+		}
+	}
+}
+
+func TestHubRefusesAfterClose(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	hub := httpserver.NewHub()
+	srv := httptest.NewServer(hub)
+	defer srv.Close()
+	hub.Close()
+
+	conn := dial(t, ctx, srv, "name=Ada")
+	var msg pushed
+	if err := wsjson.Read(ctx, conn, &msg); websocket.CloseStatus(err) != websocket.StatusGoingAway {
+		t.Errorf("read from a closed Hub: %v", err)
+	}
+	if n := hub.Len(); n != 0 {
+		t.Errorf("Len = %d, want 0", n)
+	}
+}
@@ -0,0 +1,144 @@
+package httpserver
+
+import (
+	_ "embed"
+	"fmt"
+	"maps"
+	"slices"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// openAPI is the OpenAPI document describing API.
+//
+//go:embed openapi.yaml
+var openAPI []byte
+
+// schema is the part of an OpenAPI schema object that API checks requests
+// against: types, required and unknown properties, enums and string
+// lengths.
+type schema struct {
+	Type                 string             `yaml:"type"`
+	Required             []string           `yaml:"required"`
+	Properties           map[string]*schema `yaml:"properties"`
+	AdditionalProperties *bool              `yaml:"additionalProperties"`
+	Enum                 []string           `yaml:"enum"`
+	MinLength            *int               `yaml:"minLength"`
+	MaxLength            *int               `yaml:"maxLength"`
+}
+
+// schemas are the component schemas of openAPI, by name.
+var schemas = func() map[string]*schema {
+	var doc struct {
+		Components struct {
+			Schemas map[string]*schema `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal(openAPI, &doc); err != nil {
+		panic(fmt.Sprintf("httpserver: openapi.yaml: %v", err))
+	}
+	return doc.Components.Schemas
+}()
+
+// fieldError reports a request value that does not match its schema.
+type fieldError struct {
+	Field string
+	Msg   string
+}
+
+func (e *fieldError) Error() string {
+	if e.Field == "" {
+		return e.Msg
+	}
+	return e.Field + ": " + e.Msg
+}
+
+// validate checks v, a value decoded from JSON, against s. field is the
+// path to v for error messages, empty for the whole body.
+func (s *schema) validate(field string, v any) error {
+
+	if err := s.checkType(field, v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				return &fieldError{Field: join(field, name), Msg: "is required"}
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return &fieldError{Field: join(field, name), Msg: "is not a known field"}
+				}
+				continue
+			}
+			if err := prop.validate(join(field, name), v[name]); err != nil {
+				return err
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if s.MinLength != nil && n < *s.MinLength {
+			if *s.MinLength == 1 {
+				return &fieldError{Field: field, Msg: "must not be empty"}
+			}
+			return &fieldError{Field: field, Msg: fmt.Sprintf("must be at least %d characters", *s.MinLength)}
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			return &fieldError{Field: field, Msg: fmt.Sprintf("must be at most %d characters", *s.MaxLength)}
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+			return &fieldError{Field: field, Msg: fmt.Sprintf("must be one of %v", s.Enum)}
+		}
+	}
+
+	return nil
+}
+
+// checkType reports whether v has the JSON type s asks for.
+func (s *schema) checkType(field string, v any) error {
+
+	var ok bool
+	switch s.Type {
+	case "":
+		return nil
+	case "object":
+		_, ok = v.(map[string]any)
+	case "array":
+		_, ok = v.([]any)
+	case "string":
+		_, ok = v.(string)
+	case "number":
+		_, ok = v.(float64)
+	case "integer":
+		f, isNumber := v.(float64)
+		ok = isNumber && f == float64(int64(f))
+	case "boolean":
+		_, ok = v.(bool)
+	}
+	if !ok {
+		return &fieldError{Field: field, Msg: "must be " + article(s.Type) + " " + s.Type}
+	}
+
+	return nil
+}
+
+// join returns the path of property name inside field.
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+func article(word string) string {
+	if word != "" && slices.Contains([]byte("aeiou"), word[0]) {
+		return "an"
+	}
+	return "a"
+}
@@ -0,0 +1,316 @@
+package greetings
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// LoadMessages builds a catalog from translation files as produced by
+// common translation workflows: gettext .po files and go-i18n JSON
+// message files, one locale per file. Message IDs name the Message
+// fields like catalog file keys, with a dot for the registers:
+//
+//	template, punctuation, casual.template, casual.punctuation,
+//	formal.template, formal.punctuation, title_format, separator,
+//	conjunction, emoji, birthday, welcome_back, group
+//
+// In .po files a msgctxt of "casual" or "formal" can stand in for the
+// prefix, untranslated and fuzzy entries are skipped, and the locale comes
+// from the Language header. go-i18n files may nest IDs and give messages
+// as plain strings or as objects whose "other" form is used; their locale
+// comes from the file name, "fr.json" or "active.fr.json". The catalog
+// must end up with an English entry; problems are reported as
+// *CatalogError values joined into one error.
+func LoadMessages(paths ...string) (Catalog, error) {
+
+	c := make(Catalog)
+	var errs []error
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		name := filepath.Base(path)
+		var locale string
+		var msg Message
+		switch strings.ToLower(filepath.Ext(name)) {
+		case ".po":
+			locale, msg, err = ParsePO(name, data)
+		case ".json":
+			locale = localeFromFileName(name)
+			msg, err = ParseI18nJSON(name, data)
+		default:
+			err = &CatalogError{File: name, Err: errors.New("unknown message file format; want .po or .json")}
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := language.Parse(locale); err != nil {
+			errs = append(errs, &CatalogError{File: name, Err: fmt.Errorf("invalid locale %q", locale)})
+			continue
+		}
+		if _, dup := c[locale]; dup {
+			errs = append(errs, &CatalogError{File: name, Err: fmt.Errorf("duplicate locale %q", locale)})
+			continue
+		}
+		c[locale] = msg
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// localeFromFileName returns the locale in a go-i18n style file name:
+// "fr" for "fr.json" and "active.fr.json".
+func localeFromFileName(name string) string {
+	parts := strings.Split(strings.TrimSuffix(name, filepath.Ext(name)), ".")
+	return parts[len(parts)-1]
+}
+
+// setMessageField stores value in the field of msg that id names.
+func setMessageField(msg *Message, id, value string) error {
+	switch id {
+	case "template":
+		msg.Template = value
+	case "punctuation":
+		msg.Punctuation = value
+	case "casual.template":
+		msg.Casual.Template = value
+	case "casual.punctuation":
+		msg.Casual.Punctuation = value
+	case "formal.template":
+		msg.Formal.Template = value
+	case "formal.punctuation":
+		msg.Formal.Punctuation = value
+	case "title_format":
+		msg.TitleFormat = value
+	case "separator":
+		msg.Separator = value
+	case "conjunction":
+		msg.Conjunction = value
+	case "emoji":
+		msg.Emoji = value
+	case "birthday":
+		msg.Birthday = value
+	case "welcome_back":
+		msg.WelcomeBack = value
+	case "group":
+		msg.Group = value
+	default:
+		return errors.New("unknown message ID")
+	}
+	if strings.HasSuffix(id, "template") || id == "birthday" || id == "welcome_back" {
+		return checkFormat(value)
+	}
+	return nil
+}
+
+// poEntry is one message of a .po file.
+type poEntry struct {
+	line    int
+	context string
+	id      string
+	str     string
+	plural  bool
+	fuzzy   bool
+	hasStr  bool // a msgstr was seen, so the next msgid starts an entry
+}
+
+// ParsePO parses a gettext .po file into the locale named by its Language
+// header, falling back to the file name, and that locale's message; name
+// is used in error messages.
+func ParsePO(name string, data []byte) (string, Message, error) {
+
+	entries, err := parsePO(name, data)
+	if err != nil {
+		return "", Message{}, err
+	}
+
+	locale := strings.TrimSuffix(name, filepath.Ext(name))
+	var msg Message
+	var errs []error
+	for _, e := range entries {
+		if e.id == "" && e.context == "" {
+			for _, header := range strings.Split(e.str, "\n") {
+				if key, value, ok := strings.Cut(header, ":"); ok && strings.TrimSpace(key) == "Language" {
+					if v := strings.TrimSpace(value); v != "" {
+						locale = strings.ReplaceAll(v, "_", "-")
+					}
+				}
+			}
+			continue
+		}
+		id := e.id
+		if e.context != "" {
+			id = e.context + "." + e.id
+		}
+		switch {
+		case e.plural:
+			errs = append(errs, &CatalogError{File: name, Line: e.line, Field: id, Err: errors.New("plural forms are not supported")})
+		case e.fuzzy || e.str == "":
+			// Not translated yet.
+		default:
+			if err := setMessageField(&msg, id, e.str); err != nil {
+				errs = append(errs, &CatalogError{File: name, Line: e.line, Field: id, Err: err})
+			}
+		}
+	}
+	if msg.Template == "" {
+		errs = append(errs, &CatalogError{File: name, Field: "template", Err: errors.New("missing message")})
+	}
+	if len(errs) > 0 {
+		return "", Message{}, errors.Join(errs...)
+	}
+
+	return locale, msg, nil
+}
+
+// parsePO splits a .po file into its entries.
+func parsePO(name string, data []byte) ([]poEntry, error) {
+
+	var entries []poEntry
+	var cur poEntry
+	var target *string // the string continuation lines append to
+	started := false
+	flush := func() {
+		if started {
+			entries = append(entries, cur)
+		}
+		cur, target, started = poEntry{}, nil, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		keyword, rest, _ := strings.Cut(text, " ")
+		switch {
+		case text == "":
+			flush()
+			continue
+		case strings.HasPrefix(text, "#,"):
+			if cur.hasStr {
+				flush()
+			}
+			cur.fuzzy = cur.fuzzy || strings.Contains(text, "fuzzy")
+			continue
+		case strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, `"`):
+			if target == nil {
+				return nil, &CatalogError{File: name, Line: line, Err: errors.New("string outside an entry")}
+			}
+			rest = text
+		case (keyword == "msgctxt" || keyword == "msgid") && cur.hasStr:
+			flush()
+		}
+
+		s, err := strconv.Unquote(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, &CatalogError{File: name, Line: line, Err: fmt.Errorf("malformed string %s", rest)}
+		}
+		if strings.HasPrefix(text, `"`) {
+			*target += s
+			continue
+		}
+		if !started {
+			cur.line, started = line, true
+		}
+		switch {
+		case keyword == "msgctxt":
+			cur.context, target = s, &cur.context
+		case keyword == "msgid":
+			cur.id, target = s, &cur.id
+		case keyword == "msgid_plural":
+			cur.plural, target = true, new(string)
+		case keyword == "msgstr":
+			cur.str, cur.hasStr, target = s, true, &cur.str
+		case strings.HasPrefix(keyword, "msgstr["):
+			cur.plural, cur.hasStr, target = true, true, new(string)
+		default:
+			return nil, &CatalogError{File: name, Line: line, Err: fmt.Errorf("unknown keyword %q", keyword)}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+
+	return entries, nil
+}
+
+// i18nPluralForms are the CLDR plural categories go-i18n messages use;
+// an object holding any of them, or a description, is a message rather
+// than a nesting level.
+var i18nPluralForms = []string{"zero", "one", "two", "few", "many", "other", "description", "hash"}
+
+// ParseI18nJSON parses a go-i18n JSON message file into a Message; name
+// is used in error messages.
+func ParseI18nJSON(name string, data []byte) (Message, error) {
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Message{}, &CatalogError{File: name, Err: err}
+	}
+
+	var msg Message
+	var errs []error
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			id := prefix + k
+			var value string
+			switch v := m[k].(type) {
+			case string:
+				value = v
+			case map[string]any:
+				if !slices.ContainsFunc(i18nPluralForms, func(form string) bool { _, ok := v[form]; return ok }) {
+					walk(id+".", v)
+					continue
+				}
+				other, ok := v["other"].(string)
+				if !ok {
+					errs = append(errs, &CatalogError{File: name, Field: id, Err: errors.New(`message has no "other" form`)})
+					continue
+				}
+				value = other
+			default:
+				errs = append(errs, &CatalogError{File: name, Field: id, Err: errors.New("expected a string or a message object")})
+				continue
+			}
+			if err := setMessageField(&msg, id, value); err != nil {
+				errs = append(errs, &CatalogError{File: name, Field: id, Err: err})
+			}
+		}
+	}
+	walk("", doc)
+	if msg.Template == "" {
+		errs = append(errs, &CatalogError{File: name, Field: "template", Err: errors.New("missing message")})
+	}
+	if len(errs) > 0 {
+		return Message{}, errors.Join(errs...)
+	}
+
+	return msg, nil
+}
@@ -0,0 +1,303 @@
+package greetings
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// MessageFormat is a parsed ICU MessageFormat message, the syntax
+// translators use for text that changes with a count or a choice:
+//
+//	{count, plural, one {Witaj, {names}} few {Witajcie, {names}} other {Witajcie, {names}}}!
+//
+// It supports simple arguments like {names}, plural arguments with exact
+// matches (=0) and the CLDR categories zero, one, two, few, many and
+// other, "#" for the count inside a plural case, and select arguments
+// ({gender, select, female {...} other {...}}). An apostrophe quotes
+// braces and "#", and two apostrophes stand for one. Every plural and
+// select needs an "other" case. A MessageFormat is safe for concurrent
+// use.
+type MessageFormat struct {
+	tag   language.Tag
+	nodes []icuNode
+}
+
+// icuNode is one piece of a parsed message: literal text, an argument, a
+// plural or select, or the "#" of a plural case.
+type icuNode struct {
+	text  string
+	arg   string
+	kind  string // "", "plural" or "select"; "#" for the count in a plural case
+	cases map[string][]icuNode
+}
+
+// ParseMessageFormat parses text as an ICU message for locale, whose
+// plural rules decide which plural case a count selects.
+func ParseMessageFormat(locale, text string) (*MessageFormat, error) {
+
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return nil, &Error{Code: TemplateError, Err: fmt.Errorf("greetings: message format: %w", err)}
+	}
+	p := &icuParser{src: text}
+	nodes, err := p.nodes(false)
+	if err == nil && p.pos < len(p.src) {
+		err = p.errorf("unexpected %q", p.src[p.pos])
+	}
+	if err != nil {
+		return nil, &Error{Code: TemplateError, Err: fmt.Errorf("greetings: message format %q: %w", text, err)}
+	}
+
+	return &MessageFormat{tag: tag, nodes: nodes}, nil
+}
+
+// Format renders the message with args. Plural arguments must be
+// integers; every other argument is formatted with %v. Format fails when
+// an argument the message uses is missing.
+func (m *MessageFormat) Format(args map[string]any) (string, error) {
+	var b strings.Builder
+	if err := m.format(&b, m.nodes, args, 0); err != nil {
+		return "", &Error{Code: TemplateError, Err: err}
+	}
+	return b.String(), nil
+}
+
+func (m *MessageFormat) format(b *strings.Builder, nodes []icuNode, args map[string]any, count int) error {
+	for _, n := range nodes {
+		switch {
+		case n.kind == "#":
+			b.WriteString(strconv.Itoa(count))
+		case n.arg == "":
+			b.WriteString(n.text)
+		default:
+			v, ok := args[n.arg]
+			if !ok {
+				return fmt.Errorf("greetings: message format: missing argument %q", n.arg)
+			}
+			switch n.kind {
+			case "":
+				fmt.Fprint(b, v)
+			case "select":
+				c, ok := n.cases[fmt.Sprint(v)]
+				if !ok {
+					c = n.cases["other"]
+				}
+				if err := m.format(b, c, args, count); err != nil {
+					return err
+				}
+			case "plural":
+				i, ok := toInt(v)
+				if !ok {
+					return fmt.Errorf("greetings: message format: argument %q is %T, not an integer", n.arg, v)
+				}
+				c, ok := n.cases["="+strconv.Itoa(i)]
+				if !ok {
+					c, ok = n.cases[pluralCategory(m.tag, i)]
+				}
+				if !ok {
+					c = n.cases["other"]
+				}
+				if err := m.format(b, c, args, i); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// pluralCategory returns the CLDR plural category of the integer n in the
+// language of tag: "one" for 1 in English, "few" for 3 in Polish.
+func pluralCategory(tag language.Tag, n int) string {
+	if n < 0 {
+		n = -n
+	}
+	switch plural.Cardinal.MatchPlural(tag, n%10000000, 0, 0, 0, 0) {
+	case plural.Zero:
+		return "zero"
+	case plural.One:
+		return "one"
+	case plural.Two:
+		return "two"
+	case plural.Few:
+		return "few"
+	case plural.Many:
+		return "many"
+	default:
+		return "other"
+	}
+}
+
+func toInt(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case int32:
+		return int(v), true
+	case uint:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// icuParser is a recursive descent parser over a message.
+type icuParser struct {
+	src string
+	pos int
+}
+
+func (p *icuParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("offset %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// nodes parses text and arguments up to the end of the message or, inside
+// a case, the closing brace, which it leaves unread. inPlural makes "#"
+// stand for the count.
+func (p *icuParser) nodes(inPlural bool) ([]icuNode, error) {
+
+	var nodes []icuNode
+	var text strings.Builder
+	flushText := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, icuNode{text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '}':
+			flushText()
+			return nodes, nil
+		case c == '{':
+			flushText()
+			n, err := p.argument()
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, n)
+		case c == '#' && inPlural:
+			flushText()
+			nodes = append(nodes, icuNode{kind: "#", arg: "#"})
+			p.pos++
+		case c == '\'':
+			p.quoted(&text)
+		default:
+			text.WriteByte(c)
+			p.pos++
+		}
+	}
+	flushText()
+
+	return nodes, nil
+}
+
+// quoted reads an apostrophe escape at p.pos into text.
+func (p *icuParser) quoted(text *strings.Builder) {
+	p.pos++
+	switch {
+	case p.pos < len(p.src) && p.src[p.pos] == '\'':
+		text.WriteByte('\'')
+		p.pos++
+	case p.pos < len(p.src) && strings.IndexByte("{}#", p.src[p.pos]) >= 0:
+		end := strings.IndexByte(p.src[p.pos:], '\'')
+		if end < 0 {
+			end = len(p.src) - p.pos
+		}
+		text.WriteString(strings.ReplaceAll(p.src[p.pos:p.pos+end], "''", "'"))
+		p.pos += min(end+1, len(p.src)-p.pos)
+	default:
+		text.WriteByte('\'')
+	}
+}
+
+// argument parses {name}, {name, plural, ...} or {name, select, ...}
+// with p.pos at the opening brace.
+func (p *icuParser) argument() (icuNode, error) {
+
+	p.pos++
+	name := p.word()
+	if name == "" {
+		return icuNode{}, p.errorf("missing argument name")
+	}
+	n := icuNode{arg: name}
+	p.space()
+	if p.consume('}') {
+		return n, nil
+	}
+	if !p.consume(',') {
+		return icuNode{}, p.errorf("expected ',' or '}' after %q", name)
+	}
+	p.space()
+	n.kind = p.word()
+	if n.kind != "plural" && n.kind != "select" {
+		return icuNode{}, p.errorf("unsupported argument type %q", n.kind)
+	}
+	p.space()
+	if !p.consume(',') {
+		return icuNode{}, p.errorf("expected ',' after %s", n.kind)
+	}
+
+	n.cases = make(map[string][]icuNode)
+	for {
+		p.space()
+		if p.consume('}') {
+			break
+		}
+		key := p.word()
+		if key == "" {
+			return icuNode{}, p.errorf("expected a case name")
+		}
+		if _, dup := n.cases[key]; dup {
+			return icuNode{}, p.errorf("duplicate case %q", key)
+		}
+		p.space()
+		if !p.consume('{') {
+			return icuNode{}, p.errorf("expected '{' after case %q", key)
+		}
+		body, err := p.nodes(n.kind == "plural")
+		if err != nil {
+			return icuNode{}, err
+		}
+		if !p.consume('}') {
+			return icuNode{}, errors.New("unterminated case " + strconv.Quote(key))
+		}
+		n.cases[key] = body
+	}
+	if _, ok := n.cases["other"]; !ok {
+		return icuNode{}, p.errorf("%s %q has no other case", n.kind, name)
+	}
+
+	return n, nil
+}
+
+// word reads an argument name, type or case key.
+func (p *icuParser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\n,{}", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *icuParser) space() {
+	for p.pos < len(p.src) && strings.ContainsRune(" \t\n", rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *icuParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
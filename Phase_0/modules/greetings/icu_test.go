@@ -0,0 +1,107 @@
+package greetings_test
+
+import (
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestMessageFormat(t *testing.T) {
+	const (
+		guests  = "{count, plural, =0 {no guests} one {# guest} other {# guests}}"
+		polish  = "{count, plural, one {# osoba} few {# osoby} many {# osób} other {# osoby}}"
+		russian = "{count, plural, one {# гость} few {# гостя} many {# гостей} other {# гостя}}"
+		arabic  = "{count, plural, zero {z} one {o} two {t} few {f} many {m} other {x}}"
+	)
+	for _, tt := range []struct {
+		locale, text string
+		args         map[string]any
+		want         string
+	}{
+		{"en", "Hi, {names}!", map[string]any{"names": "Ann"}, "Hi, Ann!"},
+		{"en", guests, map[string]any{"count": 0}, "no guests"},
+		{"en", guests, map[string]any{"count": 1}, "1 guest"},
+		{"en", guests, map[string]any{"count": 2}, "2 guests"},
+		{"pl", polish, map[string]any{"count": 1}, "1 osoba"},
+		{"pl", polish, map[string]any{"count": 3}, "3 osoby"},
+		{"pl", polish, map[string]any{"count": 5}, "5 osób"},
+		{"pl", polish, map[string]any{"count": 22}, "22 osoby"},
+		{"pl", polish, map[string]any{"count": 12}, "12 osób"},
+		{"ru", russian, map[string]any{"count": 21}, "21 гость"},
+		{"ru", russian, map[string]any{"count": 24}, "24 гостя"},
+		{"ru", russian, map[string]any{"count": 11}, "11 гостей"},
+		{"ar", arabic, map[string]any{"count": 0}, "z"},
+		{"ar", arabic, map[string]any{"count": 2}, "t"},
+		{"ar", arabic, map[string]any{"count": 3}, "f"},
+		{"ar", arabic, map[string]any{"count": 11}, "m"},
+		{"ar", arabic, map[string]any{"count": 100}, "x"},
+		{"en", "{gender, select, female {her} male {him} other {them}}", map[string]any{"gender": "female"}, "her"},
+		{"en", "{gender, select, female {her} male {him} other {them}}", map[string]any{"gender": "robot"}, "them"},
+		{"en", "'{names}' and '#' and it''s", nil, "{names} and # and it's"},
+		{"en", "{count, plural, other {'#' is #}}", map[string]any{"count": int64(4)}, "# is 4"},
+	} {
+		m, err := greetings.ParseMessageFormat(tt.locale, tt.text)
+		if err != nil {
+			t.Errorf("ParseMessageFormat(%s, %q): %v", tt.locale, tt.text, err)
+			continue
+		}
+		got, err := m.Format(tt.args)
+		if err != nil {
+			t.Errorf("%s: Format(%q, %v): %v", tt.locale, tt.text, tt.args, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Format(%q, %v) = %q, want %q", tt.locale, tt.text, tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestMessageFormatErrors(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		args map[string]any
+	}{
+		{"{}", nil},
+		{"{count, plural, one {# guest}}", nil},
+		{"{count, ordinal, other {#}}", nil},
+		{"{count, plural, other {a} other {b}}", nil},
+		{"{count, plural, other {a}", nil},
+		{"{names", nil},
+		{"Hi, {names}!", map[string]any{}},
+		{"{count, plural, other {#}}", map[string]any{"count": "three"}},
+	} {
+		m, err := greetings.ParseMessageFormat("en", tt.text)
+		if err == nil {
+			_, err = m.Format(tt.args)
+		}
+		if greetings.CodeOf(err) != greetings.TemplateError {
+			t.Errorf("%q with %v: error = %v, want a TemplateError", tt.text, tt.args, err)
+		}
+	}
+}
+
+func TestGroupPlurals(t *testing.T) {
+	names := []string{"Ala", "Ola", "Ela", "Iza", "Ewa"}
+	for _, tt := range []struct {
+		locale string
+		n      int
+		want   string
+	}{
+		{"pl", 2, "Witajcie, Ala i Ola! Witamy 2 osoby."},
+		{"pl", 5, "Witajcie, Ala, Ola, Ela, Iza i Ewa! Witamy 5 osób."},
+		{"ru", 2, "Здравствуйте, Ala и Ola! У нас 2 гостя."},
+		{"ru", 5, "Здравствуйте, Ala, Ola, Ela, Iza и Ewa! У нас 5 гостей."},
+	} {
+		g, err := greetings.New(greetings.WithLocale(tt.locale))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := g.HelloGroup(names[:tt.n])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: HelloGroup(%d names) = %q, want %q", tt.locale, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package greetings
+
+import "context"
+
+// Interface is the set of Greeter methods most code calls. Depend on it
+// instead of *Greeter where a test double should stand in, such as the
+// FakeGreeter in package greetingstest.
+type Interface interface {
+	Hello(name string) (string, error)
+	HelloCtx(ctx context.Context, name string) (string, error)
+	Hellos(names []string) ([]string, error)
+	HelloGroup(names []string) (string, error)
+	Greet(name string) (Greeting, error)
+	GreetPerson(p Person) (Greeting, error)
+	GreetCtx(ctx context.Context, p Person) (Greeting, error)
+	Locale() string
+}
+
+var _ Interface = (*Greeter)(nil)
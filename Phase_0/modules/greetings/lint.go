@@ -0,0 +1,229 @@
+package greetings
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+)
+
+// Problem is something LintTemplate found wrong with a template.
+type Problem struct {
+	// Line and Col locate the problem in the source, counting from one,
+	// with Col in characters. Col is zero when only the line is known, and
+	// both are zero for problems with the template as a whole.
+	Line, Col int
+
+	// Msg says what is wrong.
+	Msg string
+}
+
+// String returns the problem as "line:col: msg", leaving out what of the
+// position is unknown.
+func (p Problem) String() string {
+	switch {
+	case p.Line == 0:
+		return p.Msg
+	case p.Col == 0:
+		return fmt.Sprintf("%d: %s", p.Line, p.Msg)
+	default:
+		return fmt.Sprintf("%d:%d: %s", p.Line, p.Col, p.Msg)
+	}
+}
+
+// suspicious matches punctuation that is probably a typo: a space before a
+// comma or full stop, a doubled comma, or a full stop next to a comma or
+// another full stop that is not part of an ellipsis.
+var suspicious = regexp.MustCompile(` [,.]|,,|[.,][.,]`)
+
+// LintTemplate checks src, a text/template greeting as accepted by
+// WithTextTemplate, for mistakes, and returns what it finds in source
+// order; a clean template has no problems. Unlike ParseTemplate it does
+// not stop at the first one, and it also flags templates that are valid
+// but probably wrong: ones that never use {{.Name}}, and text with double
+// spaces or stray punctuation. It is meant for CI checks of template and
+// catalog repositories.
+func LintTemplate(src string) []Problem {
+
+	problems := lintDelims(src)
+	tmpl, err := template.New("lint").Option("missingkey=error").Funcs(templateFuncs).Parse(src)
+	if err != nil {
+		if len(problems) == 0 {
+			problems = append(problems, templateProblem(err))
+		}
+		return problems
+	}
+
+	l := &linter{src: src, tree: tmpl.Tree, refs: &varRefs{sample: make(map[string]any)}}
+	l.walk(tmpl.Tree.Root, true)
+	if !l.name {
+		l.problems = append(l.problems, Problem{Msg: "template never uses {{.Name}}"})
+	}
+	if !l.unknown && !l.refs.deep {
+		sample := TemplateData{Name: "Gladys", Pronouns: PronounsThey, Time: time.Now(), Locale: defaultLocale, Vars: l.refs.sample}
+		if err := tmpl.Execute(io.Discard, sample); err != nil {
+			l.problems = append(l.problems, templateProblem(err))
+		}
+	}
+	problems = append(problems, l.problems...)
+	slices.SortStableFunc(problems, func(a, b Problem) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Col - b.Col
+	})
+
+	return problems
+}
+
+// lintDelims reports action delimiters in src that do not pair up: a "}}"
+// outside any action, or a "{{" opened inside another or never closed.
+func lintDelims(src string) []Problem {
+
+	var problems []Problem
+	open := -1
+	for i := 0; i < len(src)-1; i++ {
+		switch src[i : i+2] {
+		case "{{":
+			if open >= 0 {
+				problems = append(problems, problemAt(src, open, "action opened with {{ is not closed before the next {{"))
+			}
+			open = i
+			i++
+		case "}}":
+			if open < 0 {
+				problems = append(problems, problemAt(src, i, "}} does not close an action"))
+			}
+			open = -1
+			i++
+		}
+	}
+	if open >= 0 {
+		problems = append(problems, problemAt(src, open, "action opened with {{ is never closed"))
+	}
+
+	return problems
+}
+
+// templateProblem turns an error from text/template, which reads
+// "template: lint:line:col: msg" with the column optional, into a Problem.
+func templateProblem(err error) Problem {
+
+	msg := strings.TrimPrefix(err.Error(), "template: lint:")
+	var p Problem
+	n, _ := fmt.Sscanf(msg, "%d:%d:", &p.Line, &p.Col)
+	if n == 0 {
+		return Problem{Msg: msg}
+	}
+	p.Msg = strings.TrimSpace(strings.SplitN(msg, ":", n+1)[n])
+
+	return p
+}
+
+// problemAt returns a Problem at byte offset off of src.
+func problemAt(src string, off int, msg string) Problem {
+	before := src[:off]
+	line := strings.Count(before, "\n") + 1
+	col := utf8.RuneCountInString(before[strings.LastIndexByte(before, '\n')+1:]) + 1
+	return Problem{Line: line, Col: col, Msg: msg}
+}
+
+// linter collects the problems in a parsed template.
+type linter struct {
+	src      string
+	tree     *parse.Tree
+	refs     *varRefs
+	name     bool // the template uses {{.Name}}
+	unknown  bool // it references a field TemplateData lacks
+	problems []Problem
+}
+
+// walk lints node. Fields are only checked where dot is the TemplateData,
+// which it is not inside range and with, but {{.Name}} counts anywhere.
+func (l *linter) walk(node parse.Node, top bool) {
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			l.walk(child, top)
+		}
+	case *parse.TextNode:
+		l.lintText(n)
+	case *parse.ActionNode:
+		l.walk(n.Pipe, top)
+	case *parse.IfNode:
+		l.walk(n.Pipe, top)
+		l.walk(n.List, top)
+		l.walk(n.ElseList, top)
+	case *parse.RangeNode:
+		l.walk(n.Pipe, top)
+		l.walk(n.List, false)
+		l.walk(n.ElseList, top)
+	case *parse.WithNode:
+		l.walk(n.Pipe, top)
+		l.walk(n.List, false)
+		l.walk(n.ElseList, top)
+	case *parse.TemplateNode:
+		l.walk(n.Pipe, top)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				l.walk(arg, top)
+			}
+		}
+	case *parse.FieldNode:
+		l.name = l.name || n.Ident[0] == "Name"
+		if !top {
+			return
+		}
+		if !templateFields[n.Ident[0]] {
+			l.unknown = true
+			l.problems = append(l.problems, problemAt(l.src, int(n.Pos), fmt.Sprintf("unknown field .%s (available: .%s)",
+				n.Ident[0], strings.Join(slices.Sorted(maps.Keys(templateFields)), ", ."))))
+		}
+		if n.Ident[0] == "Vars" && len(n.Ident) > 1 {
+			l.refs.sample[n.Ident[1]] = "sample"
+			l.refs.deep = l.refs.deep || len(n.Ident) > 2
+		}
+	}
+}
+
+// lintText flags double spaces and suspicious punctuation in literal text.
+// Indentation and trailing white space are left alone.
+func (l *linter) lintText(n *parse.TextNode) {
+
+	off := int(n.Pos)
+	for line := range strings.SplitAfterSeq(string(n.Text), "\n") {
+		body, lead := line, 0
+		if off == 0 || l.src[off-1] == '\n' {
+			body = strings.TrimLeft(line, " \t")
+			lead = len(line) - len(body)
+		}
+		if strings.HasSuffix(body, "\n") {
+			body = strings.TrimRight(body, " \t\r\n")
+		}
+		if i := strings.Index(body, "  "); i >= 0 {
+			l.problems = append(l.problems, problemAt(l.src, off+lead+i, "double space"))
+		}
+		for _, m := range suspicious.FindAllStringIndex(line, -1) {
+			match := line[m[0]:m[1]]
+			if match == ".." && (strings.HasPrefix(line[m[0]:], "...") || m[0] > 0 && line[m[0]-1] == '.') {
+				continue // an ellipsis
+			}
+			l.problems = append(l.problems, problemAt(l.src, off+m[0], fmt.Sprintf("suspicious punctuation %q", match)))
+		}
+		off += len(line)
+	}
+}
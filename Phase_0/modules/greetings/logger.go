@@ -0,0 +1,107 @@
+package greetings
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// Logger receives a structured event for each greeting. *slog.Logger
+// satisfies it, so WithLogger(slog.Default()) is enough to start logging.
+type Logger interface {
+	LogAttrs(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr)
+}
+
+// WithLogger records every greeting the Greeter generates to l: a hash of
+// the recipients' names, the locale, the style, the experiment variant if
+// any and the duration, at Info level, or at Warn level with the error
+// when the greeting fails. Names are hashed so logs can correlate repeat
+// greetings without holding personal data; WithLogRedactor chooses how.
+// Durations are measured with the Greeter's Clock. A Greeter without a
+// Logger logs nothing; WithLogger(nil) is an error.
+func WithLogger(l Logger) Option {
+	return func(g *Greeter) error {
+		if nilLogger(l) {
+			return fmt.Errorf("greetings: nil logger")
+		}
+		// Look the clock and redactor up once all options are applied, so
+		// WithLogger needs no particular order with WithClock.
+		return Use(func(next Provider) Provider {
+			return loggerMiddleware(l, g.clock, g.logRedactor)(next)
+		})(g)
+	}
+}
+
+// WithLogRedactor makes WithLogger log the recipients' names as r gives
+// them, under "name", instead of the unsalted "name_hash". Use HashName
+// with a secret salt where the hash alone could be matched against a list
+// of names.
+func WithLogRedactor(r Redactor) Option {
+	return func(g *Greeter) error {
+		g.logRedactor = r
+		return nil
+	}
+}
+
+// LoggerMiddleware returns a Middleware that times each call to the next
+// provider with clock and records it to l. A nil l logs nothing.
+func LoggerMiddleware(l Logger, clock Clock) Middleware {
+	if nilLogger(l) {
+		return func(next Provider) Provider { return next }
+	}
+	return loggerMiddleware(l, clock, nil)
+}
+
+// nilLogger reports whether l is nil, including a nil *slog.Logger, whose
+// methods panic.
+func nilLogger(l Logger) bool {
+	sl, ok := l.(*slog.Logger)
+	return l == nil || ok && sl == nil
+}
+
+// loggerMiddleware is LoggerMiddleware logging names through redact, or
+// as name_hash when redact is nil.
+func loggerMiddleware(l Logger, clock Clock, redact Redactor) Middleware {
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+			start := clock.Now()
+			greeting, err := next.Greet(ctx, req)
+			name := slog.String("name_hash", nameHash(req.Recipients))
+			if redact != nil {
+				redacted := make([]string, len(req.Recipients))
+				for i, p := range req.Recipients {
+					redacted[i] = redact(p.Name)
+				}
+				name = slog.String("name", strings.Join(redacted, ", "))
+			}
+			attrs := []slog.Attr{
+				name,
+				slog.String("locale", req.Locale),
+				slog.String("style", req.Style),
+				slog.Duration("duration", clock.Now().Sub(start)),
+			}
+			if greeting.Variant != "" {
+				attrs = append(attrs, slog.String("experiment", greeting.Experiment), slog.String("variant", greeting.Variant))
+			}
+			if err != nil {
+				l.LogAttrs(ctx, slog.LevelWarn, "greeting failed", append(attrs, slog.Any("error", err))...)
+			} else {
+				l.LogAttrs(ctx, slog.LevelInfo, "greeting", attrs...)
+			}
+			return greeting, err
+		})
+	}
+}
+
+// nameHash returns a stable FNV-1a digest of the recipients' names.
+func nameHash(people []Person) string {
+	h := fnv.New64a()
+	for _, p := range people {
+		h.Write([]byte(p.Name))
+		h.Write([]byte{0})
+	}
+	return strconv.FormatUint(h.Sum64(), 16)
+}
@@ -0,0 +1,46 @@
+package greetings_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestWithLoggerNil(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		l    greetings.Logger
+	}{
+		{"nil interface", nil},
+		{"nil *slog.Logger", (*slog.Logger)(nil)},
+	} {
+		if _, err := greetings.New(greetings.WithLogger(tt.l)); err == nil {
+			t.Errorf("%s: New succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	g, err := greetings.New(greetings.WithLogger(slog.New(slog.NewTextHandler(&buf, nil))),
+		greetings.WithLogRedactor(greetings.RedactName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Hello("Alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"level=INFO", "msg=greeting", "name=[redacted]", "locale=en"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log %q does not contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "Alice") {
+		t.Errorf("log %q contains the name", out)
+	}
+}
@@ -0,0 +1,49 @@
+package greetings
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultMarkdownEmphasis wraps the sentence holding the name in
+// FormatMarkdown output.
+const defaultMarkdownEmphasis = "**"
+
+// markdownEscaper backslash-escapes the characters Markdown would
+// otherwise read as formatting.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`",
+	"[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "|", `\|`,
+)
+
+// WithMarkdownEmphasis sets the marker FormatMarkdown wraps the sentence
+// holding the name in: "**" (the default) for bold, "*" or "_" for italics,
+// "~~" for strike-through or "`" for code. The empty string leaves the
+// sentence plain.
+func WithMarkdownEmphasis(marker string) Option {
+	return func(g *Greeter) error {
+		if strings.Trim(marker, "*_~`") != "" {
+			return fmt.Errorf("greetings: invalid Markdown emphasis %q", marker)
+		}
+		g.markdownEmphasis = marker
+		return nil
+	}
+}
+
+// markdown renders greeting as Markdown, emphasizing the sentence that
+// holds the name with marker and italicizing the rest.
+func markdown(greeting Greeting, marker string) string {
+
+	s := split(greeting)
+	head := markdownEscaper.Replace(s.before + s.name + s.rest)
+	if head != "" {
+		head = marker + head + marker
+	}
+	tail := strings.TrimLeft(s.tail, " ")
+	if tail == "" {
+		return head
+	}
+	space := s.tail[:len(s.tail)-len(tail)]
+
+	return head + space + "_" + markdownEscaper.Replace(tail) + "_"
+}
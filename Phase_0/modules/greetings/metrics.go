@@ -0,0 +1,111 @@
+package greetings
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+)
+
+// Metrics receives one observation per greeting a Greeter generates. It is
+// deliberately small so any monitoring stack can back it: ExpvarMetrics
+// publishes to expvar, and a Prometheus adapter is a few lines over a
+// CounterVec keyed by locale and a Histogram of latency seconds.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveGreeting records a greeting attempt in locale that took
+	// latency and failed with err, or succeeded when err is nil.
+	ObserveGreeting(locale string, latency time.Duration, err error)
+}
+
+// WithMetrics reports every greeting the Greeter generates to m. It is a
+// shorthand for Use(MetricsMiddleware(m, clock)) with the Greeter's Clock.
+func WithMetrics(m Metrics) Option {
+	return func(g *Greeter) error {
+		if nilMetrics(m) {
+			return fmt.Errorf("greetings: nil metrics")
+		}
+		return Use(func(next Provider) Provider {
+			return MetricsMiddleware(m, g.clock)(next)
+		})(g)
+	}
+}
+
+// nilMetrics reports whether m is nil, including a nil *ExpvarMetrics,
+// whose ObserveGreeting panics.
+func nilMetrics(m Metrics) bool {
+	em, ok := m.(*ExpvarMetrics)
+	return m == nil || ok && em == nil
+}
+
+// MetricsMiddleware returns a Middleware that times each call to the next
+// provider with clock and reports it to m.
+func MetricsMiddleware(m Metrics, clock Clock) Middleware {
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+			start := clock.Now()
+			greeting, err := next.Greet(ctx, req)
+			m.ObserveGreeting(req.Locale, clock.Now().Sub(start), err)
+			return greeting, err
+		})
+	}
+}
+
+// LatencyBuckets are the upper bounds of the latency histogram kept by
+// ExpvarMetrics.
+var LatencyBuckets = []time.Duration{
+	10 * time.Microsecond,
+	100 * time.Microsecond,
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// ExpvarMetrics is a Metrics backed by the expvar package, which serves it
+// as JSON on /debug/vars. It keeps these variables:
+//
+//	greetings          total greetings attempted
+//	errors             attempts that failed
+//	locales            attempts per locale
+//	latency_seconds    cumulative histogram: attempts faster than each
+//	                   LatencyBuckets bound, keyed "le_<bound>", and "le_+Inf"
+//	latency_sum        total latency in seconds
+type ExpvarMetrics struct {
+	greetings  expvar.Int
+	errors     expvar.Int
+	locales    expvar.Map
+	latency    expvar.Map
+	latencySum expvar.Float
+}
+
+// NewExpvarMetrics returns an ExpvarMetrics published under name. Like
+// expvar.Publish, it panics if name is already in use.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	m := new(ExpvarMetrics)
+	vars := new(expvar.Map)
+	vars.Set("greetings", &m.greetings)
+	vars.Set("errors", &m.errors)
+	vars.Set("locales", &m.locales)
+	vars.Set("latency_seconds", &m.latency)
+	vars.Set("latency_sum", &m.latencySum)
+	expvar.Publish(name, vars)
+
+	return m
+}
+
+// ObserveGreeting implements Metrics.
+func (m *ExpvarMetrics) ObserveGreeting(locale string, latency time.Duration, err error) {
+	m.greetings.Add(1)
+	if err != nil {
+		m.errors.Add(1)
+	}
+	m.locales.Add(locale, 1)
+	for _, bound := range LatencyBuckets {
+		if latency <= bound {
+			m.latency.Add("le_"+bound.String(), 1)
+		}
+	}
+	m.latency.Add("le_+Inf", 1)
+	m.latencySum.Add(latency.Seconds())
+}
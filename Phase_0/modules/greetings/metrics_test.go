@@ -0,0 +1,40 @@
+package greetings_test
+
+import (
+	"expvar"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestWithMetricsRejectsNil(t *testing.T) {
+	for _, m := range []greetings.Metrics{nil, (*greetings.ExpvarMetrics)(nil)} {
+		if _, err := greetings.New(greetings.WithMetrics(m)); greetings.CodeOf(err) != greetings.InvalidConfig {
+			t.Errorf("WithMetrics(%#v): New error = %v, want InvalidConfig", m, err)
+		}
+	}
+}
+
+func TestExpvarMetrics(t *testing.T) {
+	m := greetings.NewExpvarMetrics("greetings_test_metrics")
+	g, err := greetings.New(greetings.WithMetrics(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if _, err := g.Hello("Ada"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	vars := expvar.Get("greetings_test_metrics").(*expvar.Map)
+	for name, want := range map[string]string{
+		"greetings": "2",
+		"errors":    "0",
+		"locales":   `{"en": 2}`,
+	} {
+		if got := vars.Get(name).String(); got != want {
+			t.Errorf("%s = %s, want %s", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package greetings
+
+import (
+	"context"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Middleware wraps a Provider to add behavior around greeting generation,
+// such as logging, metrics or filtering, without touching the provider
+// itself.
+type Middleware func(next Provider) Provider
+
+// Use wraps the Greeter's provider in mw. The first middleware is the
+// outermost: Use(a, b) calls a, which calls b, which calls the provider.
+// Use may be given more than once; later calls wrap inside earlier ones.
+func Use(mw ...Middleware) Option {
+	return func(g *Greeter) error {
+		g.middleware = append(g.middleware, mw...)
+		return nil
+	}
+}
+
+// chain wraps p in mw so that mw[0] runs first.
+func chain(p Provider, mw []Middleware) Provider {
+	for i := len(mw) - 1; i >= 0; i-- {
+		p = mw[i](p)
+	}
+	return p
+}
+
+// Capitalize is a Middleware that upper-cases the first letter of every
+// message, for templates that start with the name of someone who typed it
+// in lower case.
+func Capitalize(next Provider) Provider {
+	return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+		greeting, err := next.Greet(ctx, req)
+		if err != nil {
+			return greeting, err
+		}
+		r, size := utf8.DecodeRuneInString(greeting.Message)
+		if r != utf8.RuneError && !unicode.IsUpper(r) {
+			greeting.Message = string(unicode.ToUpper(r)) + greeting.Message[size:]
+		}
+		return greeting, nil
+	})
+}
@@ -0,0 +1,80 @@
+package greetings
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"example.com/stringsx"
+)
+
+// Mode says how a Greeter treats input it cannot greet as given.
+type Mode int
+
+const (
+	// Strict fails fast: empty, blank and invalid names, unknown locales
+	// and template variables that were not given are errors. It is the
+	// default.
+	Strict Mode = iota
+
+	// Lenient makes a best effort instead: names are made valid UTF-8,
+	// stripped of control characters and cut to the length limit, and
+	// names left empty are greeted by the fallback name (see
+	// WithFallbackName). Unknown locales fall back to English, and
+	// template variables that were not given render as empty text.
+	Lenient
+)
+
+// DefaultFallbackName is the name a Lenient Greeter greets empty names by
+// unless WithFallbackName says otherwise.
+const DefaultFallbackName = "friend"
+
+// WithMode selects whether the Greeter fails on bad input or works around
+// it. Libraries that embed a Greeter usually want Strict, the default, so
+// mistakes surface; user-facing front ends may prefer Lenient.
+func WithMode(m Mode) Option {
+	return func(g *Greeter) error {
+		if m != Strict && m != Lenient {
+			return fmt.Errorf("greetings: invalid mode %d", int(m))
+		}
+		g.mode = m
+		return nil
+	}
+}
+
+// WithFallbackName sets the name a Lenient Greeter greets empty names by,
+// such as "there" for "Hi, there. Welcome!".
+func WithFallbackName(name string) Option {
+	return func(g *Greeter) error {
+		if strings.TrimSpace(name) == "" {
+			return errors.New("greetings: empty fallback name")
+		}
+		g.fallbackName = name
+		return nil
+	}
+}
+
+// Mode reports whether the Greeter is Strict or Lenient.
+func (g *Greeter) Mode() Mode {
+	return g.mode
+}
+
+// mend makes name greetable for a Lenient Greeter: valid UTF-8, without
+// control characters, and not blank.
+func (g *Greeter) mend(name string) string {
+
+	name = Sanitize(strings.ToValidUTF8(name, "�"))
+	if strings.TrimSpace(name) == "" {
+		return g.fallbackName
+	}
+
+	return name
+}
+
+// clip cuts name to the Greeter's length limit for a Lenient Greeter.
+func (g *Greeter) clip(name string) string {
+	if g.maxNameLength <= 0 {
+		return name
+	}
+	return stringsx.Truncate(name, g.maxNameLength)
+}
@@ -0,0 +1,253 @@
+// Package names takes personal names apart, so that a greeting can use
+// only the part people are addressed by. It understands the given-name
+// first order ("Ada Lovelace"), the surname-first order with a comma
+// ("Lovelace, Ada"), honorific prefixes and generational or academic
+// suffixes ("Dr. Ada Lovelace Jr."), and surnames of several words
+// introduced by particles such as van, de or al ("Ludwig van Beethoven").
+//
+// Parsing is heuristic and tuned for Western name orders; names it cannot
+// make sense of end up whole in Given.
+package names
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Name is a personal name split into its components. Any of them can be
+// empty.
+type Name struct {
+	Prefix  string // honorific, such as "Dr."
+	Given   string // the name the person is addressed by, such as "Ada"
+	Middle  string // further given names, space separated
+	Surname string // family name, particles included, such as "van Beethoven"
+	Suffix  string // such as "Jr." or "PhD", space separated
+}
+
+// prefixes are honorifics recognized before a name, lower-cased and
+// without their final dot.
+var prefixes = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "mx": true, "miss": true,
+	"dr": true, "prof": true, "rev": true, "sir": true, "dame": true,
+	"lord": true, "lady": true, "fr": true,
+}
+
+// suffixes are generational and academic suffixes recognized after a
+// name, lower-cased and without dots.
+var suffixes = map[string]bool{
+	"jr": true, "sr": true, "ii": true, "iii": true, "iv": true, "v": true,
+	"phd": true, "md": true, "esq": true, "dds": true, "mba": true,
+}
+
+// numerals are the suffixes that are also surnames or initials on their
+// own, as in "Ada V", so they only count as suffixes after a full name.
+var numerals = map[string]bool{"ii": true, "iii": true, "iv": true, "v": true}
+
+// particles introduce surnames of several words.
+var particles = map[string]bool{
+	"van": true, "von": true, "de": true, "da": true, "del": true,
+	"della": true, "der": true, "den": true, "di": true, "du": true,
+	"la": true, "le": true, "ter": true, "ten": true, "bin": true,
+	"bint": true, "ibn": true, "al": true, "el": true, "dos": true,
+	"das": true, "do": true, "st.": true,
+}
+
+// Parse splits name into its components. White space is collapsed and
+// commas separate a leading surname or trailing suffixes:
+//
+//	Parse("Dr. Ada Lovelace Jr.") // Prefix "Dr.", Given "Ada", Surname "Lovelace", Suffix "Jr."
+//	Parse("Lovelace, Ada")        // Given "Ada", Surname "Lovelace"
+//	Parse("Ludwig van Beethoven") // Given "Ludwig", Surname "van Beethoven"
+func Parse(name string) Name {
+
+	var n Name
+	head, tail, comma := strings.Cut(name, ",")
+	if comma {
+		rest := strings.Fields(strings.ReplaceAll(tail, ",", " "))
+		if allSuffixes(rest) && (!anyNumeral(rest) || fullName(strings.Fields(head))) {
+			n.Suffix = strings.Join(rest, " ")
+			comma = false
+		}
+	}
+
+	if comma {
+		// Surname-first: "Lovelace, Ada Augusta Jr."
+		words, suffix := trimSuffixes(strings.Fields(strings.ReplaceAll(tail, ",", " ")), 1)
+		words, n.Prefix = trimPrefixes(words)
+		n.Suffix = suffix
+		n.Surname = strings.Join(strings.Fields(head), " ")
+		if len(words) > 0 {
+			n.Given = words[0]
+			n.Middle = strings.Join(words[1:], " ")
+		}
+		return n
+	}
+
+	words, suffix := trimSuffixes(strings.Fields(head), 2)
+	if suffix != "" {
+		n.Suffix = strings.TrimSpace(suffix + " " + n.Suffix)
+	}
+	words, n.Prefix = trimPrefixes(words)
+	switch len(words) {
+	case 0:
+	case 1:
+		n.Given = words[0]
+	default:
+		n.Given = words[0]
+		last := len(words) - 1
+		for i := 1; i < last; i++ {
+			if isParticle(words[i]) {
+				last = i
+				break
+			}
+		}
+		n.Middle = strings.Join(words[1:last], " ")
+		n.Surname = strings.Join(words[last:], " ")
+	}
+
+	return n
+}
+
+// First returns the given name in name, or name itself when Parse finds
+// none.
+func First(name string) string {
+	if n := Parse(name); n.Given != "" {
+		return n.Given
+	}
+	return name
+}
+
+// Monogram returns the initials of name for avatars and the like: those of
+// the given name and the surname, upper-cased, with every part of a
+// hyphenated name counted and surname particles, such as van or al-, left
+// out.
+//
+//	Monogram("Ada Lovelace")         // "AL"
+//	Monogram("Jean-Luc Picard")      // "JLP"
+//	Monogram("Ludwig van Beethoven") // "LB"
+//	Monogram("Dr. Ada Lovelace Jr.") // "AL"
+//
+// A name of one word gives one initial, and one without letters none.
+func Monogram(name string) string {
+
+	n := Parse(name)
+	var b strings.Builder
+	for _, word := range strings.Fields(n.Given + " " + n.Surname) {
+		for part := range strings.FieldsFuncSeq(word, isJoiner) {
+			if particles[strings.ToLower(part)] {
+				continue
+			}
+			if before, after, ok := cutApostrophe(part); ok && particles[strings.ToLower(before)] {
+				part = after // al'Hassan
+			}
+			if i := strings.IndexFunc(part, unicode.IsLetter); i >= 0 {
+				r := []rune(part[i:])[0]
+				b.WriteRune(unicode.ToUpper(r))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// isJoiner reports whether r is a hyphen joining the parts of a compound
+// name, as in "Jean-Luc".
+func isJoiner(r rune) bool {
+	return r == '-' || r == '\u2010'
+}
+
+// cutApostrophe splits word around its first apostrophe, straight or
+// curly.
+func cutApostrophe(word string) (before, after string, found bool) {
+	i := strings.IndexAny(word, "'’")
+	if i < 0 {
+		return word, "", false
+	}
+	_, size := utf8.DecodeRuneInString(word[i:])
+	return word[:i], word[i+size:], true
+}
+
+// String returns the components of n joined by spaces.
+func (n Name) String() string {
+	var parts []string
+	for _, s := range []string{n.Prefix, n.Given, n.Middle, n.Surname, n.Suffix} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// trimPrefixes removes leading honorifics from words and returns them
+// joined by spaces.
+func trimPrefixes(words []string) ([]string, string) {
+	i := countPrefixes(words)
+	return words[i:], strings.Join(words[:i], " ")
+}
+
+// countPrefixes returns how many leading words of words are honorifics,
+// leaving at least one word that is not.
+func countPrefixes(words []string) int {
+	i := 0
+	for i < len(words)-1 && prefixes[key(words[i])] {
+		i++
+	}
+	return i
+}
+
+// trimSuffixes removes trailing suffixes from words, keeping at least one
+// word, and returns them joined by spaces. A numeral is only a suffix
+// after need words that are not honorifics: a given name and a surname, or
+// just the given name when the surname came before a comma.
+func trimSuffixes(words []string, need int) ([]string, string) {
+	need += countPrefixes(words)
+	i := len(words)
+	for i > 1 && suffixes[key(words[i-1])] {
+		if numerals[key(words[i-1])] && i-1 < need {
+			break
+		}
+		i--
+	}
+	return words[:i], strings.Join(words[i:], " ")
+}
+
+// fullName reports whether words hold a given name and a surname besides
+// any honorifics.
+func fullName(words []string) bool {
+	return len(words)-countPrefixes(words) >= 2
+}
+
+// anyNumeral reports whether words include a numeral suffix.
+func anyNumeral(words []string) bool {
+	return slices.ContainsFunc(words, func(w string) bool { return numerals[key(w)] })
+}
+
+// allSuffixes reports whether words is non-empty and made only of suffixes.
+func allSuffixes(words []string) bool {
+	for _, w := range words {
+		if !suffixes[key(w)] {
+			return false
+		}
+	}
+	return len(words) > 0
+}
+
+// isParticle reports whether word introduces a surname, as "van" does, or
+// is a particle attached with a hyphen or apostrophe, as in "al-Hassan".
+func isParticle(word string) bool {
+	lower := strings.ToLower(word)
+	if particles[lower] {
+		return true
+	}
+	if i := strings.IndexAny(lower, "-'’"); i > 0 {
+		return particles[lower[:i]]
+	}
+	return false
+}
+
+// key lower-cases word and drops its dots, so "Dr." and "dr" compare equal.
+func key(word string) string {
+	return strings.ToLower(strings.ReplaceAll(word, ".", ""))
+}
@@ -0,0 +1,94 @@
+package names_test
+
+import (
+	"testing"
+
+	"example.com/greetings/names"
+)
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		want names.Name
+	}{
+		{"Ada Lovelace", names.Name{Given: "Ada", Surname: "Lovelace"}},
+		{"Lovelace, Ada", names.Name{Given: "Ada", Surname: "Lovelace"}},
+		{"Dr. Ada Lovelace Jr.", names.Name{Prefix: "Dr.", Given: "Ada", Surname: "Lovelace", Suffix: "Jr."}},
+		{"  Ada   Augusta  Lovelace ", names.Name{Given: "Ada", Middle: "Augusta", Surname: "Lovelace"}},
+		{"Lovelace, Dr. Ada Augusta Jr.", names.Name{Prefix: "Dr.", Given: "Ada", Middle: "Augusta", Surname: "Lovelace", Suffix: "Jr."}},
+		{"Ada Lovelace, PhD", names.Name{Given: "Ada", Surname: "Lovelace", Suffix: "PhD"}},
+		{"Ada Lovelace, Jr., PhD", names.Name{Given: "Ada", Surname: "Lovelace", Suffix: "Jr. PhD"}},
+		{"Ada", names.Name{Given: "Ada"}},
+		{"Dr.", names.Name{Given: "Dr."}},
+		{"Jr.", names.Name{Given: "Jr."}},
+		{"", names.Name{}},
+
+		// Particles start the surname.
+		{"Ludwig van Beethoven", names.Name{Given: "Ludwig", Surname: "van Beethoven"}},
+		{"Vincent Willem van Gogh", names.Name{Given: "Vincent", Middle: "Willem", Surname: "van Gogh"}},
+		{"Charles de Gaulle", names.Name{Given: "Charles", Surname: "de Gaulle"}},
+		{"Maria de la Cruz", names.Name{Given: "Maria", Surname: "de la Cruz"}},
+		{"Omar al-Hassan", names.Name{Given: "Omar", Surname: "al-Hassan"}},
+		{"Omar Khalid al-Hassan", names.Name{Given: "Omar", Middle: "Khalid", Surname: "al-Hassan"}},
+		{"van Beethoven, Ludwig", names.Name{Given: "Ludwig", Surname: "van Beethoven"}},
+
+		// Numerals are suffixes only after a given name and a surname.
+		{"Henry Ford II", names.Name{Given: "Henry", Surname: "Ford", Suffix: "II"}},
+		{"Henry Ford, II", names.Name{Given: "Henry", Surname: "Ford", Suffix: "II"}},
+		{"Ford, Henry II", names.Name{Given: "Henry", Surname: "Ford", Suffix: "II"}},
+		{"Ada V", names.Name{Given: "Ada", Surname: "V"}},
+		{"Dr. Ada V", names.Name{Prefix: "Dr.", Given: "Ada", Surname: "V"}},
+		{"Ada, V", names.Name{Given: "V", Surname: "Ada"}},
+		{"Ada Jr.", names.Name{Given: "Ada", Suffix: "Jr."}},
+	} {
+		if got := names.Parse(tt.name); got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFirst(t *testing.T) {
+	for name, want := range map[string]string{
+		"Dr. Ada Lovelace Jr.": "Ada",
+		"Lovelace, Ada":        "Ada",
+		"Ada V":                "Ada",
+		"":                     "",
+	} {
+		if got := names.First(name); got != want {
+			t.Errorf("First(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestNameString(t *testing.T) {
+	if got := names.Parse("Lovelace, Dr. Ada Augusta Jr.").String(); got != "Dr. Ada Augusta Lovelace Jr." {
+		t.Errorf("String = %q", got)
+	}
+}
+
+func TestMonogram(t *testing.T) {
+	for name, want := range map[string]string{
+		"Ada Lovelace":         "AL",
+		"Jean-Luc Picard":      "JLP",
+		"Ludwig van Beethoven": "LB",
+		"Dr. Ada Lovelace Jr.": "AL",
+		"Lovelace, Ada":        "AL",
+		"Ada Augusta Lovelace": "AL",
+		"Omar al-Hassan":       "OH",
+		"Omar al'Hassan":       "OH",
+		"Charles de Gaulle":    "CG",
+		"Henry Ford II":        "HF",
+		"Ada V":                "AV",
+		"ada lovelace":         "AL",
+		"Ángel Ñúñez":          "ÁÑ",
+		"Cher":                 "C",
+		"(Ada) 'Lovelace'":     "AL",
+		"Mary\u2010Kate Olsen": "MKO",
+		"":                     "",
+		"1234":                 "",
+	} {
+		if got := names.Monogram(name); got != want {
+			t.Errorf("Monogram(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package greetings
+
+import (
+	"strings"
+
+	"example.com/greetings/names"
+	"example.com/stringsx"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalize tidies a name for display: it trims surrounding white space,
+// collapses runs of internal white space to a single space and puts the
+// result in Unicode Normalization Form C, so "  alíce  " becomes
+// "alíce" with a precomposed í.
+func Normalize(name string) string {
+	return norm.NFC.String(strings.Join(strings.Fields(name), " "))
+}
+
+// TitleCase upper-cases the first letter of every word of name using the
+// casing rules of locale. Letters that are already capitals stay that way,
+// so "mcDonald" is not flattened to "Mcdonald". A locale that does not
+// parse gets the language-neutral rules of stringsx.TitleCase.
+func TitleCase(name, locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return stringsx.TitleCase(name)
+	}
+	return cases.Title(tag, cases.NoLower).String(name)
+}
+
+// WithNormalize makes the Greeter run every name through Normalize before
+// greeting it. A name that is only white space then fails with ErrEmptyName.
+func WithNormalize() Option {
+	return func(g *Greeter) error {
+		g.normalize = true
+		return nil
+	}
+}
+
+// WithTitleCase is like WithNormalize but also applies TitleCase in the
+// Greeter's locale, so "  alice   smith " is greeted as "Alice Smith".
+func WithTitleCase() Option {
+	return func(g *Greeter) error {
+		g.normalize = true
+		g.titleCase = true
+		return nil
+	}
+}
+
+// WithFirstNameOnly makes the Greeter greet people by their given name
+// alone, as parsed by names.First: "Dr. Ada Lovelace" is greeted as
+// "Ada". Aliases apply to the given name.
+func WithFirstNameOnly() Option {
+	return func(g *Greeter) error {
+		g.firstNameOnly = true
+		return nil
+	}
+}
+
+// normalizeName applies the Greeter's normalization settings to name.
+func (g *Greeter) normalizeName(name string) string {
+	if g.firstNameOnly {
+		name = names.First(name)
+	}
+	if !g.normalize {
+		return name
+	}
+	name = Normalize(name)
+	if g.titleCase {
+		name = TitleCase(name, g.locale)
+	}
+	return name
+}
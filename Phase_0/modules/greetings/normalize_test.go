@@ -0,0 +1,25 @@
+package greetings_test
+
+import (
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestWithFirstNameOnly(t *testing.T) {
+	g, err := greetings.New(greetings.WithFirstNameOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"Dr. Ada Lovelace Jr.": "Hi, Ada. Welcome!",
+		"Lovelace, Ada":        "Hi, Ada. Welcome!",
+		"Ludwig van Beethoven": "Hi, Ludwig. Welcome!",
+		"Ada V":                "Hi, Ada. Welcome!",
+		"Cher":                 "Hi, Cher. Welcome!",
+	} {
+		if got, err := g.Hello(name); got != want || err != nil {
+			t.Errorf("Hello(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package greetings
+
+import "time"
+
+// Person is someone to greet, for callers that know more than a name.
+type Person struct {
+	Name string
+
+	// Title is an honorific such as "Dr.", "Prof." or "Mx.". It overrides
+	// the Greeter's WithHonorific default; leave it empty to use that.
+	Title string
+
+	// Pronouns are used by templates that refer to the person. The zero
+	// value means neutral they/them phrasing.
+	Pronouns Pronouns
+
+	// Pronunciation tells how to say Name. It is only used by Greeters
+	// built with WithPronunciation.
+	Pronunciation Pronunciation
+
+	// Birthday, when set, gets the person the catalog's birthday greeting
+	// on the anniversary of its month and day. The year and time are
+	// ignored; see WithLeapDay for February 29th.
+	Birthday time.Time
+}
+
+// Pronouns are the English pronouns templates use to refer to a recipient.
+// Plural records whether they take plural verb agreement, as "they" does,
+// which the Is and Has helpers rely on.
+type Pronouns struct {
+	Subject    string // she, he, they
+	Object     string // her, him, them
+	Possessive string // her, his, their
+	Plural     bool
+}
+
+// Common pronoun sets. Custom sets, such as ze/hir, are ordinary values.
+var (
+	PronounsShe  = Pronouns{Subject: "she", Object: "her", Possessive: "her"}
+	PronounsHe   = Pronouns{Subject: "he", Object: "him", Possessive: "his"}
+	PronounsThey = Pronouns{Subject: "they", Object: "them", Possessive: "their", Plural: true}
+)
+
+// Is returns the form of "to be" that agrees with the subject pronoun, so
+// "{{.Pronouns.Subject}} {{.Pronouns.Is}} here" reads "she is here" or
+// "they are here".
+func (p Pronouns) Is() string {
+	if p.Plural {
+		return "are"
+	}
+	return "is"
+}
+
+// Has returns the form of "to have" that agrees with the subject pronoun.
+func (p Pronouns) Has() string {
+	if p.Plural {
+		return "have"
+	}
+	return "has"
+}
+
+// orNeutral returns p, or PronounsThey when p is the zero value.
+func (p Pronouns) orNeutral() Pronouns {
+	if p == (Pronouns{}) {
+		return PronounsThey
+	}
+	return p
+}
+
+// WithHonorific sets a title, such as "Dr.", put in front of (or, depending
+// on the locale, after) every name the Greeter greets.
+func WithHonorific(title string) Option {
+	return func(g *Greeter) error {
+		g.honorific = title
+		return nil
+	}
+}
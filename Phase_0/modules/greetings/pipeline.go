@@ -0,0 +1,96 @@
+package greetings
+
+import (
+	"context"
+
+	"example.com/pipeline"
+)
+
+// Stages are the steps a Greeter takes to greet one person, as pipeline
+// stages: Normalize cleans up the name and fills in the default title,
+// Validate turns away names the Greeter will not greet, Render has the
+// provider write the greeting and Format shortens and formats it.
+//
+// Greeter.Stages returns a Greeter's own steps. Replace one to change that
+// step alone, e.g. to look names up in a directory before validating them,
+// then Greet with the result or Run it over a stream of people.
+type Stages struct {
+	Normalize pipeline.Stage[Person, Person]
+	Validate  pipeline.Stage[Person, Person]
+	Render    pipeline.Stage[Person, Greeting]
+	Format    pipeline.Stage[Greeting, Greeting]
+}
+
+// Stages returns the steps GreetCtx takes.
+func (g *Greeter) Stages() Stages {
+	return Stages{
+		Normalize: g.normalizePerson,
+		Validate:  g.validatePerson,
+		Render:    g.renderPerson,
+		Format:    g.formatGreeting,
+	}
+}
+
+// Greet runs p through the stages one after the other. With a Greeter's
+// own stages it greets exactly like GreetCtx.
+func (s Stages) Greet(ctx context.Context, p Person) (Greeting, error) {
+
+	if err := ctx.Err(); err != nil {
+		return Greeting{}, err
+	}
+	greet := pipeline.Compose(pipeline.Compose(s.Normalize, s.Validate), pipeline.Compose(s.Render, s.Format))
+
+	return greet(ctx, p)
+}
+
+// Run greets people as they arrive, each stage in its own goroutine, and
+// sends one item per person in the order received. An item's Index is the
+// position of its person in the stream and its Err the error of the stage
+// that failed it. The channel is closed once people is closed or ctx is
+// done.
+func (s Stages) Run(ctx context.Context, people <-chan Person) <-chan pipeline.Item[Greeting] {
+
+	normalized := pipeline.Then(ctx, pipeline.Source(ctx, people), s.Normalize)
+	validated := pipeline.Then(ctx, normalized, s.Validate)
+	rendered := pipeline.Then(ctx, validated, s.Render)
+
+	return pipeline.Then(ctx, rendered, s.Format)
+}
+
+// normalizePerson is the normalize stage: it cleans up p's name and gives
+// p the Greeter's honorific when it has no title.
+func (g *Greeter) normalizePerson(_ context.Context, p Person) (Person, error) {
+
+	name, err := g.cleanName(p.Name)
+	if err != nil {
+		return Person{}, err
+	}
+	p.Name = name
+	if p.Title == "" {
+		p.Title = g.honorific
+	}
+
+	return p, nil
+}
+
+// validatePerson is the validate stage: it checks and filters p's name.
+func (g *Greeter) validatePerson(_ context.Context, p Person) (Person, error) {
+
+	name, err := g.checkName(p.Name)
+	if err != nil {
+		return Person{}, err
+	}
+	p.Name = name
+
+	return p, nil
+}
+
+// renderPerson is the render stage for a single recipient.
+func (g *Greeter) renderPerson(ctx context.Context, p Person) (Greeting, error) {
+	return g.provide(ctx, []Person{p}, nil)
+}
+
+// formatGreeting is the format stage; it cannot fail.
+func (g *Greeter) formatGreeting(_ context.Context, greeting Greeting) (Greeting, error) {
+	return g.finish(greeting), nil
+}
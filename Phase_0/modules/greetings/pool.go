@@ -0,0 +1,30 @@
+package greetings
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the largest buffer returned to the pool. Rendering
+// an unusually long greeting should not pin its memory for good.
+const maxPooledBuffer = 4 << 10
+
+// buffers recycles the buffers greetings are rendered into, so servers
+// under sustained load do not allocate and collect one per greeting.
+var buffers = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	return buffers.Get().(*bytes.Buffer)
+}
+
+// putBuffer returns b to the pool. The caller must not use b afterwards.
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	buffers.Put(b)
+}
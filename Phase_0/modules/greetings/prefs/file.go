@@ -0,0 +1,142 @@
+package prefs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore is a Store that persists preferences to a JSON file holding
+// an object from user ID to Prefs. Every change rewrites the file through
+// a temporary file and a rename, so it is never left half written.
+type FileStore struct {
+	mu     sync.Mutex
+	mem    MemoryStore
+	path   string
+	closed bool
+}
+
+// OpenFile loads the preferences file at path. A missing file is an empty
+// store, created on the first change.
+func OpenFile(path string) (*FileStore, error) {
+
+	s := &FileStore{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var prefs map[string]Prefs
+	if err := json.Unmarshal(data, &prefs); err != nil {
+		return nil, fmt.Errorf("prefs: %s: %w", path, err)
+	}
+	for userID, p := range prefs {
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("prefs: %s: user %q: %w", path, userID, err)
+		}
+		s.mem.set(userID, p)
+	}
+
+	return s, nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(ctx context.Context, userID string) (Prefs, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return Prefs{}, false, ErrClosed
+	}
+	return s.mem.Get(ctx, userID)
+}
+
+// Set implements Store. The file is written before the change is visible
+// to Get.
+func (s *FileStore) Set(_ context.Context, userID string, p Prefs) error {
+
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	prefs := maps.Clone(s.mem.prefs)
+	if prefs == nil {
+		prefs = make(map[string]Prefs)
+	}
+	prefs[userID] = p
+	if err := s.write(prefs); err != nil {
+		return err
+	}
+	s.mem.set(userID, p)
+
+	return nil
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(ctx context.Context, userID string) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrClosed
+	}
+	if _, ok := s.mem.prefs[userID]; !ok {
+		return nil
+	}
+	prefs := maps.Clone(s.mem.prefs)
+	delete(prefs, userID)
+	if err := s.write(prefs); err != nil {
+		return err
+	}
+
+	return s.mem.Delete(ctx, userID)
+}
+
+// write replaces the file's contents with prefs. s.mu must be held.
+func (s *FileStore) write(prefs map[string]Prefs) error {
+
+	data, err := json.MarshalIndent(prefs, "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), s.path)
+}
+
+// Close marks the store closed; later calls fail with ErrClosed. The file
+// is always up to date, so there is nothing to flush.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
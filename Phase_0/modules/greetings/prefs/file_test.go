@@ -0,0 +1,89 @@
+package prefs_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"example.com/greetings/prefs"
+)
+
+func TestFileStorePersists(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "prefs.json")
+
+	s, err := prefs.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenFile created the file before any change: %v", err)
+	}
+	ada := prefs.Prefs{Name: "Ada", Locale: "fr", Formality: "formal"}
+	if err := s.Set(ctx, "ada", ada); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(ctx, "bob", prefs.Prefs{Name: "Bob"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete(ctx, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(ctx, "cy", prefs.Prefs{}); err == nil {
+		t.Error("Set of invalid prefs succeeded")
+	}
+	s.Close()
+
+	s, err = prefs.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got, ok, err := s.Get(ctx, "ada"); got != ada || !ok || err != nil {
+		t.Errorf("reopened Get(ada) = %+v, %v, %v; want %+v", got, ok, err, ada)
+	}
+	for _, id := range []string{"bob", "cy"} {
+		if _, ok, _ := s.Get(ctx, id); ok {
+			t.Errorf("reopened store has %s", id)
+		}
+	}
+	if leftovers, _ := filepath.Glob(path + ".*"); len(leftovers) != 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestFileStoreClosed(t *testing.T) {
+	ctx := context.Background()
+	s, err := prefs.OpenFile(filepath.Join(t.TempDir(), "prefs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	if _, _, err := s.Get(ctx, "ada"); !errors.Is(err, prefs.ErrClosed) {
+		t.Errorf("Get after Close = %v", err)
+	}
+	if err := s.Set(ctx, "ada", prefs.Prefs{Name: "Ada"}); !errors.Is(err, prefs.ErrClosed) {
+		t.Errorf("Set after Close = %v", err)
+	}
+	if err := s.Delete(ctx, "ada"); !errors.Is(err, prefs.ErrClosed) {
+		t.Errorf("Delete after Close = %v", err)
+	}
+}
+
+func TestOpenFileRejectsBadFiles(t *testing.T) {
+	for name, content := range map[string]string{
+		"not json":     `{"ada":`,
+		"invalid user": `{"ada":{"locale":"fr"}}`,
+		"bad register": `{"ada":{"name":"Ada","formality":"posh"}}`,
+	} {
+		path := filepath.Join(t.TempDir(), "prefs.json")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := prefs.OpenFile(path); err == nil {
+			t.Errorf("%s: OpenFile succeeded", name)
+		}
+	}
+}
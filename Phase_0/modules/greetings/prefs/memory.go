@@ -0,0 +1,53 @@
+package prefs
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps preferences in memory. The zero value
+// is an empty store ready to use.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	prefs map[string]Prefs
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return new(MemoryStore)
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, userID string) (Prefs, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	p, ok := s.prefs[userID]
+	return p, ok, nil
+}
+
+// Set implements Store.
+func (s *MemoryStore) Set(_ context.Context, userID string, p Prefs) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(userID, p)
+	return nil
+}
+
+// set stores p. s.mu must be held.
+func (s *MemoryStore) set(userID string, p Prefs) {
+	if s.prefs == nil {
+		s.prefs = make(map[string]Prefs)
+	}
+	s.prefs[userID] = p
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.prefs, userID)
+	return nil
+}
@@ -0,0 +1,148 @@
+// Package prefs remembers how each user likes to be greeted: in which
+// locale, how formally and by what name. A Store keeps the preferences;
+// MemoryStore and FileStore cover tests and single processes. A Greeter
+// looks them up so callers can greet a user by ID alone:
+//
+//	g := prefs.NewGreeter(store)
+//	greeting, err := g.GreetUser(ctx, "u-42")
+package prefs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"example.com/greetings"
+)
+
+var (
+	// ErrUnknownUser is returned by GreetUser for a user with no
+	// preferences in the store.
+	ErrUnknownUser = errors.New("prefs: unknown user")
+
+	// ErrClosed is returned by stores used after Close.
+	ErrClosed = errors.New("prefs: store closed")
+)
+
+// Prefs are one user's greeting preferences. Empty fields leave the
+// Greeter's settings alone.
+type Prefs struct {
+	// Locale is a BCP 47 tag such as "pt-BR".
+	Locale string `json:"locale,omitempty"`
+
+	// Formality is "casual", "neutral" or "formal".
+	Formality string `json:"formality,omitempty"`
+
+	// Nickname is what the user likes to be called. Name is greeted
+	// when it is empty.
+	Nickname string `json:"nickname,omitempty"`
+	Name     string `json:"name,omitempty"`
+}
+
+// Validate reports whether p can be greeted.
+func (p Prefs) Validate() error {
+	if p.Nickname == "" && p.Name == "" {
+		return errors.New("prefs: no name or nickname")
+	}
+	if p.Formality != "" {
+		if _, err := greetings.ParseFormality(p.Formality); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// name returns what to call the user.
+func (p Prefs) name() string {
+	if p.Nickname != "" {
+		return p.Nickname
+	}
+	return p.Name
+}
+
+// Store keeps preferences by user ID. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	// Get returns the preferences of userID, with false if it has none.
+	Get(ctx context.Context, userID string) (Prefs, bool, error)
+
+	// Set replaces the preferences of userID. It fails if p does not
+	// pass Validate.
+	Set(ctx context.Context, userID string, p Prefs) error
+
+	// Delete forgets userID. Deleting an unknown user is not an error.
+	Delete(ctx context.Context, userID string) error
+}
+
+// Greeter greets users by ID with their stored preferences. It keeps one
+// greetings.Greeter per locale and formality in use, built on first need.
+// A Greeter is safe for concurrent use.
+type Greeter struct {
+	store Store
+	opts  []greetings.Option
+
+	mu       sync.Mutex
+	greeters map[key]*greetings.Greeter
+}
+
+type key struct {
+	locale    string
+	formality string
+}
+
+// NewGreeter returns a Greeter reading preferences from s and configuring
+// its greeters with opts, which the preferences override.
+func NewGreeter(s Store, opts ...greetings.Option) *Greeter {
+	return &Greeter{store: s, opts: opts, greeters: make(map[key]*greetings.Greeter)}
+}
+
+// GreetUser greets userID by nickname in their preferred locale and
+// register. It fails with ErrUnknownUser if the store has nothing for
+// userID.
+func (g *Greeter) GreetUser(ctx context.Context, userID string) (greetings.Greeting, error) {
+
+	p, ok, err := g.store.Get(ctx, userID)
+	if err != nil {
+		return greetings.Greeting{}, err
+	}
+	if !ok {
+		return greetings.Greeting{}, fmt.Errorf("%w: %q", ErrUnknownUser, userID)
+	}
+	greeter, err := g.greeter(p)
+	if err != nil {
+		return greetings.Greeting{}, err
+	}
+
+	return greeter.GreetCtx(ctx, greetings.Person{Name: p.name()})
+}
+
+// greeter returns the greetings.Greeter for p's locale and formality.
+func (g *Greeter) greeter(p Prefs) (*greetings.Greeter, error) {
+
+	k := key{p.Locale, p.Formality}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if greeter, ok := g.greeters[k]; ok {
+		return greeter, nil
+	}
+
+	opts := append([]greetings.Option(nil), g.opts...)
+	if p.Locale != "" {
+		opts = append(opts, greetings.WithLocale(p.Locale))
+	}
+	if p.Formality != "" {
+		f, err := greetings.ParseFormality(p.Formality)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, greetings.WithFormality(f))
+	}
+	greeter, err := greetings.New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	g.greeters[k] = greeter
+
+	return greeter, nil
+}
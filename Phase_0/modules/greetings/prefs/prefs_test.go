@@ -0,0 +1,94 @@
+package prefs_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/greetings"
+	"example.com/greetings/prefs"
+)
+
+func TestGreetUser(t *testing.T) {
+	ctx := context.Background()
+	store := prefs.NewMemoryStore()
+	g := prefs.NewGreeter(store, greetings.WithEmoji(true))
+	for _, tt := range []struct {
+		prefs prefs.Prefs
+		want  string
+	}{
+		{prefs.Prefs{Name: "Dee"}, "Hi, Dee. Welcome! 👋"},
+		{prefs.Prefs{Name: "Ada Lovelace", Nickname: "Ada", Formality: "formal"}, "Dear Ada, welcome. 👋"},
+		{prefs.Prefs{Name: "Bob", Locale: "es"}, "Hola, Bob. Te damos la bienvenida. 🎉"},
+		{prefs.Prefs{Name: "Cy", Locale: "fr", Formality: "casual"}, "Salut Cy\u00a0! 👋"},
+		{prefs.Prefs{Name: "Zed", Locale: "fr"}, "Bonjour, Zed. Bienvenue\u00a0! 👋"},
+	} {
+		if err := store.Set(ctx, "u-1", tt.prefs); err != nil {
+			t.Fatal(err)
+		}
+		greeting, err := g.GreetUser(ctx, "u-1")
+		if err != nil || greeting.Message != tt.want {
+			t.Errorf("GreetUser with %+v = %q, %v; want %q", tt.prefs, greeting.Message, err, tt.want)
+		}
+	}
+}
+
+func TestGreetUserErrors(t *testing.T) {
+	ctx := context.Background()
+	store := prefs.NewMemoryStore()
+	if err := store.Set(ctx, "ed", prefs.Prefs{Nickname: "Eddie", Locale: "xx"}); err != nil {
+		t.Fatal(err)
+	}
+	g := prefs.NewGreeter(store)
+
+	if _, err := g.GreetUser(ctx, "nobody"); !errors.Is(err, prefs.ErrUnknownUser) {
+		t.Errorf("GreetUser(nobody) error = %v, want ErrUnknownUser", err)
+	}
+	if _, err := g.GreetUser(ctx, "ed"); greetings.CodeOf(err) != greetings.UnknownLocale {
+		t.Errorf("GreetUser(ed) error = %v, want UnknownLocale", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		prefs prefs.Prefs
+		ok    bool
+	}{
+		{prefs.Prefs{Name: "Ada"}, true},
+		{prefs.Prefs{Nickname: "Ada"}, true},
+		{prefs.Prefs{Name: "Ada", Formality: "formal"}, true},
+		{prefs.Prefs{Locale: "fr"}, false},
+		{prefs.Prefs{Name: "Ada", Formality: "posh"}, false},
+	} {
+		if err := tt.prefs.Validate(); (err == nil) != tt.ok {
+			t.Errorf("Validate(%+v) = %v", tt.prefs, err)
+		}
+		if err := prefs.NewMemoryStore().Set(context.Background(), "u", tt.prefs); (err == nil) != tt.ok {
+			t.Errorf("Set(%+v) = %v", tt.prefs, err)
+		}
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	var s prefs.MemoryStore // the zero value is ready to use
+	if _, ok, err := s.Get(ctx, "ada"); ok || err != nil {
+		t.Errorf("Get from an empty store = %v, %v", ok, err)
+	}
+	if err := s.Delete(ctx, "ada"); err != nil {
+		t.Errorf("Delete of an unknown user = %v", err)
+	}
+	want := prefs.Prefs{Name: "Ada", Locale: "fr"}
+	if err := s.Set(ctx, "ada", want); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok, err := s.Get(ctx, "ada"); got != want || !ok || err != nil {
+		t.Errorf("Get = %+v, %v, %v; want %+v", got, ok, err, want)
+	}
+	if err := s.Delete(ctx, "ada"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := s.Get(ctx, "ada"); ok {
+		t.Error("Get after Delete found the user")
+	}
+}
@@ -0,0 +1,45 @@
+package greetings
+
+import "fmt"
+
+// Pronunciation tells how to say a name.
+type Pronunciation struct {
+	// Respelling is a reader-friendly guide such as "shi-VAWN" for
+	// Siobhán, shown in greeting hints.
+	Respelling string `json:"respelling,omitempty"`
+
+	// IPA is the name in the International Phonetic Alphabet, such as
+	// "ʃɪˈvɔːn", for speech engines.
+	IPA string `json:"ipa,omitempty"`
+}
+
+// IsZero reports whether p says nothing about the name.
+func (p Pronunciation) IsZero() bool {
+	return p == Pronunciation{}
+}
+
+// WithPronunciation makes the Greeter pass on how recipients say their
+// names (see Person.Pronunciation) in Greeting.Pronunciation, and use it:
+// a respelling is appended to the message as a hint,
+//
+//	Hi, Siobhán. Welcome! (Siobhán — pronounced shi-VAWN)
+//
+// except in FormatSSML, which leaves the hint out and instead wraps the
+// name in a phoneme tag when its IPA is known, so speech engines say it
+// right: <phoneme alphabet="ipa" ph="ʃɪˈvɔːn">Siobhán</phoneme>. Group
+// greetings are left alone.
+func WithPronunciation() Option {
+	return func(g *Greeter) error {
+		g.pronunciation = true
+		return nil
+	}
+}
+
+// pronunciationHint returns the hint WithPronunciation appends to
+// greeting's message, or "" when it has no respelling.
+func pronunciationHint(greeting Greeting) string {
+	if greeting.Pronunciation.Respelling == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (%s — pronounced %s)", greeting.Name, greeting.Pronunciation.Respelling)
+}
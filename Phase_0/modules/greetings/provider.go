@@ -0,0 +1,199 @@
+package greetings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Provider renders greetings. The Greeter validates and normalizes input,
+// then hands a Request to its Provider; by default that is the built-in
+// template provider, but anything from a translation API to an LLM can
+// stand in via WithProvider.
+type Provider interface {
+	Greet(ctx context.Context, req Request) (Greeting, error)
+}
+
+// ProviderFunc adapts an ordinary function to the Provider interface.
+type ProviderFunc func(ctx context.Context, req Request) (Greeting, error)
+
+// Greet returns f(ctx, req).
+func (f ProviderFunc) Greet(ctx context.Context, req Request) (Greeting, error) {
+	return f(ctx, req)
+}
+
+// Request is what a Provider is asked to greet.
+type Request struct {
+	// Recipients are the people to greet: one for an ordinary greeting,
+	// several for a group greeting. Their names are already validated and
+	// normalized, and Title carries the Greeter's default honorific when
+	// the caller gave none.
+	Recipients []Person
+
+	// Locale is the resolved catalog locale, such as "pt".
+	Locale string
+
+	Formality Formality
+
+	// Style is the registered style the Greeter was configured with, or "".
+	Style string
+
+	// Time is the current time according to the Greeter's Clock.
+	Time time.Time
+
+	// Vars are the extra template variables of the greeting: the
+	// Greeter's WithVars overlaid with those passed to GreetWith. Do not
+	// modify them.
+	Vars map[string]any
+
+	// LastSeen is when the single recipient was last greeted, or the zero
+	// time when they are new or nobody knows. The Greeter leaves it zero;
+	// middleware that keeps history, such as package history's Recorder,
+	// sets it before passing the request on.
+	LastSeen time.Time
+
+	// VisitCount is how many times the single recipient has been greeted,
+	// this greeting included, or 0 when nobody knows. Like LastSeen it is
+	// set by history middleware.
+	VisitCount int
+}
+
+// DaysSince returns the number of whole days from LastSeen to Time, or 0
+// when LastSeen is unknown.
+func (r Request) DaysSince() int {
+	if r.LastSeen.IsZero() {
+		return 0
+	}
+	return max(int(r.Time.Sub(r.LastSeen)/(24*time.Hour)), 0)
+}
+
+// names lists the recipients' names joined the way msg's language does.
+func (r Request) names(msg Message, oxfordComma bool) string {
+	names := make([]string, len(r.Recipients))
+	for i, p := range r.Recipients {
+		names[i] = p.Name
+	}
+	return msg.joinNames(names, oxfordComma)
+}
+
+// WithProvider makes the Greeter render greetings with p instead of its
+// catalog and templates. The Greeter still validates names, applies
+// normalization and fills in Name, Locale and GeneratedAt when p leaves
+// them empty. See WithBreaker for falling back to the catalog while p is
+// down.
+func WithProvider(p Provider) Option {
+	return func(g *Greeter) error {
+		if p == nil {
+			return fmt.Errorf("greetings: nil provider")
+		}
+		g.provider = p
+		return nil
+	}
+}
+
+// templateProvider is the default Provider: it renders a catalog message,
+// or a text/template when the Greeter has one.
+type templateProvider struct {
+	message      Message
+	template     string
+	punctuation  string
+	textTemplate *Template
+	emoji        string
+	oxfordComma  bool
+	leapDay      LeapDay
+	isolate      bool
+	lenient      bool
+
+	// group renders group greetings in place of template when set.
+	group *MessageFormat
+
+	// birthday and welcomeBack are the catalog's whole messages for
+	// birthdays and returning visitors, unless the Greeter's template,
+	// punctuation or register overrides the catalog.
+	birthday    string
+	welcomeBack string
+}
+
+func (p *templateProvider) Greet(ctx context.Context, req Request) (Greeting, error) {
+
+	if len(req.Recipients) == 0 {
+		return Greeting{}, ErrNoNames
+	}
+
+	display := make([]string, len(req.Recipients))
+	for i, r := range req.Recipients {
+		display[i] = p.message.withTitle(r.Title, r.Name)
+		if p.isolate {
+			display[i] = Isolate(display[i])
+		}
+	}
+	data := TemplateData{
+		Name:     p.message.joinNames(display, p.oxfordComma),
+		Pronouns: PronounsThey,
+		Time:     req.Time,
+		Locale:   req.Locale,
+		Emoji:    p.emoji,
+		Vars:     req.Vars,
+	}
+	if len(req.Recipients) == 1 {
+		data.Title = req.Recipients[0].Title
+		data.Pronouns = req.Recipients[0].Pronouns.orNeutral()
+		data.Returning, data.LastSeen, data.DaysSince = !req.LastSeen.IsZero(), req.LastSeen, req.DaysSince()
+		data.VisitCount = req.VisitCount
+	}
+	greeting := Greeting{
+		Name:        req.names(p.message, p.oxfordComma),
+		Locale:      req.Locale,
+		GeneratedAt: req.Time,
+	}
+
+	if p.textTemplate != nil {
+		if p.lenient {
+			data.Vars = p.textTemplate.withDefaults(data.Vars)
+		}
+		message, err := p.textTemplate.Execute(data)
+		if err != nil {
+			return Greeting{}, err
+		}
+		greeting.Message = message
+		return greeting, nil
+	}
+
+	b := getBuffer()
+	defer putBuffer(b)
+	switch {
+	case len(req.Recipients) == 1 && p.welcomeBack != "" && data.Returning:
+		fmt.Fprintf(b, p.welcomeBack, data.Name)
+		greeting.Salutation = salutation(p.welcomeBack)
+	case len(req.Recipients) == 1 && p.birthday != "" &&
+		IsBirthday(req.Recipients[0].Birthday, req.Time, p.leapDay):
+		fmt.Fprintf(b, p.birthday, data.Name)
+		greeting.Salutation = salutation(p.birthday)
+	case len(req.Recipients) > 1 && p.group != nil:
+		message, err := p.group.Format(map[string]any{"names": data.Name, "count": len(req.Recipients)})
+		if err != nil {
+			return Greeting{}, err
+		}
+		before, _, _ := strings.Cut(message, data.Name)
+		greeting.Salutation = strings.TrimRight(before, " ,、،")
+		b.WriteString(message)
+	default:
+		template := p.template
+		if strings.HasSuffix(data.Name, ".") {
+			// A name ending in an abbreviation, like "Alice M." or
+			// "Jr.", already has the full stop the template may add.
+			template = strings.Replace(template, "%v.", "%v", 1)
+		}
+		fmt.Fprintf(b, template, data.Name)
+		b.WriteString(p.punctuation)
+		greeting.Salutation = salutation(p.template)
+	}
+	if data.Emoji != "" {
+		b.WriteString(" ")
+		b.WriteString(data.Emoji)
+	}
+	greeting.Message = b.String()
+
+	return greeting, nil
+}
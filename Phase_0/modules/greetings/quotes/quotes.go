@@ -0,0 +1,205 @@
+// Package quotes adds a quote of the day to greetings. A Source supplies
+// quotes by locale; Builtin holds a small embedded collection of proverbs
+// and sayings, and callers can plug in their own with a List, a Catalog
+// loaded from JSON or any type implementing Source. The quote of a day is
+// the same for everyone greeted that day, and changes at midnight in the
+// greeting's time zone:
+//
+//	g, err := greetings.New(quotes.WithQuote())
+//	// "Hi, Alice. Welcome! “Well begun is half done.” — Aristotle"
+package quotes
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"example.com/greetings"
+)
+
+// Quote is a saying and who said it.
+type Quote struct {
+	Text   string `json:"text"`
+	Author string `json:"author,omitempty"`
+}
+
+// marks holds the opening and closing quotation marks of the languages
+// that do not use English ones.
+var marks = map[string][2]string{
+	"ar": {"«", "»"},
+	"de": {"„", "“"},
+	"es": {"«", "»"},
+	"fr": {"«\u00a0", "\u00a0»"},
+	"he": {"„", "”"},
+	"ja": {"「", "」"},
+	"pl": {"„", "”"},
+	"ru": {"«", "»"},
+}
+
+// String returns the quote in English quotation marks, followed by its
+// author: “Well begun is half done.” — Aristotle.
+func (q Quote) String() string {
+	return q.In("en")
+}
+
+// In returns the quote as String does, but with the quotation marks of
+// locale's language, such as « » for French.
+func (q Quote) In(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	m, ok := marks[language]
+	if !ok {
+		m = [2]string{"“", "”"}
+	}
+	s := m[0] + q.Text + m[1]
+	if q.Author != "" {
+		s += " — " + q.Author
+	}
+	return s
+}
+
+// Source supplies the quotes for a locale, in a stable order. It returns
+// none for locales it has nothing for. Implementations must be safe for
+// concurrent use.
+type Source interface {
+	Quotes(locale string) []Quote
+}
+
+// SourceFunc adapts an ordinary function to the Source interface.
+type SourceFunc func(locale string) []Quote
+
+// Quotes returns f(locale).
+func (f SourceFunc) Quotes(locale string) []Quote {
+	return f(locale)
+}
+
+// List is a Source of the same quotes for every locale.
+type List []Quote
+
+// Quotes implements Source.
+func (l List) Quotes(string) []Quote {
+	return l
+}
+
+// Catalog is a Source of quotes by locale. A locale without an entry is
+// answered from its language alone, "pt" for "pt-BR".
+type Catalog map[string][]Quote
+
+// Quotes implements Source.
+func (c Catalog) Quotes(locale string) []Quote {
+	for {
+		if q, ok := c[locale]; ok {
+			return q
+		}
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			return nil
+		}
+		locale = locale[:i]
+	}
+}
+
+// Parse reads a Catalog from JSON mapping locales to lists of quotes:
+//
+//	{"en": [{"text": "Well begun is half done.", "author": "Aristotle"}]}
+func Parse(r io.Reader) (Catalog, error) {
+
+	var c Catalog
+	if err := json.NewDecoder(r).Decode(&c); err != nil {
+		return nil, fmt.Errorf("quotes: %w", err)
+	}
+	for locale, list := range c {
+		for i, q := range list {
+			if strings.TrimSpace(q.Text) == "" {
+				return nil, fmt.Errorf("quotes: quote %d of %q has no text", i+1, locale)
+			}
+		}
+	}
+
+	return c, nil
+}
+
+// Load reads a Catalog from the JSON file at path; see Parse.
+func Load(path string) (Catalog, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f)
+}
+
+//go:embed data/quotes.json
+var embedded embed.FS
+
+// Builtin holds proverbs and sayings in the locales of the greetings
+// package's built-in catalog.
+var Builtin Source = func() Catalog {
+	f, err := embedded.Open("data/quotes.json")
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	c, err := Parse(f)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}()
+
+// OfTheDay returns the quote s has for locale on the date of t, in t's own
+// location, with false if s has none. Every call for the same date and
+// locale picks the same quote.
+func OfTheDay(s Source, locale string, t time.Time) (Quote, bool) {
+	list := s.Quotes(locale)
+	if len(list) == 0 {
+		return Quote{}, false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(t.Format(time.DateOnly)))
+	return list[h.Sum32()%uint32(len(list))], true
+}
+
+// WithQuote appends the built-in quote of the day to the Greeter's
+// greetings; it is a shorthand for With(Builtin).
+func WithQuote() greetings.Option {
+	return With(Builtin)
+}
+
+// With appends s's quote of the day to the Greeter's greetings; it is a
+// shorthand for greetings.Use(Middleware(s)).
+func With(s Source) greetings.Option {
+	return greetings.Use(Middleware(s))
+}
+
+// Middleware returns a greetings Middleware that asks next for a greeting
+// and appends the quote of the day for the request's locale and time, in
+// that locale's quotation marks. Greetings in locales s has no quotes for
+// are left alone, as are skipped ones.
+func Middleware(s Source) greetings.Middleware {
+	return func(next greetings.Provider) greetings.Provider {
+		return greetings.ProviderFunc(func(ctx context.Context, req greetings.Request) (greetings.Greeting, error) {
+
+			greeting, err := next.Greet(ctx, req)
+			if err != nil || greeting.Skipped {
+				return greeting, err
+			}
+
+			if q, ok := OfTheDay(s, req.Locale, req.Time); ok {
+				// Japanese runs sentences together without spaces.
+				sep := " "
+				if strings.HasPrefix(req.Locale, "ja") {
+					sep = ""
+				}
+				greeting.Message += sep + q.In(req.Locale)
+			}
+
+			return greeting, nil
+		})
+	}
+}
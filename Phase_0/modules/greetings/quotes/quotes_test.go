@@ -0,0 +1,174 @@
+package quotes_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/greetingstest"
+	"example.com/greetings/quotes"
+)
+
+var wellBegun = quotes.Quote{Text: "Well begun is half done.", Author: "Aristotle"}
+
+func TestQuoteIn(t *testing.T) {
+	for _, tt := range []struct {
+		q      quotes.Quote
+		locale string
+		want   string
+	}{
+		{wellBegun, "en", "“Well begun is half done.” — Aristotle"},
+		{wellBegun, "pt-BR", "“Well begun is half done.” — Aristotle"},
+		{wellBegun, "de-AT", "„Well begun is half done.“ — Aristotle"},
+		{wellBegun, "es", "«Well begun is half done.» — Aristotle"},
+		{wellBegun, "fr", "«\u00a0Well begun is half done.\u00a0» — Aristotle"},
+		{wellBegun, "ja", "「Well begun is half done.」 — Aristotle"},
+		{wellBegun, "pl", "„Well begun is half done.” — Aristotle"},
+		{wellBegun, "ru", "«Well begun is half done.» — Aristotle"},
+		{quotes.Quote{Text: "Anon."}, "en", "“Anon.”"},
+	} {
+		if got := tt.q.In(tt.locale); got != tt.want {
+			t.Errorf("%v.In(%q) = %q, want %q", tt.q.Text, tt.locale, got, tt.want)
+		}
+	}
+	if got, want := wellBegun.String(), wellBegun.In("en"); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestCatalogFallsBackToLanguage(t *testing.T) {
+	c := quotes.Catalog{"pt": {{Text: "pt"}}, "pt-PT": {{Text: "pt-PT"}}}
+	for locale, want := range map[string]string{"pt": "pt", "pt-BR": "pt", "pt-PT": "pt-PT", "en": ""} {
+		var got string
+		if q := c.Quotes(locale); len(q) > 0 {
+			got = q[0].Text
+		}
+		if got != want {
+			t.Errorf("Quotes(%q) = %q, want %q", locale, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, tt := range []struct {
+		json, err string
+	}{
+		{`{"en": [{"text": "Hi", "author": "Me"}]}`, ""},
+		{`{"en": [{"text": "Hi"}, {"text": "  "}]}`, `quote 2 of "en" has no text`},
+		{`{"en": `, "quotes: unexpected EOF"},
+		{`["en"]`, "quotes: json: cannot unmarshal"},
+	} {
+		c, err := quotes.Parse(strings.NewReader(tt.json))
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("Parse(%s): %v", tt.json, err)
+		case tt.err == "" && len(c["en"]) != 1:
+			t.Errorf("Parse(%s) = %v, want one en quote", tt.json, c)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("Parse(%s) = %v, want an error containing %q", tt.json, err, tt.err)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	if err := os.WriteFile(path, []byte(`{"es": [{"text": "Hola"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := quotes.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q := c.Quotes("es-MX"); len(q) != 1 || q[0].Text != "Hola" {
+		t.Errorf("Quotes(es-MX) = %v, want Hola", q)
+	}
+	if _, err := quotes.Load(filepath.Join(t.TempDir(), "missing.json")); !os.IsNotExist(err) {
+		t.Errorf("Load of a missing file = %v, want a not-exist error", err)
+	}
+}
+
+func TestBuiltinCoversCatalogLocales(t *testing.T) {
+	for locale := range greetings.Builtin() {
+		if len(quotes.Builtin.Quotes(locale)) == 0 {
+			t.Errorf("Builtin has no quotes for %q", locale)
+		}
+	}
+}
+
+func TestOfTheDay(t *testing.T) {
+
+	list := quotes.List{{Text: "a"}, {Text: "b"}, {Text: "c"}, {Text: "d"}, {Text: "e"}}
+	morning := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	first, ok := quotes.OfTheDay(list, "en", morning)
+	if !ok {
+		t.Fatal("OfTheDay found no quote")
+	}
+	if q, _ := quotes.OfTheDay(list, "fr", morning.Add(15*time.Hour)); q != first {
+		t.Errorf("OfTheDay changed within a day: %q then %q", first.Text, q.Text)
+	}
+
+	// Over a few months every quote comes up.
+	seen := make(map[string]bool)
+	for d := range 90 {
+		q, _ := quotes.OfTheDay(list, "en", morning.AddDate(0, 0, d))
+		seen[q.Text] = true
+	}
+	if len(seen) != len(list) {
+		t.Errorf("OfTheDay picked %d of %d quotes over 90 days", len(seen), len(list))
+	}
+
+	if _, ok := quotes.OfTheDay(quotes.Catalog{}, "en", morning); ok {
+		t.Error("OfTheDay found a quote in an empty Catalog")
+	}
+}
+
+func TestOfTheDayUsesLocalDate(t *testing.T) {
+
+	list := quotes.List{{Text: "a"}, {Text: "b"}, {Text: "c"}, {Text: "d"}, {Text: "e"}}
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// 20:00 UTC on May 1st is already May 2nd in Tokyo.
+	utc := time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)
+	want, _ := quotes.OfTheDay(list, "ja", time.Date(2024, 5, 2, 9, 0, 0, 0, tokyo))
+	if got, _ := quotes.OfTheDay(list, "ja", utc.In(tokyo)); got != want {
+		t.Errorf("OfTheDay in Tokyo = %q, want May 2nd's %q", got.Text, want.Text)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+
+	clock := greetingstest.NewFakeClock(time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC))
+	src := quotes.Catalog{
+		"en": {wellBegun},
+		"ja": {{Text: "始めが肝心"}},
+	}
+	for _, tt := range []struct {
+		locale, want string
+	}{
+		{"en", "Hi, Ada. Welcome! “Well begun is half done.” — Aristotle"},
+		{"es", "Hola, Ada. Te damos la bienvenida."},
+	} {
+		g, err := greetings.New(quotes.With(src), greetings.WithClock(clock), greetings.WithLocale(tt.locale))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := g.Hello("Ada"); err != nil || got != tt.want {
+			t.Errorf("Hello(Ada) in %s = %q, %v, want %q", tt.locale, got, err, tt.want)
+		}
+	}
+
+	next := greetings.ProviderFunc(func(_ context.Context, req greetings.Request) (greetings.Greeting, error) {
+		return greetings.Greeting{Message: "こんにちは", Skipped: req.Locale == "skip"}, nil
+	})
+	p := quotes.Middleware(src)(next)
+	for locale, want := range map[string]string{"ja": "こんにちは「始めが肝心」", "skip": "こんにちは"} {
+		got, err := p.Greet(context.Background(), greetings.Request{Locale: locale, Time: clock.Now()})
+		if err != nil || got.Message != want {
+			t.Errorf("Greet in %s = %q, %v, want %q", locale, got.Message, err, want)
+		}
+	}
+}
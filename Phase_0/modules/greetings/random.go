@@ -0,0 +1,92 @@
+package greetings
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"strings"
+	"sync"
+)
+
+// formats is the pool of greeting templates RandomHello and StableHello
+// pick from.
+var formats = []string{
+	"Hi, %v!",
+	"Great to see you, %v!",
+	"Hail, %v!",
+}
+
+// RandomHello returns a greeting for the named person using a template
+// chosen at random from the pool, with the global random source.
+func RandomHello(name string) (string, error) {
+
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(formats[rand.IntN(len(formats))], name), nil
+}
+
+// lockedRand is a *rand.Rand made safe for concurrent use.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) IntN(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.IntN(n)
+}
+
+// WithRand makes the Greeter's RandomHello draw from r instead of the
+// global source, which lets tests pin the selection with a seeded
+// generator such as rand.New(rand.NewPCG(1, 2)). The Greeter serializes
+// its use of r.
+func WithRand(r *rand.Rand) Option {
+	return func(g *Greeter) error {
+		if r == nil {
+			return fmt.Errorf("greetings: nil random source")
+		}
+		g.rand = &lockedRand{r: r}
+		return nil
+	}
+}
+
+// RandomHello is like the package's RandomHello but checks the name as
+// the Greeter's other methods do and draws from the source set with
+// WithRand, if any.
+func (g *Greeter) RandomHello(name string) (string, error) {
+
+	name, err := g.checkName(name)
+	if err != nil {
+		return "", coded(InvalidName, err)
+	}
+	intN := rand.IntN
+	if g.rand != nil {
+		intN = g.rand.IntN
+	}
+
+	return fmt.Sprintf(formats[intN(len(formats))], name), nil
+}
+
+// StableHello is like RandomHello but picks the template by hashing the
+// name, ignoring case, so each person gets the same greeting every time
+// and on every machine without any stored state. Different names still
+// spread evenly over the pool.
+func StableHello(name string) (string, error) {
+
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf(formats[hashIndex(strings.ToLower(name), len(formats))], name), nil
+}
+
+// hashIndex maps key to an index below n with FNV-1a, which is stable
+// across processes and Go releases.
+func hashIndex(key string, n int) int {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return int(h.Sum64() % uint64(n))
+}
@@ -0,0 +1,55 @@
+package greetings_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestRandomHelloWithRand(t *testing.T) {
+	pool := map[string]bool{"Hi, Ann!": true, "Great to see you, Ann!": true, "Hail, Ann!": true}
+	greet := func(seed uint64) []string {
+		g, err := greetings.New(greetings.WithRand(rand.New(rand.NewPCG(seed, 2))))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for range 20 {
+			msg, err := g.RandomHello("Ann")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pool[msg] {
+				t.Fatalf("RandomHello(Ann) = %q, not from the pool", msg)
+			}
+			got = append(got, msg)
+		}
+		return got
+	}
+
+	first, again := greet(1), greet(1)
+	seen := make(map[string]bool)
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("greeting %d = %q, then %q with the same seed", i, first[i], again[i])
+		}
+		seen[first[i]] = true
+	}
+	if len(seen) != len(pool) {
+		t.Errorf("20 greetings used %d of the %d templates", len(seen), len(pool))
+	}
+}
+
+func TestRandomHelloErrors(t *testing.T) {
+	if _, err := greetings.New(greetings.WithRand(nil)); greetings.CodeOf(err) != greetings.InvalidConfig {
+		t.Errorf("New(WithRand(nil)) error = %v, want an InvalidConfig error", err)
+	}
+	g, err := greetings.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.RandomHello(""); greetings.CodeOf(err) != greetings.InvalidName {
+		t.Errorf("RandomHello(\"\") error = %v, want an InvalidName error", err)
+	}
+}
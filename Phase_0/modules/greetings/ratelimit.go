@@ -0,0 +1,128 @@
+package greetings
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket that caps how often a Greeter's provider is
+// called, for providers backed by external services with quotas. It holds
+// up to burst tokens, refills at rps tokens per second and spends one per
+// greeting. Install it with WithRateLimit. A RateLimiter is safe for
+// concurrent use and may be shared by several Greeters to enforce one
+// budget between them.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	clock  Clock
+}
+
+// NewRateLimiter returns a full RateLimiter allowing rps greetings per
+// second on average and bursts of up to burst. A burst below one is
+// raised to one; an rps of zero or less never limits.
+func NewRateLimiter(rps float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	if rps <= 0 {
+		rps = math.Inf(1)
+	}
+	return &RateLimiter{rate: rps, burst: float64(burst), tokens: float64(burst), clock: SystemClock}
+}
+
+// SetClock makes l refill its bucket by c's time instead of the system's,
+// so tests can step through refills. Wait still sleeps in real time for
+// the delay it computes. A nil c means SystemClock. SetClock must be
+// called before l is used.
+func (l *RateLimiter) SetClock(c Clock) {
+	if c == nil {
+		c = SystemClock
+	}
+	l.clock = c
+}
+
+// WithRateLimit puts l in front of the Greeter's provider. Greetings block
+// until a token is available or their context is done; TryGreet fails
+// fast with ErrRateLimited instead.
+func WithRateLimit(l *RateLimiter) Option {
+	return Use(l.Middleware)
+}
+
+// Allow spends a token if one is available and reports whether it did.
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait spends a token, blocking until one is available. It returns
+// ctx.Err() without spending a token if ctx is done first.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+
+	l.mu.Lock()
+	l.refill()
+	l.tokens--
+	delay := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// refill adds the tokens earned since the last call. l.mu must be held.
+func (l *RateLimiter) refill() {
+	now := l.clock.Now()
+	if math.IsInf(l.rate, 1) {
+		l.tokens = l.burst
+	} else if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	}
+	l.last = now
+}
+
+// tryKey marks contexts of TryGreet calls, which must not block.
+type tryKey struct{}
+
+// Middleware spends a token before asking next, waiting for one unless
+// the greeting came from TryGreet.
+func (l *RateLimiter) Middleware(next Provider) Provider {
+	return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+		if ctx.Value(tryKey{}) != nil {
+			if !l.Allow() {
+				return Greeting{}, ErrRateLimited
+			}
+		} else if err := l.Wait(ctx); err != nil {
+			return Greeting{}, err
+		}
+		return next.Greet(ctx, req)
+	})
+}
+
+// TryGreet is like GreetPerson but never waits on a rate limit: when the
+// Greeter's RateLimiter has no token to spare it fails at once with
+// ErrRateLimited.
+func (g *Greeter) TryGreet(p Person) (Greeting, error) {
+	return g.GreetCtx(context.WithValue(context.Background(), tryKey{}, true), p)
+}
@@ -0,0 +1,86 @@
+package greetings_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/greetingstest"
+)
+
+func TestTryGreetRateLimit(t *testing.T) {
+	clock := greetingstest.NewFakeClock(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	l := greetings.NewRateLimiter(2, 3)
+	l.SetClock(clock)
+	g, err := greetings.New(greetings.WithRateLimit(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, tt := range []struct {
+		advance time.Duration
+		want    []bool // whether each TryGreet gets through
+	}{
+		{0, []bool{true, true, true, false}},                  // the burst
+		{500 * time.Millisecond, []bool{true, false}},         // one token a half second
+		{250 * time.Millisecond, []bool{false}},               // half a token
+		{250 * time.Millisecond, []bool{true, false}},         // the other half
+		{time.Minute, []bool{true, true, true, false, false}}, // refills stop at the burst
+	} {
+		clock.Advance(tt.advance)
+		for j, want := range tt.want {
+			_, err := g.TryGreet(greetings.Person{Name: "Ann"})
+			if got := err == nil; got != want {
+				t.Errorf("step %d, greeting %d: TryGreet error = %v, want allowed = %v", i, j, err, want)
+			}
+			if err != nil && (!errors.Is(err, greetings.ErrRateLimited) || greetings.CodeOf(err) != greetings.ProviderUnavailable) {
+				t.Errorf("step %d, greeting %d: TryGreet error = %v, want %v", i, j, err, greetings.ErrRateLimited)
+			}
+		}
+	}
+}
+
+func TestRateLimitWait(t *testing.T) {
+	clock := greetingstest.NewFakeClock(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	l := greetings.NewRateLimiter(50, 1)
+	l.SetClock(clock)
+	g, err := greetings.New(greetings.WithRateLimit(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Greet("Ann"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The clock stands still, so the next token is 20ms of real waiting
+	// away.
+	start := time.Now()
+	if _, err := g.Greet("Bob"); err != nil {
+		t.Fatal(err)
+	}
+	if waited := time.Since(start); waited < 15*time.Millisecond {
+		t.Errorf("Greet waited %v for a token, want about 20ms", waited)
+	}
+	clock.Advance(20 * time.Millisecond) // repays the token Bob waited for
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if _, err := g.GreetCtx(ctx, greetings.Person{Name: "Carol"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GreetCtx with a short deadline = %v, want %v", err, context.DeadlineExceeded)
+	}
+	clock.Advance(20 * time.Millisecond)
+	if !l.Allow() {
+		t.Error("Allow() = false a token's time after a canceled wait, want the token back")
+	}
+}
+
+func TestRateLimitUnlimited(t *testing.T) {
+	l := greetings.NewRateLimiter(0, 1)
+	for i := range 100 {
+		if !l.Allow() {
+			t.Fatalf("Allow() = false on call %d with no rate, want true", i)
+		}
+	}
+}
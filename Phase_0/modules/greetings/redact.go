@@ -0,0 +1,33 @@
+package greetings
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Redactor stands in for a personal name in logs and audit records, so they
+// can be kept without holding the names of the people greeted. See
+// HashName and RedactName.
+type Redactor func(name string) string
+
+// HashName returns a Redactor that replaces each name with a keyed
+// SHA-256 digest of it, as 32 hex digits. Equal names get equal digests,
+// so records of the same person still line up, but without salt they
+// cannot be recomputed from a list of likely names. Keep salt as secret
+// as the records are sensitive, and keep it stable, since changing it
+// breaks the link to older records.
+func HashName(salt []byte) Redactor {
+	salt = append([]byte(nil), salt...)
+	return func(name string) string {
+		mac := hmac.New(sha256.New, salt)
+		mac.Write([]byte(name))
+		return hex.EncodeToString(mac.Sum(nil)[:16])
+	}
+}
+
+// RedactName is a Redactor that replaces every name with "[redacted]",
+// for records that must not tell people apart at all.
+func RedactName(string) string {
+	return "[redacted]"
+}
@@ -0,0 +1,180 @@
+package greetings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy says how a Greeter retries providers that fail transiently,
+// such as remote translation services. Zero fields take the defaults
+// noted below.
+type RetryPolicy struct {
+	// Attempts is the total number of tries, the first included.
+	// Default 3.
+	Attempts int
+
+	// InitialBackoff is the wait before the first retry. Default 100ms.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the wait between tries. Default 5s.
+	MaxBackoff time.Duration
+
+	// Multiplier grows the wait after every retry. Default 2.
+	Multiplier float64
+
+	// Jitter randomizes each wait by up to this fraction in either
+	// direction, so many clients failing together do not retry in step.
+	// Default 0.2; a negative value turns jitter off.
+	Jitter float64
+
+	// Timeout bounds each try; a try that runs out of time counts as a
+	// transient failure. Zero means tries are bounded only by the
+	// caller's context.
+	Timeout time.Duration
+}
+
+// withDefaults returns p with its zero fields filled in.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.Attempts <= 0 {
+		p.Attempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	if p.Jitter == 0 {
+		p.Jitter = 0.2
+	}
+	return p
+}
+
+// backoff returns the wait before retry number n, counting from zero.
+func (p RetryPolicy) backoff(n int) time.Duration {
+	d := float64(p.InitialBackoff)
+	for range n {
+		d *= p.Multiplier
+	}
+	d = min(d, float64(p.MaxBackoff))
+	if p.Jitter > 0 {
+		d *= 1 + p.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(d)
+}
+
+// transientError marks an error as worth retrying.
+type transientError struct {
+	err error
+}
+
+func (e transientError) Error() string   { return e.err.Error() }
+func (e transientError) Unwrap() error   { return e.err }
+func (e transientError) Transient() bool { return true }
+
+// Transient marks err as a transient failure that a RetryPolicy should
+// retry, as opposed to a permanent one such as a rejected name. Providers
+// return Transient(err) for timeouts, throttling and unavailable
+// services. Transient(nil) is nil.
+func Transient(err error) error {
+	if err == nil {
+		return nil
+	}
+	return transientError{err}
+}
+
+// IsTransient reports whether err, or an error it wraps, is transient: it
+// was marked with Transient, or it has a Transient or Timeout method
+// returning true, as net.Error timeouts do. Other errors are permanent.
+func IsTransient(err error) bool {
+	var t interface{ Transient() bool }
+	if errors.As(err, &t) && t.Transient() {
+		return true
+	}
+	var timeout interface{ Timeout() bool }
+	return errors.As(err, &timeout) && timeout.Timeout()
+}
+
+// WithRetry retries the Greeter's provider as p says. Only transient
+// errors (see IsTransient) are retried; permanent ones and the caller's
+// own cancellation are returned at once.
+func WithRetry(p RetryPolicy) Option {
+	return Use(RetryMiddleware(p))
+}
+
+// RetryMiddleware returns a Middleware retrying next as p says.
+func RetryMiddleware(p RetryPolicy) Middleware {
+	p = p.withDefaults()
+	return func(next Provider) Provider {
+		return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+			var greeting Greeting
+			err := p.Do(ctx, func(ctx context.Context) error {
+				var err error
+				greeting, err = next.Greet(ctx, req)
+				return err
+			})
+			if err != nil {
+				return Greeting{}, err
+			}
+			return greeting, nil
+		})
+	}
+}
+
+// Do calls fn until it succeeds, fails permanently or has been tried
+// p.Attempts times, waiting between tries as p says, and returns its last
+// error. Only transient errors (see IsTransient) are retried; ctx.Err() is
+// returned at once when ctx is done. It is the loop behind WithRetry, for
+// anything else a greeting depends on, such as delivering it.
+func (p RetryPolicy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+
+	p = p.withDefaults()
+	var err error
+	for attempt := range p.Attempts {
+		if attempt > 0 {
+			timer := time.NewTimer(p.backoff(attempt - 1))
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+
+		err = tryOnce(ctx, fn, p.Timeout)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !IsTransient(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("greetings: giving up after %d attempts: %w", p.Attempts, err)
+}
+
+// tryOnce calls fn within timeout, if there is one. A try cut short by
+// its own timeout fails transiently.
+func tryOnce(ctx context.Context, fn func(ctx context.Context) error, timeout time.Duration) error {
+
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := fn(tctx)
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		err = Transient(err)
+	}
+
+	return err
+}
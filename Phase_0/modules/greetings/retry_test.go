@@ -0,0 +1,113 @@
+package greetings_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+)
+
+// scriptedProvider fails with each of errs in turn, then greets, and
+// counts the calls.
+type scriptedProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *scriptedProvider) Greet(_ context.Context, req greetings.Request) (greetings.Greeting, error) {
+	p.calls++
+	if len(p.errs) > 0 {
+		err := p.errs[0]
+		p.errs = p.errs[1:]
+		return greetings.Greeting{}, err
+	}
+	return greetings.Greeting{Message: "Remote hi, " + req.Recipients[0].Name}, nil
+}
+
+func TestRetry(t *testing.T) {
+	policy := greetings.RetryPolicy{Attempts: 3, InitialBackoff: time.Millisecond, Jitter: -1}
+	for _, tt := range []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   string
+	}{
+		{"success", nil, 1, ""},
+		{"transient then success", []error{errFlaky, errFlaky}, 3, ""},
+		{"permanent", []error{errBroken, errFlaky}, 1, "remote: bad request"},
+		{"transient throughout", []error{errFlaky, errFlaky, errFlaky, errFlaky}, 3, "giving up after 3 attempts: remote: unavailable"},
+		{"transient then permanent", []error{errFlaky, errBroken}, 2, "remote: bad request"},
+	} {
+		p := &scriptedProvider{errs: tt.errs}
+		g, err := greetings.New(greetings.WithProvider(p), greetings.WithRetry(policy))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := g.Hello("Ann")
+		if p.calls != tt.wantCalls {
+			t.Errorf("%s: provider called %d times, want %d", tt.name, p.calls, tt.wantCalls)
+		}
+		switch {
+		case tt.wantErr == "" && (err != nil || got != "Remote hi, Ann"):
+			t.Errorf("%s: Hello(Ann) = %q, %v, want the provider's greeting", tt.name, got, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: Hello(Ann) error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRetryTimeout(t *testing.T) {
+	calls := 0
+	p := greetings.ProviderFunc(func(ctx context.Context, req greetings.Request) (greetings.Greeting, error) {
+		calls++
+		if calls == 1 {
+			<-ctx.Done()
+			return greetings.Greeting{}, ctx.Err()
+		}
+		return greetings.Greeting{Message: "Remote hi, " + req.Recipients[0].Name}, nil
+	})
+	policy := greetings.RetryPolicy{Attempts: 2, InitialBackoff: time.Millisecond, Timeout: 10 * time.Millisecond}
+	g, err := greetings.New(greetings.WithProvider(p), greetings.WithRetry(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, err := g.Hello("Ann"); err != nil || got != "Remote hi, Ann" || calls != 2 {
+		t.Errorf("Hello(Ann) = %q, %v after %d calls, want a retry once the first try timed out", got, err, calls)
+	}
+}
+
+func TestRetryCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := greetings.RetryPolicy{Attempts: 5, InitialBackoff: time.Hour}.Do(ctx, func(context.Context) error {
+		calls++
+		cancel()
+		return errFlaky
+	})
+	if !errors.Is(err, context.Canceled) || calls != 1 {
+		t.Errorf("Do = %v after %d calls, want %v after one", err, calls, context.Canceled)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{errBroken, false},
+		{errFlaky, true},
+		{greetings.Transient(nil), false},
+		{errors.Join(errBroken, errFlaky), true},
+		{context.DeadlineExceeded, true},
+		{context.Canceled, false},
+	} {
+		if got := greetings.IsTransient(tt.err); got != tt.want {
+			t.Errorf("IsTransient(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package greetings
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SanitizeMode controls what a Greeter does with names holding bytes that
+// are unsafe to print to a terminal or log: invalid UTF-8, NULs and other
+// control characters, bidirectional formatting characters, which can make
+// text display in a different order than it is stored, and ANSI escape
+// sequences.
+type SanitizeMode int
+
+const (
+	// SanitizeOff greets names as given, apart from validation. It is the
+	// default; invalid UTF-8 still fails with ErrInvalidUTF8.
+	SanitizeOff SanitizeMode = iota
+
+	// SanitizeReject fails names holding control characters, bidi
+	// formatting characters or escape sequences with ErrUnsafeName.
+	SanitizeReject
+
+	// SanitizeReplace cleans names with Sanitize before greeting them.
+	SanitizeReplace
+)
+
+// WithSanitize selects how the Greeter treats unsafe names; see
+// SanitizeMode.
+func WithSanitize(mode SanitizeMode) Option {
+	return func(g *Greeter) error {
+		g.sanitize = mode
+		return nil
+	}
+}
+
+// Sanitize makes name safe to print: ANSI escape sequences are removed,
+// invalid UTF-8 becomes U+FFFD, tabs and line breaks become spaces, and
+// all other control characters, NUL included, and bidi formatting
+// characters such as RIGHT-TO-LEFT OVERRIDE are dropped. So
+// "\x1b[31mAnn\x1b[0m\x00" becomes "Ann".
+func Sanitize(name string) string {
+
+	var b strings.Builder
+	b.Grow(len(name))
+	for i := 0; i < len(name); {
+		if n := escapeLen(name[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(name[i:])
+		i += size
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteRune(utf8.RuneError)
+		case r == '\t' || r == '\n' || r == '\r':
+			b.WriteByte(' ')
+		case unsafeRune(r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+// isSafe reports whether name holds no control characters, no bidi
+// formatting characters and no escape sequences.
+func isSafe(name string) bool {
+	return strings.IndexFunc(name, unsafeRune) < 0
+}
+
+// unsafeRune reports whether r is a control or bidi formatting character.
+func unsafeRune(r rune) bool {
+	return unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r)
+}
+
+// escapeLen returns the length of the ANSI escape sequence s starts with,
+// or zero if it does not start with one. It recognizes CSI sequences
+// (ESC [ ... final byte), OSC sequences (ESC ] ... BEL or ESC \) and
+// two-byte escapes; an unterminated sequence runs to the end of s.
+func escapeLen(s string) int {
+
+	if len(s) == 0 || s[0] != 0x1b {
+		return 0
+	}
+	if len(s) == 1 {
+		return 1
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == 0x07 {
+				return i + 1
+			}
+			if s[i] == 0x1b && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+
+	return len(s)
+}
+
+// prepareName sanitizes, normalizes, resolves, transliterates, validates
+// and filters name according to the Greeter's settings, returning the name
+// to greet.
+func (g *Greeter) prepareName(name string) (string, error) {
+
+	name, err := g.cleanName(name)
+	if err != nil {
+		return "", err
+	}
+
+	return g.checkName(name)
+}
+
+// cleanName sanitizes, normalizes, resolves and transliterates name: the
+// normalize stage of a greeting. Only SanitizeReject can make it fail.
+func (g *Greeter) cleanName(name string) (string, error) {
+
+	if g.mode == Lenient {
+		name = g.mend(name)
+	}
+	switch g.sanitize {
+	case SanitizeReject:
+		if utf8.ValidString(name) && !isSafe(name) {
+			return "", fmt.Errorf("%w: %q", ErrUnsafeName, name)
+		}
+	case SanitizeReplace:
+		name = Sanitize(name)
+	}
+	name = g.resolveAlias(g.normalizeName(name))
+	if g.transliterator != nil {
+		name = g.transliterator.Transliterate(name)
+	}
+
+	return name, nil
+}
+
+// checkName validates and filters an already cleaned name: the validate
+// stage of a greeting.
+func (g *Greeter) checkName(name string) (string, error) {
+
+	switch g.mode {
+	case Lenient:
+		name = g.clip(g.mend(name))
+	case Strict:
+		if blankName(name) {
+			return "", ErrEmptyName
+		}
+	}
+	if err := ValidateMax(name, g.maxNameLength); err != nil {
+		return "", err
+	}
+	if g.filter != nil {
+		return g.applyFilter(name)
+	}
+
+	return name, nil
+}
+
+// blankName reports whether name is white space only, which Strict mode
+// rejects like an empty name.
+func blankName(name string) bool {
+	return name != "" && strings.TrimSpace(name) == ""
+}
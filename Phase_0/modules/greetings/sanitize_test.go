@@ -0,0 +1,65 @@
+package greetings_test
+
+import (
+	"errors"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestSanitize(t *testing.T) {
+	for _, tt := range []struct {
+		name, in, want string
+	}{
+		{"plain", "Zoë Ann", "Zoë Ann"},
+		{"csi color", "\x1b[31mAnn\x1b[0m", "Ann"},
+		{"csi cursor", "Ann\x1b[2J\x1b[1;1H", "Ann"},
+		{"osc bel", "\x1b]0;pwned\x07Ann", "Ann"},
+		{"osc st", "\x1b]8;;https://example.com\x1b\\Ann\x1b]8;;\x1b\\", "Ann"},
+		{"two-byte escape", "\x1bcAnn", "Ann"},
+		{"unterminated csi", "Ann\x1b[31", "Ann"},
+		{"lone escape", "Ann\x1b", "Ann"},
+		{"nul", "Ann\x00", "Ann"},
+		{"c0", "A\x01n\x07n\x7f", "Ann"},
+		{"c1", "A\u0085n\u009bn", "Ann"},
+		{"whitespace", "Ann\tMarie\r\nSmith", "Ann Marie  Smith"},
+		{"invalid utf-8", "Ann\xff", "Ann\ufffd"},
+		{"rlo", "Ann\u202egnp.exe", "Anngnp.exe"},
+		{"embeddings", "\u202aAnn\u202c \u202bSmith\u202c", "Ann Smith"},
+		{"isolates", "\u2066Ann\u2069 \u2067\u2068Smith\u2069", "Ann Smith"},
+		{"marks", "Ann\u200e\u200f\u061c", "Ann"},
+		{"kept", "مريم \u200dAnn", "مريم \u200dAnn"},
+	} {
+		if got := greetings.Sanitize(tt.in); got != tt.want {
+			t.Errorf("%s: Sanitize(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeModes(t *testing.T) {
+	for _, tt := range []struct {
+		mode    greetings.SanitizeMode
+		name    string
+		want    string
+		wantErr error
+	}{
+		{greetings.SanitizeOff, "\x1b[31mAnn", "Hi, \x1b[31mAnn. Welcome!", nil},
+		{greetings.SanitizeOff, "Ann\xff", "", greetings.ErrInvalidUTF8},
+		{greetings.SanitizeReject, "Ann", "Hi, Ann. Welcome!", nil},
+		{greetings.SanitizeReject, "\x1b[31mAnn", "", greetings.ErrUnsafeName},
+		{greetings.SanitizeReject, "Ann\u0085", "", greetings.ErrUnsafeName},
+		{greetings.SanitizeReject, "Ann\u202e", "", greetings.ErrUnsafeName},
+		{greetings.SanitizeReject, "Ann\xff", "", greetings.ErrInvalidUTF8},
+		{greetings.SanitizeReplace, "\x1b[31mAnn\x1b[0m\x00", "Hi, Ann. Welcome!", nil},
+		{greetings.SanitizeReplace, "Ann\u202e", "Hi, Ann. Welcome!", nil},
+	} {
+		g, err := greetings.New(greetings.WithSanitize(tt.mode))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := g.Hello(tt.name)
+		if got != tt.want || !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+			t.Errorf("mode %d: Hello(%q) = %q, %v; want %q, %v", tt.mode, tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
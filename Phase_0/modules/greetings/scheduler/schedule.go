@@ -0,0 +1,212 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when a job runs.
+type Schedule interface {
+	// Next returns the first time after t the job should run, or the
+	// zero time if it should not run again.
+	Next(t time.Time) time.Time
+}
+
+// Parse parses a schedule spec, which is one of
+//
+//	@at 2025-12-24T18:00:00Z   once, at an RFC 3339 time
+//	@every 1h30m               repeatedly, at a fixed interval
+//	@hourly, @daily, @weekly, @monthly, @yearly
+//	30 9 * * 1-5               a cron expression
+//
+// A cron expression has five fields: minute (0-59), hour (0-23), day of
+// the month (1-31), month (1-12) and day of the week (0-6, Sunday is 0 or
+// 7). Each field is *, a value, a range like 1-5, a list like 1,15 or any
+// of those with a step, like */15 or 0-30/10. As in cron, when both day
+// fields are restricted a day matching either will do. Cron times are in
+// the location of the time passed to Next.
+func Parse(spec string) (Schedule, error) {
+
+	spec = strings.TrimSpace(spec)
+	if at, ok := strings.CutPrefix(spec, "@at "); ok {
+		t, err := time.Parse(time.RFC3339, strings.TrimSpace(at))
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: %q: %w", spec, err)
+		}
+		return At(t), nil
+	}
+	if every, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(every))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("scheduler: %q: invalid interval", spec)
+		}
+		return Every(d), nil
+	}
+	if expr, ok := macros[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("scheduler: %q: want 5 cron fields, have %d", spec, len(fields))
+	}
+	var c cron
+	for i, f := range fields {
+		set, err := parseField(f, cronFields[i])
+		if err != nil {
+			return nil, fmt.Errorf("scheduler: %q: %s: %w", spec, cronFields[i].name, err)
+		}
+		c.fields[i] = set
+	}
+	if c.fields[dow]&(1<<7) != 0 {
+		c.fields[dow] |= 1 << 0
+	}
+	c.anyDOM = fields[dom] == "*"
+	c.anyDOW = fields[dow] == "*"
+
+	return &c, nil
+}
+
+// macros are the cron expressions the @ shorthands stand for.
+var macros = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// At returns a Schedule that runs once, at t.
+func At(t time.Time) Schedule {
+	return at(t)
+}
+
+type at time.Time
+
+func (a at) Next(t time.Time) time.Time {
+	if time.Time(a).After(t) {
+		return time.Time(a)
+	}
+	return time.Time{}
+}
+
+// Every returns a Schedule that runs every d.
+func Every(d time.Duration) Schedule {
+	return every(d)
+}
+
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// The cron fields, in order.
+const (
+	minute = iota
+	hour
+	dom
+	month
+	dow
+)
+
+// fieldRange names a cron field and bounds its values.
+type fieldRange struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [...]fieldRange{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// cron is a parsed cron expression: a bit set of the allowed values of
+// each field.
+type cron struct {
+	fields         [5]uint64
+	anyDOM, anyDOW bool
+}
+
+// parseField parses one cron field into a bit set.
+func parseField(s string, f fieldRange) (uint64, error) {
+
+	var set uint64
+	for part := range strings.SplitSeq(s, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = n
+		}
+		lo, hi := f.min, f.max
+		if rng != "*" {
+			loText, hiText, isRange := strings.Cut(rng, "-")
+			var err error
+			if lo, err = strconv.Atoi(loText); err != nil {
+				return 0, fmt.Errorf("invalid value %q", loText)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(hiText); err != nil {
+					return 0, fmt.Errorf("invalid value %q", hiText)
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+		}
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, f.min, f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			set |= 1 << v
+		}
+	}
+
+	return set, nil
+}
+
+func (c *cron) has(field, v int) bool {
+	return c.fields[field]&(1<<v) != 0
+}
+
+// day reports whether t's day matches the day fields.
+func (c *cron) day(t time.Time) bool {
+	domOK, dowOK := c.has(dom, t.Day()), c.has(dow, int(t.Weekday()))
+	if !c.anyDOM && !c.anyDOW {
+		return domOK || dowOK
+	}
+	return domOK && dowOK
+}
+
+// Next implements Schedule. It gives up, returning the zero time, on
+// expressions that never match, such as February 30th.
+func (c *cron) Next(t time.Time) time.Time {
+
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !c.has(month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !c.day(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case !c.has(hour, t.Hour()):
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case !c.has(minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
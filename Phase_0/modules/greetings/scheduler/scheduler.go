@@ -0,0 +1,216 @@
+// Package scheduler sends greetings at set times: once at a fixed time,
+// at an interval or on a cron schedule (see Parse). A Scheduler keeps its
+// pending jobs in a Store, so they survive restarts, and hands every
+// greeting it generates to a greetings.Sender, such as those in package
+// deliver.
+//
+//	s := scheduler.New(greeter, deliver.NewSlack(url), store)
+//	s.Add(ctx, scheduler.Job{ID: "standup", Person: greetings.Person{Name: "team"}, Spec: "0 9 * * 1-5"})
+//	err := s.Run(ctx) // until ctx is canceled
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"example.com/greetings"
+)
+
+// Job is a greeting to send on a schedule.
+type Job struct {
+	// ID identifies the job; adding a job with the ID of another
+	// replaces it.
+	ID string `json:"id"`
+
+	// Person is who to greet.
+	Person greetings.Person `json:"person"`
+
+	// Spec is the schedule, as accepted by Parse.
+	Spec string `json:"spec"`
+
+	// Next is when the job runs next. Add computes it.
+	Next time.Time `json:"next"`
+}
+
+// Scheduler runs jobs. It is safe for concurrent use. Change its jobs
+// through Add and Remove rather than its Store, which it does not expect
+// to change under it.
+type Scheduler struct {
+	greeter *greetings.Greeter
+	sender  greetings.Sender
+	store   Store
+	clock   greetings.Clock
+
+	// OnError, if set, is called with every failed dispatch. Failures
+	// are not retried; a repeating job runs again at its next time.
+	OnError func(Job, error)
+
+	// mu serializes changes to the store, so that rescheduling a job
+	// cannot bring back one removed since it came due.
+	mu       sync.Mutex
+	wake     chan struct{}
+	inFlight sync.WaitGroup
+}
+
+// New returns a Scheduler greeting with g, sending with s and keeping its
+// jobs in store.
+func New(g *greetings.Greeter, s greetings.Sender, store Store) *Scheduler {
+	return &Scheduler{
+		greeter: g,
+		sender:  s,
+		store:   store,
+		clock:   greetings.SystemClock,
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// SetClock makes s read the time from c instead of the system's; it still
+// waits in real time. A nil c means SystemClock. SetClock must be called
+// before s is used.
+func (s *Scheduler) SetClock(c greetings.Clock) {
+	if c == nil {
+		c = greetings.SystemClock
+	}
+	s.clock = c
+}
+
+// Add schedules j, computing its first run from its Spec, and persists
+// it. It fails for a spec that does not parse or will never run.
+func (s *Scheduler) Add(ctx context.Context, j Job) (Job, error) {
+
+	if j.ID == "" {
+		return Job{}, errors.New("scheduler: empty job ID")
+	}
+	sched, err := Parse(j.Spec)
+	if err != nil {
+		return Job{}, err
+	}
+	j.Next = sched.Next(s.clock.Now())
+	if j.Next.IsZero() {
+		return Job{}, fmt.Errorf("scheduler: job %q: %q never runs", j.ID, j.Spec)
+	}
+	s.mu.Lock()
+	err = s.store.Save(ctx, j)
+	s.mu.Unlock()
+	if err != nil {
+		return Job{}, err
+	}
+	s.notify()
+
+	return j, nil
+}
+
+// Remove unschedules the job with id. A dispatch already under way still
+// completes.
+func (s *Scheduler) Remove(ctx context.Context, id string) error {
+	s.mu.Lock()
+	err := s.store.Delete(ctx, id)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.notify()
+	return nil
+}
+
+// Jobs returns the pending jobs.
+func (s *Scheduler) Jobs(ctx context.Context) ([]Job, error) {
+	return s.store.Jobs(ctx)
+}
+
+// notify wakes Run to look at the jobs again.
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches jobs as they come due until ctx is done, then waits for
+// the dispatches under way to finish and returns ctx.Err(). Dispatches
+// are not canceled with ctx, so a shutdown does not cut a delivery
+// short; bound them through the Sender. Jobs that came due while no
+// Scheduler was running, such as during a restart, run once at start.
+func (s *Scheduler) Run(ctx context.Context) error {
+
+	defer s.inFlight.Wait()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		next, err := s.dispatchDue(ctx)
+		if err != nil {
+			return err
+		}
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = min(wait, next.Sub(s.clock.Now()))
+		}
+		timer.Reset(wait)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// dispatchDue starts the jobs that are due and reschedules them, and
+// returns when the next job is due, or the zero time when none is.
+func (s *Scheduler) dispatchDue(ctx context.Context) (time.Time, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs, err := s.store.Jobs(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now := s.clock.Now()
+	var next time.Time
+	for _, j := range jobs {
+		if j.Next.After(now) {
+			if next.IsZero() || j.Next.Before(next) {
+				next = j.Next
+			}
+			continue
+		}
+
+		s.inFlight.Add(1)
+		go s.dispatch(context.WithoutCancel(ctx), j)
+
+		sched, err := Parse(j.Spec)
+		if err == nil {
+			j.Next = sched.Next(now)
+		}
+		if err != nil || j.Next.IsZero() {
+			err = s.store.Delete(ctx, j.ID)
+		} else {
+			err = s.store.Save(ctx, j)
+			if next.IsZero() || j.Next.Before(next) {
+				next = j.Next
+			}
+		}
+		if err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return next, nil
+}
+
+// dispatch greets and sends for j.
+func (s *Scheduler) dispatch(ctx context.Context, j Job) {
+
+	defer s.inFlight.Done()
+	greeting, err := s.greeter.GreetCtx(ctx, j.Person)
+	if err == nil {
+		err = s.sender.Send(ctx, greeting)
+	}
+	if err != nil && s.OnError != nil {
+		s.OnError(j, err)
+	}
+}
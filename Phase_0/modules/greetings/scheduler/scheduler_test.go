@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/greetingstest"
+)
+
+// sink is a greetings.Sender that keeps what it is sent.
+type sink struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (s *sink) Send(_ context.Context, g greetings.Greeting) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, g.Message)
+	return nil
+}
+
+// removingStore is a Store that, the first time its jobs are listed,
+// starts removing one through the Scheduler and gives the removal time
+// to happen before answering.
+type removingStore struct {
+	*MemoryStore
+	s       *Scheduler
+	id      string
+	removed chan error
+	once    sync.Once
+}
+
+func (r *removingStore) Jobs(ctx context.Context) ([]Job, error) {
+	jobs, err := r.MemoryStore.Jobs(ctx)
+	r.once.Do(func() {
+		go func() { r.removed <- r.s.Remove(ctx, r.id) }()
+		time.Sleep(20 * time.Millisecond)
+	})
+	return jobs, err
+}
+
+func TestRemoveDuringDispatch(t *testing.T) {
+	ctx := context.Background()
+	g, err := greetings.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := greetingstest.NewFakeClock(time.Date(2025, 1, 6, 9, 0, 0, 0, time.UTC))
+	out := &sink{}
+	store := &removingStore{MemoryStore: NewMemoryStore(), id: "standup", removed: make(chan error, 1)}
+	s := New(g, out, store)
+	s.SetClock(clock)
+	store.s = s
+
+	if _, err := s.Add(ctx, Job{ID: "standup", Person: greetings.Person{Name: "Ada"}, Spec: "@every 1h"}); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Hour)
+	if _, err := s.dispatchDue(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-store.removed; err != nil {
+		t.Fatal(err)
+	}
+	s.inFlight.Wait()
+
+	jobs, err := s.Jobs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 0 {
+		t.Errorf("Jobs() = %v after Remove, want none", jobs)
+	}
+	if len(out.sent) != 1 {
+		t.Errorf("sent %q, want the one greeting that was due", out.sent)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// Monday 6 January 2025, 09:30.
+	from := time.Date(2025, 1, 6, 9, 30, 0, 0, time.UTC)
+	for _, tt := range []struct {
+		spec string
+		want time.Time
+	}{
+		{"@at 2025-12-24T18:00:00Z", time.Date(2025, 12, 24, 18, 0, 0, 0, time.UTC)},
+		{"@at 2024-12-24T18:00:00Z", time.Time{}},
+		{"@every 1h30m", time.Date(2025, 1, 6, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 1, 12, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 1, 6, 9, 45, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2025, 1, 7, 9, 30, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2025, 1, 12, 9, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)},
+	} {
+		sched, err := Parse(tt.spec)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := sched.Next(from); !got.Equal(tt.want) {
+			t.Errorf("Parse(%q).Next(%v) = %v, want %v", tt.spec, from, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"@every 0s",
+		"@every soon",
+		"@at tomorrow",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
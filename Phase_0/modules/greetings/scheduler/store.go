@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// Store keeps the pending jobs of a Scheduler so they survive restarts.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Save adds j or replaces the job with its ID.
+	Save(ctx context.Context, j Job) error
+
+	// Delete removes the job with id. Deleting an unknown job is not an
+	// error.
+	Delete(ctx context.Context, id string) error
+
+	// Jobs returns every job, in ID order.
+	Jobs(ctx context.Context) ([]Job, error)
+}
+
+// MemoryStore is a Store that keeps jobs in memory, for tests and for
+// schedulers whose jobs need not survive a restart. The zero value is an
+// empty store ready to use.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs map[string]Job
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return new(MemoryStore)
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.jobs == nil {
+		s.jobs = make(map[string]Job)
+	}
+	s.jobs[j.ID] = j
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+// Jobs implements Store.
+func (s *MemoryStore) Jobs(context.Context) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedJobs(s.jobs), nil
+}
+
+func sortedJobs(jobs map[string]Job) []Job {
+	return slices.SortedFunc(maps.Values(jobs), func(a, b Job) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+}
+
+// FileStore is a Store that persists jobs to a JSON file holding an array
+// of jobs. Every change rewrites the file through a temporary file and a
+// rename, so it is never left half written.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+	jobs map[string]Job
+}
+
+// OpenFile loads the jobs file at path. A missing file is an empty store,
+// created on the first change.
+func OpenFile(path string) (*FileStore, error) {
+
+	s := &FileStore{path: path, jobs: make(map[string]Job)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []Job
+	if err := json.Unmarshal(data, &jobs); err != nil {
+		return nil, fmt.Errorf("scheduler: %s: %w", path, err)
+	}
+	for _, j := range jobs {
+		s.jobs[j.ID] = j
+	}
+
+	return s, nil
+}
+
+// Save implements Store.
+func (s *FileStore) Save(_ context.Context, j Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := maps.Clone(s.jobs)
+	jobs[j.ID] = j
+	return s.write(jobs)
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[id]; !ok {
+		return nil
+	}
+	jobs := maps.Clone(s.jobs)
+	delete(jobs, id)
+	return s.write(jobs)
+}
+
+// Jobs implements Store.
+func (s *FileStore) Jobs(context.Context) ([]Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedJobs(s.jobs), nil
+}
+
+// write replaces the file's contents with jobs and, once that succeeded,
+// s.jobs. s.mu must be held.
+func (s *FileStore) write(jobs map[string]Job) error {
+
+	data, err := json.MarshalIndent(sortedJobs(jobs), "", "\t")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0o644); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), s.path); err != nil {
+		return err
+	}
+	s.jobs = jobs
+
+	return nil
+}
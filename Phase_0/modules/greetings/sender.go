@@ -0,0 +1,22 @@
+package greetings
+
+import "context"
+
+// Sender delivers greetings somewhere people read them: a chat channel,
+// an inbox, a phone. Package deliver has implementations.
+type Sender interface {
+	Send(ctx context.Context, greeting Greeting) error
+}
+
+// GreetAndSend greets the named person and hands the greeting to s. It
+// returns the greeting even when sending fails, so callers can log what
+// was not delivered.
+func (g *Greeter) GreetAndSend(ctx context.Context, name string, s Sender) (Greeting, error) {
+
+	greeting, err := g.GreetCtx(ctx, Person{Name: name})
+	if err != nil {
+		return Greeting{}, err
+	}
+
+	return greeting, s.Send(ctx, greeting)
+}
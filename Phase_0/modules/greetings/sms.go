@@ -0,0 +1,202 @@
+package greetings
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+// SMSEncoding is the character set a text message is sent in.
+type SMSEncoding int
+
+const (
+	// GSM7 is the GSM 03.38 default alphabet: 160 characters per message,
+	// with the characters of its extension table counting twice.
+	GSM7 SMSEncoding = iota
+
+	// UCS2 is UTF-16, used when the text has characters GSM-7 lacks: 70
+	// code units per message.
+	UCS2
+)
+
+var smsEncodingNames = [...]string{GSM7: "GSM-7", UCS2: "UCS-2"}
+
+// String returns the conventional name of e, "GSM-7" or "UCS-2".
+func (e SMSEncoding) String() string {
+	if e < 0 || int(e) >= len(smsEncodingNames) {
+		return fmt.Sprintf("SMSEncoding(%d)", int(e))
+	}
+	return smsEncodingNames[e]
+}
+
+// Capacity of a single text message and of each part of a concatenated
+// one, whose header takes up room.
+const (
+	gsm7Single = 160
+	gsm7Part   = 153
+	ucs2Single = 70
+	ucs2Part   = 67
+)
+
+const (
+	// gsm7Basic is the GSM-7 default alphabet, less the escape character.
+	gsm7Basic = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?" +
+		"¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+
+	// gsm7Extension holds the characters sent as an escape and a code.
+	gsm7Extension = "\f^{}\\[~]|€"
+)
+
+// smsSpaces turns the no-break spaces some locales write, which GSM-7
+// lacks, into plain ones, so French greetings still fit 160 characters.
+var smsSpaces = strings.NewReplacer("\u00a0", " ", "\u202f", " ")
+
+// smsLength returns the length of text in e's units.
+func smsLength(text string, e SMSEncoding) int {
+	if e == UCS2 {
+		return len(utf16.Encode([]rune(text)))
+	}
+	n := 0
+	for _, r := range text {
+		n++
+		if strings.ContainsRune(gsm7Extension, r) {
+			n++
+		}
+	}
+	return n
+}
+
+// smsEncoding returns the encoding text needs.
+func smsEncoding(text string) SMSEncoding {
+	for _, r := range text {
+		if !strings.ContainsRune(gsm7Basic, r) && !strings.ContainsRune(gsm7Extension, r) {
+			return UCS2
+		}
+	}
+	return GSM7
+}
+
+// SMSSegments reports the encoding text would be sent in and how many
+// segments of a concatenated message it takes.
+func SMSSegments(text string) (SMSEncoding, int) {
+
+	e := smsEncoding(text)
+	single, part := gsm7Single, gsm7Part
+	if e == UCS2 {
+		single, part = ucs2Single, ucs2Part
+	}
+	n := smsLength(text, e)
+	if n <= single {
+		return e, 1
+	}
+
+	return e, (n + part - 1) / part
+}
+
+// smsFits reports whether text fits a single message, with room to spare
+// for reserve units.
+func smsFits(text string, reserve int) bool {
+	e := smsEncoding(text)
+	single := gsm7Single
+	if e == UCS2 {
+		single = ucs2Single
+	}
+	return smsLength(text, e)+reserve <= single
+}
+
+// SplitSMS splits text into parts that each fit a single text message,
+// breaking between words where it can. Parts of a longer text start with
+// their number, as in "(1/2) ", so they read in order even when phones
+// receive them out of order.
+func SplitSMS(text string) []string {
+
+	text = smsSpaces.Replace(text)
+	if smsFits(text, 0) {
+		return []string{text}
+	}
+	for total := 2; ; total++ {
+		parts := splitSMS(text, len(fmt.Sprintf("(%d/%d) ", total, total)))
+		if len(parts) <= total {
+			for i := range parts {
+				parts[i] = fmt.Sprintf("(%d/%d) %s", i+1, len(parts), parts[i])
+			}
+			return parts
+		}
+	}
+}
+
+// splitSMS splits text into pieces that fit a message with reserve units
+// left for a prefix.
+func splitSMS(text string, reserve int) []string {
+
+	var parts []string
+	for text != "" {
+		runes := []rune(text)
+		n := len(runes)
+		for n > 1 && !smsFits(string(runes[:n]), reserve) {
+			n--
+		}
+		if n < len(runes) {
+			if i := strings.LastIndexByte(string(runes[:n]), ' '); i > 0 {
+				n = len([]rune(string(runes[:n])[:i]))
+			}
+		}
+		parts = append(parts, strings.TrimSpace(string(runes[:n])))
+		text = strings.TrimLeft(string(runes[n:]), " ")
+	}
+
+	return parts
+}
+
+// WithSMSSplit makes FormatSMS split long greetings into numbered parts
+// (see SplitSMS) instead of shortening them.
+func WithSMSSplit() Option {
+	return func(g *Greeter) error {
+		g.smsSplit = true
+		return nil
+	}
+}
+
+// sms renders greeting for FormatSMS, and reports how many messages the
+// rendering takes.
+func (g *Greeter) sms(greeting Greeting) (string, int) {
+
+	message := smsSpaces.Replace(greeting.Message)
+	if smsFits(message, 0) {
+		return message, smsCount(message)
+	}
+	if g.smsSplit {
+		parts := SplitSMS(message)
+		n := 0
+		for _, part := range parts {
+			n += smsCount(part)
+		}
+		return strings.Join(parts, "\n"), n
+	}
+
+	// "…" is not in GSM-7, so messages that would otherwise be sent in
+	// it end in three dots rather than doubling their cost.
+	ellipsis := "..."
+	if g.ellipsisSet {
+		ellipsis = g.ellipsis
+	} else if smsEncoding(message) == UCS2 {
+		ellipsis = defaultEllipsis
+	}
+	for n := len([]rune(message)) - 1; n > 0; n-- {
+		if short := truncate(message, greeting.Name, n, ellipsis); smsFits(short, 0) {
+			return short, smsCount(short)
+		}
+	}
+
+	return "", 0
+}
+
+// smsCount returns how many messages text takes, by its encoded length;
+// none when it is empty.
+func smsCount(text string) int {
+	if text == "" {
+		return 0
+	}
+	_, n := SMSSegments(text)
+	return n
+}
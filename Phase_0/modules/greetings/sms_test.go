@@ -0,0 +1,108 @@
+package greetings_test
+
+import (
+	"strings"
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestSMSSegments(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		text     string
+		encoding greetings.SMSEncoding
+		segments int
+	}{
+		{"short", "Hi, Ada. Welcome!", greetings.GSM7, 1},
+		{"gsm-7 accents", "Hola, José. ¿Qué tal? Ça va, Søren?", greetings.GSM7, 1},
+		{"gsm-7 full", strings.Repeat("a", 160), greetings.GSM7, 1},
+		{"gsm-7 two parts", strings.Repeat("a", 161), greetings.GSM7, 2},
+		{"gsm-7 two full parts", strings.Repeat("a", 2*153), greetings.GSM7, 2},
+		{"gsm-7 three parts", strings.Repeat("a", 2*153+1), greetings.GSM7, 3},
+		{"extension full", strings.Repeat("€", 80), greetings.GSM7, 1},
+		{"extension over", strings.Repeat("€", 80) + "^", greetings.GSM7, 2},
+		{"extension counts twice", strings.Repeat("a", 159) + "{", greetings.GSM7, 2},
+		{"ucs-2", "Cześć, Łukasz!", greetings.UCS2, 1},
+		{"ucs-2 full", strings.Repeat("ł", 70), greetings.UCS2, 1},
+		{"ucs-2 two parts", strings.Repeat("ł", 71), greetings.UCS2, 2},
+		{"ucs-2 two full parts", strings.Repeat("ł", 2*67), greetings.UCS2, 2},
+		{"ucs-2 three parts", strings.Repeat("ł", 2*67+1), greetings.UCS2, 3},
+		{"surrogate pairs", strings.Repeat("👋", 35), greetings.UCS2, 1},
+		{"surrogate pairs over", strings.Repeat("👋", 36), greetings.UCS2, 2},
+		{"one character makes ucs-2", strings.Repeat("a", 100) + "ł", greetings.UCS2, 2},
+		{"newlines", "Hi\nAda\n", greetings.GSM7, 1},
+	} {
+		e, n := greetings.SMSSegments(tt.text)
+		if e != tt.encoding || n != tt.segments {
+			t.Errorf("%s: SMSSegments = %v, %d; want %v, %d", tt.name, e, n, tt.encoding, tt.segments)
+		}
+	}
+}
+
+func TestSMSEncodingString(t *testing.T) {
+	for e, want := range map[greetings.SMSEncoding]string{
+		greetings.GSM7:            "GSM-7",
+		greetings.UCS2:            "UCS-2",
+		greetings.SMSEncoding(-1): "SMSEncoding(-1)",
+	} {
+		if got := e.String(); got != want {
+			t.Errorf("String = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestSplitSMS(t *testing.T) {
+	if parts := greetings.SplitSMS("Hi, Ada."); len(parts) != 1 || parts[0] != "Hi, Ada." {
+		t.Errorf("SplitSMS(short) = %q", parts)
+	}
+
+	text := strings.Repeat("word ", 100)
+	parts := greetings.SplitSMS(text)
+	if len(parts) != 4 {
+		t.Errorf("SplitSMS made %d parts, want 4", len(parts))
+	}
+	var words int
+	for i, part := range parts {
+		if _, n := greetings.SMSSegments(part); n != 1 {
+			t.Errorf("part %d takes %d messages: %q", i+1, n, part)
+		}
+		prefix := "(" + string(rune('1'+i)) + "/4) "
+		if !strings.HasPrefix(part, prefix) {
+			t.Errorf("part %d = %q, want prefix %q", i+1, part, prefix)
+		}
+		words += len(strings.Fields(strings.TrimPrefix(part, prefix)))
+	}
+	if words != 100 {
+		t.Errorf("parts hold %d words, want 100", words)
+	}
+}
+
+func TestFormatSMSSegments(t *testing.T) {
+	long := strings.Repeat("Gopher ", 30)
+	for _, tt := range []struct {
+		name     string
+		opts     []greetings.Option
+		person   string
+		segments int
+		lines    int
+	}{
+		{"fits", nil, "Ada", 1, 1},
+		{"newline fits", []greetings.Option{greetings.WithTemplate("Hi, %v.\nWelcome")}, "Ada", 1, 2},
+		{"shortened", nil, long, 1, 1},
+		{"split", []greetings.Option{greetings.WithSMSSplit()}, long, 2, 2},
+	} {
+		opts := append([]greetings.Option{greetings.WithFormat(greetings.FormatSMS), greetings.WithMaxNameLength(500)}, tt.opts...)
+		g, err := greetings.New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		greeting, err := g.Greet(strings.TrimSpace(tt.person))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if greeting.Segments != tt.segments || strings.Count(greeting.Formatted, "\n")+1 != tt.lines {
+			t.Errorf("%s: %d segments in %q, want %d in %d lines", tt.name, greeting.Segments, greeting.Formatted, tt.segments, tt.lines)
+		}
+	}
+}
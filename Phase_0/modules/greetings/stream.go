@@ -0,0 +1,49 @@
+package greetings
+
+import "context"
+
+// Result is the outcome of greeting one name from a stream: either a
+// Greeting or the error that prevented it.
+type Result struct {
+	Name     string
+	Greeting Greeting
+	Err      error
+}
+
+// HelloStream greets names as they arrive and sends one Result per name,
+// in the order received, on the returned channel. The channel is closed
+// once names is closed or ctx is done, whichever comes first, so callers
+// can range over it. Names are never buffered beyond the one in flight.
+func (g *Greeter) HelloStream(ctx context.Context, names <-chan string) <-chan Result {
+
+	results := make(chan Result)
+	go func() {
+		defer close(results)
+		for {
+			var name string
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case name, ok = <-names:
+				if !ok {
+					return
+				}
+			}
+
+			greeting, err := g.GreetCtx(ctx, Person{Name: name})
+			select {
+			case <-ctx.Done():
+				return
+			case results <- Result{Name: name, Greeting: greeting, Err: err}:
+			}
+		}
+	}()
+
+	return results
+}
+
+// HelloStream greets a stream of names with the default Greeter.
+func HelloStream(ctx context.Context, names <-chan string) <-chan Result {
+	return std.HelloStream(ctx, names)
+}
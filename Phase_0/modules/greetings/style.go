@@ -0,0 +1,138 @@
+package greetings
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// StyleRegistry holds named text/template greeting styles. It is safe for
+// concurrent use; the zero value is an empty registry.
+type StyleRegistry struct {
+	mu     sync.RWMutex
+	styles map[string]*Template
+}
+
+// Register parses text with ParseTemplate and stores it under name. It
+// fails if the template is invalid or the name is already taken.
+func (r *StyleRegistry) Register(name, text string) error {
+
+	if name == "" {
+		return errors.New("greetings: empty style name")
+	}
+	t, err := ParseTemplate(name, text)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.styles[name]; dup {
+		return fmt.Errorf("greetings: style %q already registered", name)
+	}
+	if r.styles == nil {
+		r.styles = make(map[string]*Template)
+	}
+	r.styles[name] = t
+
+	return nil
+}
+
+// Lookup returns the template registered under name, or an error wrapping
+// ErrUnknownStyle.
+func (r *StyleRegistry) Lookup(name string) (*Template, error) {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.styles[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStyle, name)
+	}
+
+	return t, nil
+}
+
+// Styles returns the registered style names in sorted order.
+func (r *StyleRegistry) Styles() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Sorted(maps.Keys(r.styles))
+}
+
+// styles is the registry behind Register, Styles and WithStyle.
+var styles = func() *StyleRegistry {
+	r := new(StyleRegistry)
+	for name, text := range map[string]string{
+		"pirate": "Ahoy, {{.Name}}! Welcome aboard, matey!",
+		"cowboy": "Howdy, {{.Name}}! Pull up a chair.",
+		"welcome-back": "Welcome back, {{.Name}}! " +
+			"It's been a while since we saw {{.Pronouns.Object}}, " +
+			"and {{.Pronouns.Subject}} {{.Pronouns.Has}} been missed.",
+	} {
+		if err := r.Register(name, text); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}()
+
+// Register adds a named style that any Greeter can select with WithStyle.
+// See StyleRegistry.Register.
+func Register(name, text string) error {
+	return styles.Register(name, text)
+}
+
+// Styles returns the names of all registered styles in sorted order.
+func Styles() []string {
+	return styles.Styles()
+}
+
+// Style is the name of a registered style, for flags, config files and
+// map keys. The empty Style means none. Unmarshaling fails with
+// ErrUnknownStyle for names not in the registry at the time, so register
+// custom styles before decoding configuration that uses them.
+type Style string
+
+// String returns s as a string.
+func (s Style) String() string {
+	return string(s)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Style) MarshalText() ([]byte, error) {
+	return []byte(s), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Style) UnmarshalText(text []byte) error {
+	if len(text) > 0 {
+		if _, err := styles.Lookup(string(text)); err != nil {
+			return err
+		}
+	}
+	*s = Style(text)
+	return nil
+}
+
+// WithStyle makes the Greeter render with the named registered style, as if
+// its template had been passed to WithTextTemplate. New fails with
+// ErrUnknownStyle if no such style is registered.
+func WithStyle(name string) Option {
+	return func(g *Greeter) error {
+		t, err := styles.Lookup(name)
+		if err != nil {
+			return err
+		}
+		g.style = name
+		g.textTemplate = t
+		return nil
+	}
+}
+
+// Style reports the registered style the Greeter renders with, or "" when
+// it uses the locale catalog.
+func (g *Greeter) Style() string {
+	return g.style
+}
@@ -0,0 +1,82 @@
+package greetings
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+// LocaleError reports a locale no catalog entry serves. It wraps
+// ErrUnknownLocale, and suggests the closest locale the catalog does
+// have when the requested one looks like a typo of it.
+type LocaleError struct {
+	Locale string
+
+	// Suggestion is the likely intended locale, such as "en-US" for
+	// "enn-US", or "" when nothing is close.
+	Suggestion string
+}
+
+func (e *LocaleError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("%v: %q (did you mean %q?)", ErrUnknownLocale, e.Locale, e.Suggestion)
+	}
+	return fmt.Sprintf("%v: %q", ErrUnknownLocale, e.Locale)
+}
+
+func (e *LocaleError) Unwrap() error {
+	return ErrUnknownLocale
+}
+
+// suggestLocale returns the locale of c whose language is closest to
+// locale's by edit distance, or "" when none is close enough to be a
+// plausible typo. A region or script in locale is kept when the catalog
+// entry has none, so "enn-US" suggests "en-US" rather than "en".
+func (c Catalog) suggestLocale(locale string) string {
+
+	lang, rest, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	lang = strings.ToLower(lang)
+
+	// Allow one typo for every two letters after the first, and no more
+	// than two: none in "ex", one in "enn" or "engl", two from five
+	// letters on.
+	best, bestDist := "", min(2, (len(lang)-1)/2)+1
+	for _, candidate := range c.Locales() {
+		candidateLang, _, _ := strings.Cut(candidate, "-")
+		if d := editDistance(lang, strings.ToLower(candidateLang)); d < bestDist {
+			best, bestDist = candidate, d
+		}
+	}
+	if best != "" && rest != "" && !strings.Contains(best, "-") {
+		if tag, err := language.Parse(best + "-" + rest); err == nil && strings.HasPrefix(tag.String(), best+"-") {
+			best = tag.String()
+		}
+	}
+
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b, counted
+// in bytes, which suits locale tags.
+func editDistance(a, b string) int {
+
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,283 @@
+package greetings
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"example.com/greetings/names"
+)
+
+// TemplateData is the value a text/template greeting is executed against.
+// Templates refer to its fields as {{.Name}}, {{.Title}}, {{.Pronouns}},
+// {{.Time}}, {{.Locale}}, {{.Emoji}}, {{.Vars.key}} and so on, and can
+// make sections conditional on them with {{if}}. They can also call
+// monogram for a name's initials (see names.Monogram), as in
+// {{monogram .Name}}, plural to pick the singular or plural of a word by
+// a count, as in {{plural .VisitCount "visit" "visits"}}, and Ordinal for
+// ordinal numbers.
+type TemplateData struct {
+	// Name is the name to greet, with the title already placed for the
+	// locale ("Dr. Ada").
+	Name string
+
+	// Title is the honorific on its own, or empty.
+	Title string
+
+	// Pronouns refer to the recipient, as in "we missed {{.Pronouns.Object}}".
+	// They are neutral (they/them) unless the Person says otherwise.
+	Pronouns Pronouns
+
+	Time   time.Time
+	Locale string
+
+	// Emoji is the decoration chosen by the Greeter's emoji settings, or
+	// empty when emoji are off.
+	Emoji string
+
+	// Vars are extra variables set with WithVars and GreetWith, such as
+	// {{.Vars.event}} in "Hi {{.Name}}, welcome to {{.Vars.event}}!". A
+	// greeting fails when its template uses a variable it was not given.
+	Vars map[string]any
+
+	// Returning reports that the recipient was greeted before, LastSeen
+	// says when and DaysSince how many whole days ago, for templates like
+	//
+	//	{{if .Returning}}Welcome back{{else}}Welcome{{end}}, {{.Name}}!
+	//	{{- if .DaysSince}} It's been {{.DaysSince}} days.{{end}}
+	//
+	// They come from history middleware (see Request.LastSeen); without
+	// it, or for group greetings, Returning is false, LastSeen is zero and
+	// DaysSince is 0. DaysSince is also 0 within a day of the last visit.
+	Returning bool
+	LastSeen  time.Time
+	DaysSince int
+
+	// VisitCount is how many times the recipient has been greeted, this
+	// time included, for templates like
+	//
+	//	Welcome back for the {{.Ordinal .VisitCount}} time, {{.Name}}!
+	//
+	// It comes from history middleware too (see Request.VisitCount), and
+	// is 0 without it.
+	VisitCount int
+}
+
+// templateFields is the set of field and method names TemplateData
+// exposes.
+var templateFields = func() map[string]bool {
+	fields := make(map[string]bool)
+	t := reflect.TypeFor[TemplateData]()
+	for i := range t.NumField() {
+		fields[t.Field(i).Name] = true
+	}
+	for i := range t.NumMethod() {
+		fields[t.Method(i).Name] = true
+	}
+	return fields
+}()
+
+// Ordinal returns n as an ordinal number the way the greeting's locale
+// writes it: "3rd" in English, "3e" in French, "3.º" in Spanish, "3." in
+// German. Languages it does not know get "3.".
+func (d TemplateData) Ordinal(n int) string {
+	language, _, _ := strings.Cut(d.Locale, "-")
+	s := strconv.Itoa(n)
+	switch language {
+	case "en":
+		switch {
+		case n%100 >= 11 && n%100 <= 13:
+			return s + "th"
+		case n%10 == 1:
+			return s + "st"
+		case n%10 == 2:
+			return s + "nd"
+		case n%10 == 3:
+			return s + "rd"
+		default:
+			return s + "th"
+		}
+	case "fr":
+		if n == 1 {
+			return s + "er"
+		}
+		return s + "e"
+	case "es", "it", "pt", "gl":
+		return s + ".º"
+	case "ja", "zh", "ko":
+		return "第" + s
+	default:
+		return s + "."
+	}
+}
+
+// pluralize returns singular when n is 1 and plural otherwise, for
+// {{plural .VisitCount "visit" "visits"}}. Languages with more plural
+// forms than two are better served by a catalog's Group message, which
+// follows the CLDR rules (see MessageFormat).
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// Template is a validated text/template greeting.
+type Template struct {
+	tmpl *template.Template
+	vars []string // the variables it references, sorted
+}
+
+// templateFuncs are the functions greeting templates can call.
+var templateFuncs = template.FuncMap{
+	"monogram": names.Monogram,
+	"plural":   pluralize,
+}
+
+// ParseTemplate parses text as a text/template greeting and checks that it
+// only references fields of TemplateData, so mistakes surface when the
+// template is registered rather than when the first greeting is rendered.
+func ParseTemplate(name, text string) (*Template, error) {
+
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, &Error{Code: TemplateError, Err: fmt.Errorf("greetings: %w", err)}
+	}
+	refs := &varRefs{sample: make(map[string]any)}
+	if err := checkFields(tmpl.Tree, tmpl.Tree.Root, refs); err != nil {
+		return nil, &Error{Code: TemplateError, Err: err}
+	}
+
+	//Dry run against sample data to catch errors the tree walk cannot see.
+	//Variables are only known when greeting, so templates that look
+	//inside them cannot be dry run.
+	sample := TemplateData{Name: "Gladys", Pronouns: PronounsThey, Time: time.Now(), Locale: defaultLocale, Vars: refs.sample}
+	if !refs.deep {
+		if err := tmpl.Execute(io.Discard, sample); err != nil {
+			return nil, &Error{Code: TemplateError, Err: fmt.Errorf("greetings: %w", err)}
+		}
+	}
+
+	return &Template{tmpl: tmpl, vars: slices.Sorted(maps.Keys(refs.sample))}, nil
+}
+
+// Execute renders the template for data.
+func (t *Template) Execute(data TemplateData) (string, error) {
+	b := getBuffer()
+	defer putBuffer(b)
+	if err := t.tmpl.Execute(b, data); err != nil {
+		return "", &Error{Code: TemplateError, Err: fmt.Errorf("greetings: %w", err)}
+	}
+	return b.String(), nil
+}
+
+// withDefaults returns vars with an empty string for every variable t
+// references that vars lacks, for Lenient Greeters. vars itself is not
+// modified.
+func (t *Template) withDefaults(vars map[string]any) map[string]any {
+
+	var filled map[string]any
+	for _, name := range t.vars {
+		if _, ok := vars[name]; ok {
+			continue
+		}
+		if filled == nil {
+			filled = make(map[string]any, len(vars)+len(t.vars))
+			maps.Copy(filled, vars)
+		}
+		filled[name] = ""
+	}
+	if filled == nil {
+		return vars
+	}
+
+	return filled
+}
+
+// varRefs records the variables a template references.
+type varRefs struct {
+	sample map[string]any // every variable used, with a sample value
+	deep   bool           // a reference looks inside a variable, as in {{.Vars.meeting.Hour}}
+}
+
+// checkFields reports the first field reference in node that TemplateData
+// does not have, and notes the variables it uses in refs. Bodies of range
+// and with are skipped because they move dot to a different value.
+func checkFields(tree *parse.Tree, node parse.Node, refs *varRefs) error {
+
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkFields(tree, child, refs); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkFields(tree, n.Pipe, refs)
+	case *parse.IfNode:
+		if err := checkFields(tree, n.Pipe, refs); err != nil {
+			return err
+		}
+		if err := checkFields(tree, n.List, refs); err != nil {
+			return err
+		}
+		return checkFields(tree, n.ElseList, refs)
+	case *parse.RangeNode:
+		return checkFields(tree, n.Pipe, refs)
+	case *parse.WithNode:
+		return checkFields(tree, n.Pipe, refs)
+	case *parse.TemplateNode:
+		return checkFields(tree, n.Pipe, refs)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			for _, arg := range cmd.Args {
+				if err := checkFields(tree, arg, refs); err != nil {
+					return err
+				}
+			}
+		}
+	case *parse.FieldNode:
+		if !templateFields[n.Ident[0]] {
+			location, _ := tree.ErrorContext(n)
+			return fmt.Errorf("greetings: template: %s: unknown field .%s (available: .%s)",
+				location, n.Ident[0], strings.Join(slices.Sorted(maps.Keys(templateFields)), ", ."))
+		}
+		if n.Ident[0] == "Vars" && len(n.Ident) > 1 {
+			refs.sample[n.Ident[1]] = "sample"
+			refs.deep = refs.deep || len(n.Ident) > 2
+		}
+	}
+
+	return nil
+}
+
+// WithTextTemplate makes the Greeter render messages with a text/template
+// instead of a fmt format, for example
+//
+//	{{.Name}}, good to have you back ({{.Locale}})!
+//
+// The template produces the whole message, so punctuation is not appended.
+// New fails if the template does not parse or references unknown fields.
+func WithTextTemplate(text string) Option {
+	return func(g *Greeter) error {
+		t, err := ParseTemplate("greeting", text)
+		if err != nil {
+			return err
+		}
+		g.textTemplate = t
+		return nil
+	}
+}
@@ -0,0 +1,22 @@
+package greetings_test
+
+import (
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestMonogramTemplateFunc(t *testing.T) {
+	g, err := greetings.New(greetings.WithTextTemplate(`[{{monogram .Name}}] Hi, {{.Name}}!`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"Ada Lovelace":         "[AL] Hi, Ada Lovelace!",
+		"Ludwig van Beethoven": "[LB] Hi, Ludwig van Beethoven!",
+	} {
+		if got, err := g.Hello(name); got != want || err != nil {
+			t.Errorf("Hello(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+// Package tenants serves greetings for many customer brands from one
+// process. A Registry builds a Greeter per tenant from the Tenant its
+// Loader returns, with the tenant's own catalog, themes and defaults, the
+// first time the tenant is asked for, and forgets tenants left idle so
+// memory follows the tenants in use rather than every tenant ever seen.
+package tenants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"example.com/greetings"
+)
+
+// ErrUnknownTenant may be returned by a Loader for a tenant ID it does not
+// recognize; Registry passes it through.
+var ErrUnknownTenant = errors.New("tenants: unknown tenant")
+
+// Tenant is one brand's greeting setup.
+type Tenant struct {
+	// Catalog replaces the built-in catalog when not nil.
+	Catalog greetings.Catalog
+
+	// Themes holds the tenant's themes, and Theme names the one to speak.
+	// A nil Themes means the package-level registry of greetings, and an
+	// empty Theme means none.
+	Themes *greetings.ThemeRegistry
+	Theme  string
+
+	// Options are the tenant's other defaults, such as its locale and
+	// formality. They apply after Catalog and Theme.
+	Options []greetings.Option
+}
+
+// options returns the greetings options that build t's Greeter.
+func (t Tenant) options() []greetings.Option {
+
+	var opts []greetings.Option
+	if t.Catalog != nil {
+		opts = append(opts, greetings.WithCatalog(t.Catalog))
+	}
+	if t.Theme != "" {
+		if t.Themes != nil {
+			opts = append(opts, t.Themes.WithTheme(t.Theme))
+		} else {
+			opts = append(opts, greetings.WithTheme(t.Theme))
+		}
+	}
+
+	return append(opts, t.Options...)
+}
+
+// Loader returns the setup of tenantID, typically from a database or a
+// directory per tenant.
+type Loader func(ctx context.Context, tenantID string) (Tenant, error)
+
+// Registry keeps a Greeter per tenant. It is safe for concurrent use.
+type Registry struct {
+	load  Loader
+	idle  time.Duration
+	clock greetings.Clock
+
+	mu        sync.Mutex
+	tenants   map[string]*entry
+	lastSweep time.Time
+}
+
+// entry is a tenant's Greeter, or the promise of one while it loads.
+type entry struct {
+	ready    chan struct{} // closed once greeter or err is set
+	greeter  *greetings.Greeter
+	err      error
+	lastUsed time.Time // guarded by Registry.mu
+}
+
+// NewRegistry returns a Registry loading tenants with load and evicting
+// those not used for idle. An idle of zero or less keeps tenants until
+// Evict.
+func NewRegistry(load Loader, idle time.Duration) *Registry {
+	return &Registry{
+		load:    load,
+		idle:    idle,
+		clock:   greetings.SystemClock,
+		tenants: make(map[string]*entry),
+	}
+}
+
+// SetClock makes r measure idleness by c's time instead of the system's,
+// so tests can step past the idle timeout. A nil c means SystemClock.
+// SetClock must be called before r is used.
+func (r *Registry) SetClock(c greetings.Clock) {
+	if c == nil {
+		c = greetings.SystemClock
+	}
+	r.clock = c
+}
+
+// Greeter returns the Greeter of tenantID, loading it on first use.
+// Concurrent calls for a tenant that is loading wait for the one load.
+// Failed loads are not remembered, so the next call tries again.
+func (r *Registry) Greeter(ctx context.Context, tenantID string) (*greetings.Greeter, error) {
+
+	now := r.clock.Now()
+	r.mu.Lock()
+	r.sweep(now)
+	e, ok := r.tenants[tenantID]
+	if ok {
+		e.lastUsed = now
+		r.mu.Unlock()
+		select {
+		case <-e.ready:
+			return e.greeter, e.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	e = &entry{ready: make(chan struct{}), lastUsed: now}
+	r.tenants[tenantID] = e
+	r.mu.Unlock()
+
+	e.greeter, e.err = r.build(ctx, tenantID)
+	if e.err != nil {
+		r.mu.Lock()
+		if r.tenants[tenantID] == e {
+			delete(r.tenants, tenantID)
+		}
+		r.mu.Unlock()
+	}
+	close(e.ready)
+
+	return e.greeter, e.err
+}
+
+// build loads tenantID and builds its Greeter.
+func (r *Registry) build(ctx context.Context, tenantID string) (*greetings.Greeter, error) {
+
+	t, err := r.load(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	g, err := greetings.New(t.options()...)
+	if err != nil {
+		return nil, fmt.Errorf("tenants: %q: %w", tenantID, err)
+	}
+
+	return g, nil
+}
+
+// GreetCtx greets p on behalf of tenantID.
+func (r *Registry) GreetCtx(ctx context.Context, tenantID string, p greetings.Person) (greetings.Greeting, error) {
+
+	g, err := r.Greeter(ctx, tenantID)
+	if err != nil {
+		return greetings.Greeting{}, err
+	}
+
+	return g.GreetCtx(ctx, p)
+}
+
+// sweep evicts idle tenants, at most once per quarter of the idle
+// timeout. r.mu must be held.
+func (r *Registry) sweep(now time.Time) {
+	if r.idle <= 0 || now.Sub(r.lastSweep) < r.idle/4 {
+		return
+	}
+	r.lastSweep = now
+	for id, e := range r.tenants {
+		if now.Sub(e.lastUsed) >= r.idle {
+			delete(r.tenants, id)
+		}
+	}
+}
+
+// Evict forgets tenantID, so its next use loads it afresh: call it when
+// a tenant's setup changes. Callers still holding its Greeter can keep
+// using it.
+func (r *Registry) Evict(tenantID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tenants, tenantID)
+}
+
+// Len reports how many tenants are loaded or loading.
+func (r *Registry) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.tenants)
+}
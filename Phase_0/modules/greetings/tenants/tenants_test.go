@@ -0,0 +1,244 @@
+package tenants_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"example.com/greetings"
+	"example.com/greetings/greetingstest"
+	"example.com/greetings/tenants"
+)
+
+// countingLoader loads the tenants in setups and counts the loads of
+// each. A non-nil gate holds every load until it is closed.
+type countingLoader struct {
+	mu      sync.Mutex
+	loads   map[string]int
+	setups  map[string]tenants.Tenant
+	gate    chan struct{}
+	started chan struct{}
+}
+
+func newLoader(setups map[string]tenants.Tenant) *countingLoader {
+	return &countingLoader{loads: make(map[string]int), setups: setups}
+}
+
+func (l *countingLoader) load(ctx context.Context, id string) (tenants.Tenant, error) {
+
+	l.mu.Lock()
+	l.loads[id]++
+	t, ok := l.setups[id]
+	l.mu.Unlock()
+	if l.gate != nil {
+		l.started <- struct{}{}
+		<-l.gate
+	}
+	if !ok {
+		return tenants.Tenant{}, tenants.ErrUnknownTenant
+	}
+
+	return t, nil
+}
+
+func (l *countingLoader) count(id string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.loads[id]
+}
+
+func (l *countingLoader) set(id string, t tenants.Tenant) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.setups[id] = t
+}
+
+func TestRegistryGreetsPerTenant(t *testing.T) {
+
+	l := newLoader(map[string]tenants.Tenant{
+		"acme":   {Catalog: greetings.Catalog{"en": {Template: "Ahoy, %v"}}},
+		"globex": {Options: []greetings.Option{greetings.WithLocale("es"), greetings.WithFormality(greetings.Formal)}},
+		"plain":  {},
+	})
+	r := tenants.NewRegistry(l.load, 0)
+	for _, tt := range []struct {
+		tenant, want string
+	}{
+		{"acme", "Ahoy, Ada"},
+		{"globex", "Reciba una cordial bienvenida, Ada."},
+		{"plain", "Hi, Ada. Welcome!"},
+		{"acme", "Ahoy, Ada"},
+	} {
+		got, err := r.GreetCtx(context.Background(), tt.tenant, greetings.Person{Name: "Ada"})
+		if err != nil {
+			t.Fatalf("GreetCtx(%q): %v", tt.tenant, err)
+		}
+		if got.Message != tt.want {
+			t.Errorf("GreetCtx(%q) = %q, want %q", tt.tenant, got.Message, tt.want)
+		}
+	}
+	if n := l.count("acme"); n != 1 {
+		t.Errorf("acme loaded %d times, want 1", n)
+	}
+	if n := r.Len(); n != 3 {
+		t.Errorf("Len() = %d, want 3", n)
+	}
+}
+
+func TestRegistryRejectsBadSetup(t *testing.T) {
+	l := newLoader(map[string]tenants.Tenant{
+		"broken": {Options: []greetings.Option{greetings.WithLocale("")}},
+	})
+	r := tenants.NewRegistry(l.load, 0)
+	if _, err := r.Greeter(context.Background(), "broken"); greetings.CodeOf(err) != greetings.InvalidConfig {
+		t.Fatalf("Greeter(broken) = %v, want an InvalidConfig error", err)
+	}
+	if n := r.Len(); n != 0 {
+		t.Errorf("Len() = %d after a failed load, want 0", n)
+	}
+}
+
+func TestRegistryRetriesFailedLoads(t *testing.T) {
+
+	l := newLoader(map[string]tenants.Tenant{})
+	r := tenants.NewRegistry(l.load, 0)
+	ctx := context.Background()
+	if _, err := r.Greeter(ctx, "late"); !errors.Is(err, tenants.ErrUnknownTenant) {
+		t.Fatalf("Greeter(late) = %v, want ErrUnknownTenant", err)
+	}
+	if n := r.Len(); n != 0 {
+		t.Errorf("Len() = %d after a failed load, want 0", n)
+	}
+	l.set("late", tenants.Tenant{})
+	if _, err := r.Greeter(ctx, "late"); err != nil {
+		t.Fatalf("Greeter(late) after setup: %v", err)
+	}
+	if n := l.count("late"); n != 2 {
+		t.Errorf("late loaded %d times, want 2", n)
+	}
+}
+
+func TestRegistrySharesOneLoad(t *testing.T) {
+
+	l := newLoader(map[string]tenants.Tenant{"acme": {}})
+	l.gate = make(chan struct{})
+	l.started = make(chan struct{}, 1)
+	r := tenants.NewRegistry(l.load, 0)
+
+	const callers = 8
+	greeters := make(chan *greetings.Greeter, callers)
+	var wg sync.WaitGroup
+	call := func() {
+		defer wg.Done()
+		g, err := r.Greeter(context.Background(), "acme")
+		if err != nil {
+			t.Errorf("Greeter(acme): %v", err)
+		}
+		greeters <- g
+	}
+	wg.Add(1)
+	go call()
+	<-l.started
+	for i := 1; i < callers; i++ {
+		wg.Add(1)
+		go call()
+	}
+	close(l.gate)
+	wg.Wait()
+	close(greeters)
+
+	first := <-greeters
+	for g := range greeters {
+		if g != first {
+			t.Fatal("callers got different Greeters for one tenant")
+		}
+	}
+	if n := l.count("acme"); n != 1 {
+		t.Errorf("acme loaded %d times, want 1", n)
+	}
+}
+
+func TestRegistryWaitHonorsContext(t *testing.T) {
+
+	l := newLoader(map[string]tenants.Tenant{"acme": {}})
+	l.gate = make(chan struct{})
+	l.started = make(chan struct{}, 1)
+	r := tenants.NewRegistry(l.load, 0)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Greeter(context.Background(), "acme")
+		done <- err
+	}()
+	<-l.started
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.Greeter(ctx, "acme"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Greeter with a canceled ctx = %v, want context.Canceled", err)
+	}
+	close(l.gate)
+	if err := <-done; err != nil {
+		t.Errorf("loading Greeter(acme): %v", err)
+	}
+}
+
+func TestRegistryEvictsIdleTenants(t *testing.T) {
+
+	l := newLoader(map[string]tenants.Tenant{"a": {}, "b": {}})
+	r := tenants.NewRegistry(l.load, time.Minute)
+	clock := greetingstest.NewFakeClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	r.SetClock(clock)
+	ctx := context.Background()
+	use := func(id string) {
+		t.Helper()
+		if _, err := r.Greeter(ctx, id); err != nil {
+			t.Fatalf("Greeter(%q): %v", id, err)
+		}
+	}
+
+	use("a")
+	use("b")
+	clock.Advance(30 * time.Second)
+	use("a")
+	clock.Advance(40 * time.Second)
+	use("a")
+	if n := r.Len(); n != 1 {
+		t.Errorf("Len() = %d after b idled out, want 1", n)
+	}
+	use("b")
+	if n := l.count("a"); n != 1 {
+		t.Errorf("a loaded %d times, want 1", n)
+	}
+	if n := l.count("b"); n != 2 {
+		t.Errorf("b loaded %d times, want 2", n)
+	}
+}
+
+func TestRegistryEvict(t *testing.T) {
+
+	l := newLoader(map[string]tenants.Tenant{"acme": {}})
+	r := tenants.NewRegistry(l.load, 0)
+	ctx := context.Background()
+	before, err := r.Greeter(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.set("acme", tenants.Tenant{Options: []greetings.Option{greetings.WithLocale("fr")}})
+	r.Evict("acme")
+	if n := r.Len(); n != 0 {
+		t.Errorf("Len() = %d after Evict, want 0", n)
+	}
+	after, err := r.Greeter(ctx, "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after == before {
+		t.Error("Greeter after Evict is the evicted Greeter")
+	}
+	if got, _ := after.Hello("Ada"); got != "Bonjour, Ada. Bienvenue\u00a0!" {
+		t.Errorf("Hello after Evict = %q, want the reloaded fr greeting", got)
+	}
+}
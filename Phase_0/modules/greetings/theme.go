@@ -0,0 +1,275 @@
+package greetings
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Theme is a named pack of wording for greetings: templates in every
+// register, punctuation and emoji, all in one language. A Greeter with a
+// theme speaks it instead of its locale's catalog entry, keeping only the
+// locale's title format and the way it joins names where the theme leaves
+// those empty.
+type Theme struct {
+	Name string
+
+	// Message is the theme's wording, as in a catalog entry. Casual and
+	// Formal variants that leave Punctuation empty take Message's.
+	Message Message
+}
+
+// message returns the locale's catalog entry msg reworded by t.
+func (t Theme) message(msg Message) Message {
+
+	m := t.Message
+	m.TitleFormat = cmp.Or(m.TitleFormat, msg.TitleFormat)
+	m.Separator = cmp.Or(m.Separator, msg.Separator)
+	m.Conjunction = cmp.Or(m.Conjunction, msg.Conjunction)
+	for _, v := range []*Variant{&m.Casual, &m.Formal} {
+		if v.Template != "" && v.Punctuation == "" {
+			v.Punctuation = m.Punctuation
+		}
+	}
+
+	return m
+}
+
+// validate checks that every template of t is a well-formed format.
+func (t Theme) validate() error {
+
+	if t.Name == "" {
+		return errors.New("greetings: empty theme name")
+	}
+	for _, f := range []Formality{Neutral, Casual, Formal} {
+		if err := checkFormat(t.Message.variant(f).Template); err != nil {
+			return fmt.Errorf("greetings: theme %q (%v): %w", t.Name, f, err)
+		}
+	}
+	if b := t.Message.Birthday; b != "" {
+		if err := checkFormat(b); err != nil {
+			return fmt.Errorf("greetings: theme %q (birthday): %w", t.Name, err)
+		}
+	}
+	if w := t.Message.WelcomeBack; w != "" {
+		if err := checkFormat(w); err != nil {
+			return fmt.Errorf("greetings: theme %q (welcome back): %w", t.Name, err)
+		}
+	}
+	if g := t.Message.Group; g != "" {
+		if _, err := ParseMessageFormat(defaultLocale, g); err != nil {
+			return fmt.Errorf("greetings: theme %q (group): %w", t.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ThemeRegistry holds named themes. It is safe for concurrent use; the
+// zero value is an empty registry.
+type ThemeRegistry struct {
+	mu     sync.RWMutex
+	themes map[string]Theme
+}
+
+// Register stores t under its name. It fails if a template is invalid or
+// the name is already taken.
+func (r *ThemeRegistry) Register(t Theme) error {
+
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.themes[t.Name]; dup {
+		return fmt.Errorf("greetings: theme %q already registered", t.Name)
+	}
+	if r.themes == nil {
+		r.themes = make(map[string]Theme)
+	}
+	r.themes[t.Name] = t
+
+	return nil
+}
+
+// Lookup returns the theme registered under name, or an error wrapping
+// ErrUnknownTheme.
+func (r *ThemeRegistry) Lookup(name string) (Theme, error) {
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("%w: %q", ErrUnknownTheme, name)
+	}
+
+	return t, nil
+}
+
+// Themes returns the registered theme names in sorted order.
+func (r *ThemeRegistry) Themes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Sorted(maps.Keys(r.themes))
+}
+
+// LoadFS registers every theme file in the directory dir of fsys; see
+// LoadThemes.
+func (r *ThemeRegistry) LoadFS(fsys fs.FS, dir string) error {
+
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	var themes []Theme
+	var errs []error
+	for _, e := range entries {
+		ext := strings.ToLower(path.Ext(e.Name()))
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		name := path.Join(dir, e.Name())
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		t, err := ParseTheme(name, data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		themes = append(themes, t)
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	for _, t := range themes {
+		if err := r.Register(t); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// themes is the registry behind RegisterTheme, Themes, LoadThemes and
+// WithTheme. It starts with the themes in data/themes.
+var themes = func() *ThemeRegistry {
+	r := new(ThemeRegistry)
+	if err := r.LoadFS(embedded, "data/themes"); err != nil {
+		panic(err)
+	}
+	return r
+}()
+
+// RegisterTheme adds a theme that any Greeter can select with WithTheme.
+// See ThemeRegistry.Register.
+func RegisterTheme(t Theme) error {
+	return themes.Register(t)
+}
+
+// Themes returns the names of all registered themes in sorted order.
+func Themes() []string {
+	return themes.Themes()
+}
+
+// LoadThemes registers every theme file in the directory dir, so teams can
+// ship their own themes alongside the program. Files ending in .yaml,
+// .yml or .json are theme files; everything else is ignored. Nothing is
+// registered when a file cannot be parsed, and every problem found is
+// reported, joined into one error.
+func LoadThemes(dir string) error {
+	return themes.LoadFS(os.DirFS(dir), ".")
+}
+
+// A theme file is YAML, or JSON, with the keys of a catalog entry (see
+// LoadCatalog) and an optional name, which defaults to the file name
+// without its extension:
+//
+//	name: spooky
+//	template: "Boo, %v. Welcome"
+//	punctuation: "!"
+//	casual: {template: "Eek, %v"}
+//	emoji: "🎃"
+
+// ParseTheme parses theme file contents; name is the file name, used for
+// the theme's default name and in error messages, each a *CatalogError.
+func ParseTheme(name string, data []byte) (Theme, error) {
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return Theme{}, &CatalogError{File: name, Err: err}
+	}
+	if len(doc.Content) == 0 {
+		return Theme{}, &CatalogError{File: name, Err: errors.New("empty theme")}
+	}
+
+	root := doc.Content[0]
+	t := Theme{Name: strings.TrimSuffix(path.Base(name), path.Ext(name))}
+	p := &catalogParser{file: name}
+	if root.Kind == yaml.MappingNode {
+		// name is the one key a catalog entry does not have.
+		entry := *root
+		entry.Content = nil
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if key, value := root.Content[i], root.Content[i+1]; key.Value == "name" {
+				t.Name = p.str(value, "name")
+			} else {
+				entry.Content = append(entry.Content, key, value)
+			}
+		}
+		root = &entry
+	}
+	t.Message = p.message(root, "")
+	if len(p.errs) > 0 {
+		return Theme{}, errors.Join(p.errs...)
+	}
+	if err := t.validate(); err != nil {
+		return Theme{}, &CatalogError{File: name, Err: err}
+	}
+
+	return t, nil
+}
+
+// WithTheme makes the Greeter speak the named registered theme instead of
+// its locale's catalog entry; WithTemplate and WithPunctuation still take
+// precedence. A theme that names an emoji turns emoji on unless WithEmoji
+// or WithEmojiMode say otherwise. New fails with ErrUnknownTheme if no
+// such theme is registered.
+func WithTheme(name string) Option {
+	return themes.WithTheme(name)
+}
+
+// WithTheme is like the package-level WithTheme but takes the theme from
+// r, so different Greeters can draw on different sets of themes.
+func (r *ThemeRegistry) WithTheme(name string) Option {
+	return func(g *Greeter) error {
+		t, err := r.Lookup(name)
+		if err != nil {
+			return err
+		}
+		g.theme = &t
+		return nil
+	}
+}
+
+// Theme reports the registered theme the Greeter speaks, or "" when it
+// uses the locale catalog.
+func (g *Greeter) Theme() string {
+	if g.theme == nil {
+		return ""
+	}
+	return g.theme.Name
+}
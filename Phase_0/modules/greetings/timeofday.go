@@ -0,0 +1,86 @@
+package greetings
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the time. Greeters read the current time through a Clock so
+// tests can substitute a fixed one instead of depending on wall time: it
+// is the Time of every Request, and so decides time-of-day greetings,
+// holidays (package calendar), birthdays and the timestamps of greeting
+// history, and it times greetings for WithLogger and WithMetrics.
+// greetingstest.FakeClock is a Clock tests can set and advance.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// SystemClock is the Clock backed by time.Now.
+var SystemClock Clock = ClockFunc(time.Now)
+
+// partOfDay returns the salutation for the hour of t: morning before noon,
+// afternoon until 6pm, evening otherwise.
+func partOfDay(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return "Good morning"
+	case h >= 12 && h < 18:
+		return "Good afternoon"
+	default:
+		return "Good evening"
+	}
+}
+
+// HelloAt returns a time-of-day greeting for the named person, such as
+// "Good morning, Gladys", based on the hour of t in its own location.
+func HelloAt(name string, t time.Time) (string, error) {
+
+	if err := Validate(name); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%v, %v", partOfDay(t), name), nil
+}
+
+// WithClock sets the Clock the Greeter reads the current time from.
+// It defaults to SystemClock, as does a nil c.
+func WithClock(c Clock) Option {
+	return func(g *Greeter) error {
+		if c == nil {
+			c = SystemClock
+		}
+		g.clock = c
+		return nil
+	}
+}
+
+// HelloNow returns a time-of-day greeting for the named person at the
+// current time of the Greeter's Clock.
+func (g *Greeter) HelloNow(name string) (string, error) {
+	return HelloAt(name, g.clock.Now())
+}
+
+// HelloAtZone returns a time-of-day greeting for the named person based on
+// the current time in the recipient's time zone, so a server running in
+// UTC still says "Good morning" to someone in Tokyo at 9am their time.
+// A nil loc means UTC, as with time.LoadLocation("").
+func HelloAtZone(name string, loc *time.Location) (string, error) {
+	return std.HelloAtZone(name, loc)
+}
+
+// HelloAtZone is like HelloNow but reads the Greeter's Clock in loc, the
+// recipient's time zone. A nil loc means UTC.
+func (g *Greeter) HelloAtZone(name string, loc *time.Location) (string, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return HelloAt(name, g.clock.Now().In(loc))
+}
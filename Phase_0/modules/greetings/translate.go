@@ -0,0 +1,150 @@
+package greetings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+// TranslationProvider machine-translates text from one locale to another,
+// for instance by calling a translation service. Implementations must be
+// safe for concurrent use.
+type TranslationProvider interface {
+	Translate(ctx context.Context, text, from, to string) (string, error)
+}
+
+// TranslationFunc adapts an ordinary function to the TranslationProvider
+// interface.
+type TranslationFunc func(ctx context.Context, text, from, to string) (string, error)
+
+// Translate returns f(ctx, text, from, to).
+func (f TranslationFunc) Translate(ctx context.Context, text, from, to string) (string, error) {
+	return f(ctx, text, from, to)
+}
+
+// NamePlaceholder stands in for the names in text sent to a
+// TranslationProvider, which must leave it as it is.
+const NamePlaceholder = "{name}"
+
+// TranslationCache is a TranslationProvider that remembers what another
+// one translated, so each greeting template is only sent out once per
+// locale. Failed translations are not remembered. A TranslationCache is
+// safe for concurrent use and may be shared by several Greeters.
+type TranslationCache struct {
+	tp TranslationProvider
+	mu sync.Mutex
+	m  map[translationKey]string
+}
+
+type translationKey struct {
+	text, from, to string
+}
+
+// NewTranslationCache returns an empty TranslationCache in front of tp.
+func NewTranslationCache(tp TranslationProvider) *TranslationCache {
+	return &TranslationCache{tp: tp, m: make(map[translationKey]string)}
+}
+
+// Translate implements TranslationProvider.
+func (c *TranslationCache) Translate(ctx context.Context, text, from, to string) (string, error) {
+
+	key := translationKey{text, from, to}
+	c.mu.Lock()
+	translated, ok := c.m[key]
+	c.mu.Unlock()
+	if ok {
+		return translated, nil
+	}
+
+	translated, err := c.tp.Translate(ctx, text, from, to)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.m[key] = translated
+	c.mu.Unlock()
+
+	return translated, nil
+}
+
+// Len returns the number of translations c holds.
+func (c *TranslationCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.m)
+}
+
+// WithTranslation lets the Greeter greet in locales its catalog has no
+// entry for, which New would otherwise reject: greetings are rendered in
+// English and then machine-translated by tp. The names in the message are
+// replaced by NamePlaceholder first, so what is translated is the
+// template, and each template is translated once per locale; tp is
+// wrapped in a NewTranslationCache unless it is a *TranslationCache
+// already. Translated greetings have MachineTranslated set and Locale
+// naming the locale asked for.
+//
+// Translation happens where WithTranslation falls among the Greeter's
+// middleware, so give it before the options that add middleware to
+// translate what they produce as well.
+func WithTranslation(tp TranslationProvider) Option {
+	return func(g *Greeter) error {
+		cache, ok := tp.(*TranslationCache)
+		if !ok {
+			cache = NewTranslationCache(tp)
+		}
+		g.translator = cache
+		// translateTo is only known once init has resolved the locale.
+		return Use(func(next Provider) Provider {
+			if g.translateTo == "" {
+				return next
+			}
+			return translated(next, cache, g.locale, g.translateTo)
+		})(g)
+	}
+}
+
+// translated returns a Provider that translates next's greetings from
+// locale from to locale to.
+func translated(next Provider, tp TranslationProvider, from, to string) Provider {
+	return ProviderFunc(func(ctx context.Context, req Request) (Greeting, error) {
+
+		greeting, err := next.Greet(ctx, req)
+		if err != nil || greeting.Skipped {
+			return greeting, err
+		}
+
+		name := greeting.Name
+		template := greeting.Message
+		if name != "" {
+			template = strings.ReplaceAll(template, name, NamePlaceholder)
+		}
+		message, err := tp.Translate(ctx, template, from, to)
+		if err != nil {
+			return Greeting{}, fmt.Errorf("greetings: translating to %s: %w", to, err)
+		}
+		if strings.Count(message, NamePlaceholder) != strings.Count(template, NamePlaceholder) {
+			return Greeting{}, fmt.Errorf("greetings: translating to %s: translation %q lost the name placeholder %s", to, message, NamePlaceholder)
+		}
+
+		greeting.Message = strings.ReplaceAll(message, NamePlaceholder, name)
+		greeting.Salutation = ""
+		greeting.Locale = to
+		greeting.MachineTranslated = true
+
+		return greeting, nil
+	})
+}
+
+// translatable reports whether a Greeter with a TranslationProvider should
+// translate to the locale it was asked for after Resolve failed with err.
+func (g *Greeter) translatable(err error) bool {
+	if g.translator == nil || !errors.Is(err, ErrUnknownLocale) {
+		return false
+	}
+	_, parseErr := language.Parse(g.locale)
+	return parseErr == nil
+}
@@ -0,0 +1,106 @@
+package greetings
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Transliterator rewrites names into another script, for output channels
+// that cannot render the original. Implementations must be safe for
+// concurrent use.
+type Transliterator interface {
+	Transliterate(name string) string
+}
+
+// TransliteratorFunc adapts an ordinary function to the Transliterator
+// interface.
+type TransliteratorFunc func(name string) string
+
+// Transliterate returns f(name).
+func (f TransliteratorFunc) Transliterate(name string) string {
+	return f(name)
+}
+
+// WithTransliterator makes the Greeter pass every name through t before
+// greeting it, as in WithTransliterator(BasicTransliterator) for channels
+// limited to Latin letters. It is off by default.
+func WithTransliterator(t Transliterator) Option {
+	return func(g *Greeter) error {
+		g.transliterator = t
+		return nil
+	}
+}
+
+// BasicTransliterator romanizes Cyrillic and Greek and strips diacritics
+// from Latin letters, so "Владимир" becomes "Vladimir", "Σοφία" "Sofia"
+// and "José Müller" "Jose Muller". Its tables are simple letter-by-letter
+// ones rather than a national standard, and other scripts pass through
+// unchanged.
+var BasicTransliterator Transliterator = TransliteratorFunc(basicTransliterate)
+
+// stripMarks decomposes text and drops the combining marks.
+var stripMarks = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)))
+
+// romanize maps lower-case letters to their Latin spelling.
+var romanize = map[rune]string{
+	// Cyrillic, Russian and Ukrainian.
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'ґ': "g", 'д': "d", 'е': "e",
+	'ё': "yo", 'є': "ye", 'ж': "zh", 'з': "z", 'и': "i", 'і': "i", 'ї': "yi",
+	'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p",
+	'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e",
+	'ю': "yu", 'я': "ya",
+
+	// Greek.
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i",
+	'θ': "th", 'ι': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x",
+	'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y",
+	'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+
+	// Latin letters that do not decompose.
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'þ': "th",
+	'ð': "d", 'ı': "i",
+}
+
+func basicTransliterate(name string) string {
+
+	var b strings.Builder
+	for _, r := range norm.NFC.String(name) {
+		b.WriteString(romanizeRune(r))
+	}
+
+	return b.String()
+}
+
+// romanizeRune returns the Latin spelling of r. Letters missing from the
+// romanize table, such as "é" or the Greek "ά", are looked up again
+// without their diacritics; letters it has, such as the Cyrillic "й",
+// keep theirs, and other scripts are left alone.
+func romanizeRune(r rune) string {
+
+	lower := unicode.ToLower(r)
+	latin, ok := romanize[lower]
+	if !ok {
+		if !unicode.In(r, unicode.Latin, unicode.Greek) {
+			return string(r)
+		}
+		base, _, err := transform.String(stripMarks, string(r))
+		if err != nil || base == string(r) {
+			return string(r)
+		}
+		var b strings.Builder
+		for _, r := range base {
+			b.WriteString(romanizeRune(r))
+		}
+		return b.String()
+	}
+	if r != lower && latin != "" {
+		return strings.ToUpper(latin[:1]) + latin[1:]
+	}
+
+	return latin
+}
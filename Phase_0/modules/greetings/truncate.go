@@ -0,0 +1,87 @@
+package greetings
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"example.com/stringsx"
+)
+
+// defaultEllipsis marks a greeting shortened by WithMaxLength.
+const defaultEllipsis = "…"
+
+// WithMaxLength caps rendered greetings at n runes, ellipsis included.
+// Longer messages are cut at a rune boundary and end in the ellipsis set
+// by WithEllipsis. When the cut would fall inside the recipient's name the
+// whole name is dropped instead, so "Hi, Bartholomew. Welcome!" at 10
+// runes becomes "Hi…" rather than "Hi, Barth…". An ellipsis of n runes or
+// more replaces the whole message, cut to n runes. Zero or less, the
+// default, leaves greetings at any length.
+func WithMaxLength(n int) Option {
+	return func(g *Greeter) error {
+		g.maxLength = n
+		return nil
+	}
+}
+
+// WithEllipsis sets the text WithMaxLength appends to truncated greetings.
+// It defaults to "…"; the empty string truncates without a marker.
+func WithEllipsis(s string) Option {
+	return func(g *Greeter) error {
+		g.ellipsis = s
+		g.ellipsisSet = true
+		return nil
+	}
+}
+
+// truncate shortens message to at most n runes ending in ellipsis, trying
+// not to cut inside name. Bidi isolates the cut leaves open, as around an
+// isolated name, are closed before the ellipsis. When the ellipsis leaves
+// no room for the message, it is all that is left, itself cut to n runes.
+func truncate(message, name string, n int, ellipsis string) string {
+
+	if n <= 0 || utf8.RuneCountInString(message) <= n {
+		return message
+	}
+	budget := n - utf8.RuneCountInString(ellipsis)
+	if budget <= 0 {
+		return stringsx.Truncate(ellipsis, n)
+	}
+
+	start, end := 0, 0
+	if i := strings.Index(message, name); name != "" && i >= 0 {
+		start = utf8.RuneCountInString(message[:i])
+		end = start + utf8.RuneCountInString(name)
+	}
+
+	// Cut further until there is room to close the open isolates.
+	for cut := budget; ; cut-- {
+		if start > 0 && start < cut && cut < end {
+			cut = start
+		}
+		short := strings.TrimRight(stringsx.Truncate(message, cut), " ,、"+isolateOpeners)
+		open := openIsolates(short)
+		if utf8.RuneCountInString(short)+open <= budget {
+			return short + strings.Repeat(pdi, open) + ellipsis
+		}
+	}
+}
+
+// isolateOpeners are the characters that start a bidi isolate: LRI, RLI
+// and FSI.
+const isolateOpeners = "\u2066\u2067\u2068"
+
+// openIsolates returns how many isolates s starts without ending them with
+// a PDI.
+func openIsolates(s string) int {
+	open := 0
+	for _, r := range s {
+		switch {
+		case strings.ContainsRune(isolateOpeners, r):
+			open++
+		case string(r) == pdi && open > 0:
+			open--
+		}
+	}
+	return open
+}
@@ -0,0 +1,65 @@
+package greetings_test
+
+import (
+	"testing"
+
+	"example.com/greetings"
+)
+
+func TestMaxLength(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		n        int
+		ellipsis []greetings.Option
+		want     string
+	}{
+		{"Ann", 0, nil, "Hi, Ann. Welcome!"},
+		{"Ann", 17, nil, "Hi, Ann. Welcome!"},
+		{"Ann", 12, nil, "Hi, Ann. We…"},
+		{"Bartholomew", 10, nil, "Hi…"},
+		{"Ann", 9, []greetings.Option{greetings.WithEllipsis("")}, "Hi, Ann."},
+		{"Ann", 12, []greetings.Option{greetings.WithEllipsis(" [...]")}, "Hi [...]"},
+		{"Ann", 3, nil, "Hi…"},
+		{"Ann", 4, []greetings.Option{greetings.WithEllipsis("....")}, "...."},
+		{"Ann", 5, []greetings.Option{greetings.WithEllipsis("......")}, "....."},
+		{"Ann", 1, []greetings.Option{greetings.WithEllipsis("……")}, "…"},
+	} {
+		opts := append([]greetings.Option{greetings.WithMaxLength(tt.n)}, tt.ellipsis...)
+		g, err := greetings.New(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := g.Hello(tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("Hello(%s) at %d runes = %q, want %q", tt.name, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMaxLengthClosesIsolates(t *testing.T) {
+	for _, tt := range []struct {
+		n    int
+		want string
+	}{
+		{30, "Hi, \u2068Bartholomew\u2069. Welcome!"},
+		{19, "Hi, \u2068Bartholomew\u2069.…"},
+		{18, "Hi, \u2068Bartholomew\u2069…"},
+		{17, "Hi…"},
+		{10, "Hi…"},
+	} {
+		g, err := greetings.New(greetings.WithBidiIsolation(true), greetings.WithMaxLength(tt.n))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := g.Hello("Bartholomew")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("at %d runes: Hello = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package greetings
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// DefaultMaxNameLength is the longest name, in runes, Validate accepts and
+// a Greeter greets unless configured otherwise.
+const DefaultMaxNameLength = 256
+
+// Validate reports whether name can be greeted: it must be non-empty,
+// valid UTF-8 and at most DefaultMaxNameLength runes long. The error wraps
+// ErrEmptyName, ErrInvalidUTF8 or ErrNameTooLong so callers can tell the
+// causes apart with errors.Is.
+func Validate(name string) error {
+	return ValidateMax(name, DefaultMaxNameLength)
+}
+
+// ValidateMax is like Validate with a custom length limit in runes.
+// A limit of zero or less disables the length check.
+func ValidateMax(name string, max int) error {
+
+	if len(name) <= 0 {
+		return ErrEmptyName
+	}
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidUTF8, name)
+	}
+	if n := utf8.RuneCountInString(name); max > 0 && n > max {
+		return fmt.Errorf("%w: %d runes, limit is %d", ErrNameTooLong, n, max)
+	}
+
+	return nil
+}
+
+// WithMaxNameLength sets the longest name, in runes, the Greeter accepts;
+// longer names fail with ErrNameTooLong. It defaults to
+// DefaultMaxNameLength, and zero or less removes the limit.
+func WithMaxNameLength(n int) Option {
+	return func(g *Greeter) error {
+		g.maxNameLength = n
+		return nil
+	}
+}
@@ -0,0 +1,79 @@
+package greetings
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+)
+
+// WithVars gives every greeting's template the extra variables vars, as
+// {{.Vars.key}}: the company, the product. GreetWith adds to them per
+// greeting. vars is a map with string keys or a struct, whose exported
+// fields become variables named after them. Variables only reach text
+// templates and styles (see WithTextTemplate); catalog messages ignore
+// them.
+func WithVars(vars any) Option {
+	return func(g *Greeter) error {
+		m, err := toVars(vars)
+		if err != nil {
+			return err
+		}
+		if g.vars == nil {
+			g.vars = make(map[string]any, len(m))
+		}
+		maps.Copy(g.vars, m)
+		return nil
+	}
+}
+
+// GreetWith is like GreetCtx with extra template variables for this
+// greeting, which take precedence over the Greeter's WithVars. vars is
+// a map with string keys or a struct, as for WithVars:
+//
+//	g, _ := greetings.New(greetings.WithTextTemplate("Hi {{.Name}}, welcome to {{.Vars.Event}}!"))
+//	greeting, err := g.GreetWith(ctx, greetings.Person{Name: "Alice"}, struct{ Event string }{"GopherCon"})
+func (g *Greeter) GreetWith(ctx context.Context, p Person, vars any) (Greeting, error) {
+
+	m, err := toVars(vars)
+	if err != nil {
+		return Greeting{}, err
+	}
+
+	return g.greetPerson(ctx, p, m)
+}
+
+// toVars converts a map with string keys or a struct, or a pointer to
+// either, to template variables.
+func toVars(vars any) (map[string]any, error) {
+
+	if m, ok := vars.(map[string]any); ok || vars == nil {
+		return m, nil
+	}
+	v := reflect.ValueOf(vars)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	switch {
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		m := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			m[iter.Key().String()] = iter.Value().Interface()
+		}
+		return m, nil
+	case v.Kind() == reflect.Struct:
+		m := make(map[string]any)
+		for i := range v.NumField() {
+			if f := v.Type().Field(i); f.IsExported() {
+				m[f.Name] = v.Field(i).Interface()
+			}
+		}
+		return m, nil
+	}
+
+	return nil, fmt.Errorf("greetings: template variables must be a map or a struct, not %T", vars)
+}
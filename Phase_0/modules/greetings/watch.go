@@ -0,0 +1,247 @@
+package greetings
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultWatchInterval is how often a CatalogWatcher checks its file
+// unless told otherwise.
+const DefaultWatchInterval = time.Second
+
+// CatalogWatcher serves greetings from a catalog file and reloads it when
+// the file changes, so a service picks up new wording without a restart.
+// Each change builds a new Greeter, which is swapped in atomically once
+// the catalog has loaded and validated; greetings in flight finish with
+// the Greeter they started with. A file that fails to load is reported
+// to OnError and the previous catalog stays in use.
+//
+// Set the fields, then call Start:
+//
+//	w := &greetings.CatalogWatcher{Path: "catalog.yaml", OnError: logError}
+//	if err := w.Start(); err != nil {
+//		return err
+//	}
+//	defer w.Close()
+//	msg, err := w.Hello("Alice")
+//
+// The watcher polls the file's size and modification time, so it works
+// on every platform and file system, including editors that save by
+// renaming a new file into place.
+type CatalogWatcher struct {
+	// Path is the catalog file, as for LoadCatalog.
+	Path string
+
+	// Options configure every Greeter the watcher builds, after the
+	// catalog.
+	Options []Option
+
+	// Interval is how often the file is checked; zero means
+	// DefaultWatchInterval.
+	Interval time.Duration
+
+	// OnError, if set, is called with every error a reload runs into. It
+	// is called once per change: a file that stays broken is not
+	// reported again until it changes.
+	OnError func(error)
+
+	// OnReload, if set, is called with every catalog swapped in after
+	// the first.
+	OnReload func(Catalog)
+
+	current atomic.Pointer[Greeter]
+	catalog atomic.Pointer[Catalog]
+
+	mu   sync.Mutex // serializes reloads
+	seen fileStamp
+	stop chan struct{}
+	done chan struct{}
+}
+
+// fileStamp identifies a version of a file.
+type fileStamp struct {
+	size    int64
+	modTime time.Time
+}
+
+// Start loads the catalog and starts watching it. Unlike later reloads, a
+// catalog that fails to load here fails Start.
+func (w *CatalogWatcher) Start() error {
+
+	if w.stop != nil {
+		return errors.New("greetings: catalog watcher already started")
+	}
+	if err := w.Reload(); err != nil {
+		return err
+	}
+
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	interval := w.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	go w.watch(interval)
+
+	return nil
+}
+
+// watch checks the file every interval until Close.
+func (w *CatalogWatcher) watch(interval time.Duration) {
+
+	defer close(w.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+		if err := w.reloadIfChanged(); err != nil && w.OnError != nil {
+			w.OnError(err)
+		}
+	}
+}
+
+// reloadIfChanged reloads the file if it differs from the version seen
+// last, whether that version loaded or not.
+func (w *CatalogWatcher) reloadIfChanged() error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stamp, err := stat(w.Path)
+	if err != nil {
+		if w.seen == (fileStamp{}) {
+			return nil
+		}
+		w.seen = fileStamp{}
+		return err
+	}
+	if stamp == w.seen {
+		return nil
+	}
+
+	return w.reload(stamp)
+}
+
+// Reload loads the catalog now, whether or not the file changed, as for a
+// SIGHUP handler. On error the previous catalog stays in use.
+func (w *CatalogWatcher) Reload() error {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	stamp, err := stat(w.Path)
+	if err != nil {
+		return err
+	}
+
+	return w.reload(stamp)
+}
+
+// reload loads version stamp of the file and swaps in a Greeter for it.
+// w.mu must be held.
+func (w *CatalogWatcher) reload(stamp fileStamp) error {
+
+	w.seen = stamp
+	c, err := LoadCatalog(w.Path)
+	if err != nil {
+		return err
+	}
+	g, err := New(append([]Option{WithCatalog(c)}, w.Options...)...)
+	if err != nil {
+		return err
+	}
+
+	first := w.current.Load() == nil
+	w.catalog.Store(&c)
+	w.current.Store(g)
+	if !first && w.OnReload != nil {
+		w.OnReload(c)
+	}
+
+	return nil
+}
+
+func stat(path string) (fileStamp, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileStamp{}, err
+	}
+	return fileStamp{info.Size(), info.ModTime()}, nil
+}
+
+// Close stops watching. The watcher keeps greeting with the last catalog
+// it loaded.
+func (w *CatalogWatcher) Close() error {
+	if w.stop == nil {
+		return nil
+	}
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	<-w.done
+	return nil
+}
+
+// Greeter returns the Greeter for the current catalog, or nil before
+// Start.
+func (w *CatalogWatcher) Greeter() *Greeter {
+	return w.current.Load()
+}
+
+// Catalog returns the current catalog, or nil before Start.
+func (w *CatalogWatcher) Catalog() Catalog {
+	if c := w.catalog.Load(); c != nil {
+		return *c
+	}
+	return nil
+}
+
+var _ Interface = (*CatalogWatcher)(nil)
+
+// Hello is like Greeter.Hello with the current catalog.
+func (w *CatalogWatcher) Hello(name string) (string, error) {
+	return w.Greeter().Hello(name)
+}
+
+// HelloCtx is like Greeter.HelloCtx with the current catalog.
+func (w *CatalogWatcher) HelloCtx(ctx context.Context, name string) (string, error) {
+	return w.Greeter().HelloCtx(ctx, name)
+}
+
+// Hellos is like Greeter.Hellos with the current catalog.
+func (w *CatalogWatcher) Hellos(names []string) ([]string, error) {
+	return w.Greeter().Hellos(names)
+}
+
+// HelloGroup is like Greeter.HelloGroup with the current catalog.
+func (w *CatalogWatcher) HelloGroup(names []string) (string, error) {
+	return w.Greeter().HelloGroup(names)
+}
+
+// Greet is like Greeter.Greet with the current catalog.
+func (w *CatalogWatcher) Greet(name string) (Greeting, error) {
+	return w.Greeter().Greet(name)
+}
+
+// GreetPerson is like Greeter.GreetPerson with the current catalog.
+func (w *CatalogWatcher) GreetPerson(p Person) (Greeting, error) {
+	return w.Greeter().GreetPerson(p)
+}
+
+// GreetCtx is like Greeter.GreetCtx with the current catalog.
+func (w *CatalogWatcher) GreetCtx(ctx context.Context, p Person) (Greeting, error) {
+	return w.Greeter().GreetCtx(ctx, p)
+}
+
+// Locale is like Greeter.Locale with the current catalog.
+func (w *CatalogWatcher) Locale() string {
+	return w.Greeter().Locale()
+}
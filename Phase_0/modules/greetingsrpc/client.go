@@ -0,0 +1,103 @@
+package greetingsrpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"example.com/greetingsrpc/greetingspb"
+)
+
+// Client is a thin wrapper over the generated GreetingService client.
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  greetingspb.GreetingServiceClient
+}
+
+// Dial returns a Client for the service at target. Without options it
+// connects over plaintext, which suits local development; pass transport
+// credentials for anything else.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{conn: conn, rpc: greetingspb.NewGreetingServiceClient(conn)}, nil
+}
+
+// Greet asks the service for a greeting for name in locale. An empty
+// locale lets the server pick its default.
+func (c *Client) Greet(ctx context.Context, name, locale string) (string, error) {
+
+	resp, err := c.rpc.Greet(ctx, &greetingspb.GreetRequest{Name: name, Locale: locale})
+	if err != nil {
+		return "", err
+	}
+
+	return resp.GetMessage(), nil
+}
+
+// StreamResult is one answer from a greeting stream.
+type StreamResult struct {
+	Name    string // the name as sent
+	Message string // the greeting, empty when Err is set
+	Locale  string // the locale the greeting was rendered in
+	Err     error  // why the name could not be greeted
+}
+
+// Stream is an open GreetStream call. Send and Recv may be used from
+// different goroutines, but neither from more than one at a time.
+type Stream struct {
+	rpc grpc.BidiStreamingClient[greetingspb.GreetRequest, greetingspb.GreetStreamResponse]
+}
+
+// GreetStream opens a bidirectional stream: names go out with Send and
+// greetings come back, in the same order, from Recv. Canceling ctx aborts
+// the call.
+func (c *Client) GreetStream(ctx context.Context) (*Stream, error) {
+	rpc, err := c.rpc.GreetStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Stream{rpc: rpc}, nil
+}
+
+// Send asks for a greeting for name in locale.
+func (s *Stream) Send(name, locale string) error {
+	return s.rpc.Send(&greetingspb.GreetRequest{Name: name, Locale: locale})
+}
+
+// CloseSend tells the server no more names are coming. Recv keeps
+// returning the remaining greetings, then io.EOF.
+func (s *Stream) CloseSend() error {
+	return s.rpc.CloseSend()
+}
+
+// Recv returns the next greeting. A name the server could not greet comes
+// back with Err set; an error from Recv itself means the stream is over,
+// io.EOF when it ended normally.
+func (s *Stream) Recv() (StreamResult, error) {
+
+	resp, err := s.rpc.Recv()
+	if err != nil {
+		return StreamResult{}, err
+	}
+	result := StreamResult{Name: resp.GetName(), Message: resp.GetMessage(), Locale: resp.GetLocale()}
+	if resp.GetError() != "" {
+		result.Err = errors.New(resp.GetError())
+	}
+
+	return result, nil
+}
+
+// Close tears down the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
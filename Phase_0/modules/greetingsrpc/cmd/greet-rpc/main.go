@@ -0,0 +1,132 @@
+// Greet-rpc runs the greetings gRPC service, or calls it.
+//
+// Usage:
+//
+//	greet-rpc [-addr host:port]                       serve
+//	greet-rpc [-addr host:port] -name name [-locale l] call the server
+//	greet-rpc [-addr host:port] -stream [-locale l]    greet names read from stdin
+//
+// With -stream, each line of standard input is sent over a GreetStream
+// call and greetings are printed as they arrive; names the server rejects
+// are reported on standard error.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"example.com/greetingsrpc"
+	"example.com/greetingsrpc/greetingspb"
+)
+
+func main() {
+	log.SetPrefix("greet-rpc: ")
+	log.SetFlags(0)
+
+	addr := flag.String("addr", "localhost:50051", "server `address`")
+	name := flag.String("name", "", "call the server to greet `name` instead of serving")
+	locale := flag.String("locale", "", "greeting `locale` when calling")
+	stream := flag.Bool("stream", false, "stream names from standard input to the server")
+	flag.Parse()
+
+	if *stream {
+		client, err := greetingsrpc.Dial(*addr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer client.Close()
+		if err := streamNames(client, os.Stdin, *locale); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *name != "" {
+		client, err := greetingsrpc.Dial(*addr)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer client.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		message, err := client.Greet(ctx, *name, *locale)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(message)
+		return
+	}
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	greetingspb.RegisterGreetingServiceServer(srv, greetingsrpc.NewServer())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		srv.GracefulStop()
+	}()
+
+	log.Printf("listening on %s", *addr)
+	if err := srv.Serve(ln); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// streamNames sends every line of r to the server over one GreetStream
+// call and prints the greetings as they come back.
+func streamNames(client *greetingsrpc.Client, r io.Reader, locale string) error {
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	stream, err := client.GreetStream(ctx)
+	if err != nil {
+		return err
+	}
+
+	sent := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			if err := stream.Send(scanner.Text(), locale); err != nil {
+				sent <- err
+				return
+			}
+		}
+		stream.CloseSend()
+		sent <- scanner.Err()
+	}()
+
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if result.Err != nil {
+			log.Printf("%q: %v", result.Name, result.Err)
+			continue
+		}
+		fmt.Println(result.Message)
+	}
+
+	return <-sent
+}
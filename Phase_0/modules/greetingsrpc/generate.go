@@ -0,0 +1,8 @@
+// Package greetingsrpc exposes the greetings package as a gRPC service.
+//
+// The service is defined in proto/greetings.proto; greetingspb holds the
+// generated code. NewServer returns the implementation to register on a
+// grpc.Server and Dial returns a client for it.
+package greetingsrpc
+
+//go:generate protoc -I proto --go_out=greetingspb --go_opt=paths=source_relative --go-grpc_out=greetingspb --go-grpc_opt=paths=source_relative greetings.proto
@@ -0,0 +1,168 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: greetings.proto
+
+package greetingspb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	GreetingService_Greet_FullMethodName       = "/greetings.v1.GreetingService/Greet"
+	GreetingService_GreetStream_FullMethodName = "/greetings.v1.GreetingService/GreetStream"
+)
+
+// GreetingServiceClient is the client API for GreetingService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// GreetingService greets people in any locale the greetings catalog knows.
+type GreetingServiceClient interface {
+	// Greet returns a greeting for one person.
+	Greet(ctx context.Context, in *GreetRequest, opts ...grpc.CallOption) (*GreetResponse, error)
+	// GreetStream greets names as the client sends them, one response per
+	// request in the order received. A name that cannot be greeted gets a
+	// response with error set instead of failing the stream, so bulk imports
+	// can carry on past bad rows.
+	GreetStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GreetRequest, GreetStreamResponse], error)
+}
+
+type greetingServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGreetingServiceClient(cc grpc.ClientConnInterface) GreetingServiceClient {
+	return &greetingServiceClient{cc}
+}
+
+func (c *greetingServiceClient) Greet(ctx context.Context, in *GreetRequest, opts ...grpc.CallOption) (*GreetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GreetResponse)
+	err := c.cc.Invoke(ctx, GreetingService_Greet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *greetingServiceClient) GreetStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[GreetRequest, GreetStreamResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &GreetingService_ServiceDesc.Streams[0], GreetingService_GreetStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GreetRequest, GreetStreamResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreetingService_GreetStreamClient = grpc.BidiStreamingClient[GreetRequest, GreetStreamResponse]
+
+// GreetingServiceServer is the server API for GreetingService service.
+// All implementations must embed UnimplementedGreetingServiceServer
+// for forward compatibility.
+//
+// GreetingService greets people in any locale the greetings catalog knows.
+type GreetingServiceServer interface {
+	// Greet returns a greeting for one person.
+	Greet(context.Context, *GreetRequest) (*GreetResponse, error)
+	// GreetStream greets names as the client sends them, one response per
+	// request in the order received. A name that cannot be greeted gets a
+	// response with error set instead of failing the stream, so bulk imports
+	// can carry on past bad rows.
+	GreetStream(grpc.BidiStreamingServer[GreetRequest, GreetStreamResponse]) error
+	mustEmbedUnimplementedGreetingServiceServer()
+}
+
+// UnimplementedGreetingServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGreetingServiceServer struct{}
+
+func (UnimplementedGreetingServiceServer) Greet(context.Context, *GreetRequest) (*GreetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Greet not implemented")
+}
+func (UnimplementedGreetingServiceServer) GreetStream(grpc.BidiStreamingServer[GreetRequest, GreetStreamResponse]) error {
+	return status.Error(codes.Unimplemented, "method GreetStream not implemented")
+}
+func (UnimplementedGreetingServiceServer) mustEmbedUnimplementedGreetingServiceServer() {}
+func (UnimplementedGreetingServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeGreetingServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GreetingServiceServer will
+// result in compilation errors.
+type UnsafeGreetingServiceServer interface {
+	mustEmbedUnimplementedGreetingServiceServer()
+}
+
+func RegisterGreetingServiceServer(s grpc.ServiceRegistrar, srv GreetingServiceServer) {
+	// If the following call panics, it indicates UnimplementedGreetingServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&GreetingService_ServiceDesc, srv)
+}
+
+func _GreetingService_Greet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GreetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GreetingServiceServer).Greet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GreetingService_Greet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GreetingServiceServer).Greet(ctx, req.(*GreetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GreetingService_GreetStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(GreetingServiceServer).GreetStream(&grpc.GenericServerStream[GreetRequest, GreetStreamResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type GreetingService_GreetStreamServer = grpc.BidiStreamingServer[GreetRequest, GreetStreamResponse]
+
+// GreetingService_ServiceDesc is the grpc.ServiceDesc for GreetingService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var GreetingService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "greetings.v1.GreetingService",
+	HandlerType: (*GreetingServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Greet",
+			Handler:    _GreetingService_Greet_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GreetStream",
+			Handler:       _GreetingService_GreetStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "greetings.proto",
+}
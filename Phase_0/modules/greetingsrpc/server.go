@@ -0,0 +1,113 @@
+package greetingsrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"example.com/greetings"
+	"example.com/greetingsrpc/greetingspb"
+)
+
+// Server implements greetingspb.GreetingServiceServer on top of the
+// greetings package.
+type Server struct {
+	greetingspb.UnimplementedGreetingServiceServer
+
+	greeters map[string]*greetings.Greeter
+}
+
+// NewServer returns a Server for every locale of the built-in catalog.
+func NewServer() *Server {
+
+	s := &Server{greeters: make(map[string]*greetings.Greeter)}
+	for _, locale := range greetings.Locales() {
+		g, err := greetings.New(greetings.WithLocale(locale))
+		if err != nil {
+			panic(err) // the built-in catalog always has these locales
+		}
+		s.greeters[locale] = g
+	}
+
+	return s
+}
+
+// Greet implements greetingspb.GreetingServiceServer.
+func (s *Server) Greet(ctx context.Context, req *greetingspb.GreetRequest) (*greetingspb.GreetResponse, error) {
+	return s.greet(ctx, req)
+}
+
+// GreetStream implements greetingspb.GreetingServiceServer. Requests are
+// greeted in the order they arrive and each answer is sent as soon as it
+// is ready; names that fail get a response carrying the error. The stream
+// ends when the client closes its side or the call is canceled.
+func (s *Server) GreetStream(stream grpc.BidiStreamingServer[greetingspb.GreetRequest, greetingspb.GreetStreamResponse]) error {
+
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp := &greetingspb.GreetStreamResponse{Name: req.GetName()}
+		greeted, err := s.greet(ctx, req)
+		switch {
+		case ctx.Err() != nil:
+			return toStatus(ctx.Err())
+		case err != nil:
+			resp.Error = status.Convert(err).Message()
+		default:
+			resp.Message = greeted.GetMessage()
+			resp.Locale = greeted.GetLocale()
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// greet greets one request, returning errors as gRPC statuses.
+func (s *Server) greet(ctx context.Context, req *greetingspb.GreetRequest) (*greetingspb.GreetResponse, error) {
+
+	locale := req.GetLocale()
+	if locale == "" {
+		locale = "en"
+	}
+	resolved, err := greetings.ResolveLocale(locale)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown locale %q", locale)
+	}
+
+	message, err := s.greeters[resolved].HelloCtx(ctx, req.GetName())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &greetingspb.GreetResponse{Message: message, Locale: resolved}, nil
+}
+
+// toStatus converts an error from the greetings package to a gRPC status
+// by its greetings.Code.
+func toStatus(err error) error {
+	switch greetings.CodeOf(err) {
+	case greetings.InvalidName, greetings.UnknownLocale, greetings.InvalidConfig:
+		return status.Error(codes.InvalidArgument, err.Error())
+	case greetings.ProviderUnavailable:
+		return status.Error(codes.Unavailable, err.Error())
+	case greetings.Canceled:
+		if errors.Is(err, context.DeadlineExceeded) {
+			return status.Error(codes.DeadlineExceeded, err.Error())
+		}
+		return status.Error(codes.Canceled, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,93 @@
+package greetingsrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"example.com/greetingsrpc"
+	"example.com/greetingsrpc/greetingspb"
+)
+
+// dial serves a Server over an in-memory listener and returns a Client
+// connected to it.
+func dial(t *testing.T) *greetingsrpc.Client {
+
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	greetingspb.RegisterGreetingServiceServer(srv, greetingsrpc.NewServer())
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	c, err := greetingsrpc.Dial("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { c.Close() })
+
+	return c
+}
+
+func TestGreet(t *testing.T) {
+
+	c := dial(t)
+	for _, tt := range []struct {
+		name, locale string
+		want         string
+		code         codes.Code
+	}{
+		{"Ada", "", "Hi, Ada. Welcome!", codes.OK},
+		{"Ada", "es", "Hola, Ada. Te damos la bienvenida.", codes.OK},
+		{"Ada", "es-MX", "Hola, Ada. Te damos la bienvenida.", codes.OK},
+		{"Ada", "xx", "", codes.InvalidArgument},
+		{"", "en", "", codes.InvalidArgument},
+	} {
+		got, err := c.Greet(context.Background(), tt.name, tt.locale)
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("Greet(%q, %q) = %v, want code %v", tt.name, tt.locale, err, tt.code)
+		}
+		if got != tt.want {
+			t.Errorf("Greet(%q, %q) = %q, want %q", tt.name, tt.locale, got, tt.want)
+		}
+	}
+}
+
+func TestGreetReportsLocale(t *testing.T) {
+	resp, err := greetingsrpc.NewServer().Greet(context.Background(), &greetingspb.GreetRequest{Name: "Ada", Locale: "pt-BR"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.GetLocale() != "pt" {
+		t.Errorf("Locale = %q, want pt", resp.GetLocale())
+	}
+}
+
+func TestGreetCanceled(t *testing.T) {
+
+	s := greetingsrpc.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.Greet(ctx, &greetingspb.GreetRequest{Name: "Ada"})
+	if code := status.Code(err); code != codes.Canceled {
+		t.Errorf("Greet with a canceled ctx = %v, want code Canceled", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 0)
+	defer cancel()
+	_, err = s.Greet(ctx, &greetingspb.GreetRequest{Name: "Ada"})
+	if code := status.Code(err); code != codes.DeadlineExceeded {
+		t.Errorf("Greet past its deadline = %v, want code DeadlineExceeded", err)
+	}
+}
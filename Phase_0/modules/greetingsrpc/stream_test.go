@@ -0,0 +1,78 @@
+package greetingsrpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"example.com/greetingsrpc"
+)
+
+func TestGreetStream(t *testing.T) {
+
+	c := dial(t)
+	stream, err := c.GreetStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := []struct{ name, locale string }{
+		{"Ada", ""},
+		{"", "en"},
+		{"Cy", "xx"},
+		{"Bo", "es"},
+	}
+	go func() {
+		for _, r := range requests {
+			if err := stream.Send(r.name, r.locale); err != nil {
+				t.Errorf("Send(%q): %v", r.name, err)
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	want := []greetingsrpc.StreamResult{
+		{Name: "Ada", Message: "Hi, Ada. Welcome!", Locale: "en"},
+		{Name: ""},
+		{Name: "Cy"},
+		{Name: "Bo", Message: "Hola, Bo. Te damos la bienvenida.", Locale: "es"},
+	}
+	for i, w := range want {
+		got, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv %d: %v", i, err)
+		}
+		if got.Name != w.Name || got.Message != w.Message || got.Locale != w.Locale {
+			t.Errorf("Recv %d = %+v, want %+v", i, got, w)
+		}
+		if failed := w.Message == ""; (got.Err != nil) != failed {
+			t.Errorf("Recv %d: Err = %v, want an error: %v", i, got.Err, failed)
+		}
+	}
+	if _, err := stream.Recv(); !errors.Is(err, io.EOF) {
+		t.Errorf("Recv after the last greeting = %v, want io.EOF", err)
+	}
+}
+
+func TestGreetStreamCanceled(t *testing.T) {
+
+	c := dial(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.GreetStream(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Send("Ada", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("Recv after cancel = %v, want code Canceled", err)
+	}
+}
@@ -4,4 +4,32 @@ go 1.25.5
 
 replace example.com/greetings => ./../greetings
 
-require example.com/greetings v0.0.0-00010101000000-000000000000
+replace example.com/collections => ./../../../Phase_1/modules/collections
+
+replace example.com/stringsx => ./../../../Phase_1/modules/stringsx
+
+replace example.com/concurrent => ./../../../Phase_2/modules/concurrent
+
+replace example.com/pipeline => ./../../../Phase_2/modules/pipeline
+
+replace example.com/calculator => ./../../../Phase_3/modules/calculator
+
+replace example.com/nameio => ./../../../Phase_4/modules/nameio
+
+replace example.com/farewells => ./../farewells
+
+require (
+	example.com/farewells v0.0.0-00010101000000-000000000000
+	example.com/greetings v0.0.0-00010101000000-000000000000
+)
+
+require (
+	example.com/collections v0.0.0-00010101000000-000000000000 // indirect
+	example.com/concurrent v0.0.0-00010101000000-000000000000 // indirect
+	example.com/pipeline v0.0.0-00010101000000-000000000000 // indirect
+	example.com/stringsx v0.0.0-00010101000000-000000000000 // indirect
+	github.com/BurntSushi/toml v1.5.0 // indirect
+	golang.org/x/sync v0.22.0 // indirect
+	golang.org/x/text v0.40.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -1,12 +1,20 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"time"
 
+	"example.com/farewells"
 	"example.com/greetings"
 )
 
 func main() {
+	//Properties of the predefined logger: prefix and no timestamp.
+	log.SetPrefix("hello: ")
+	log.SetFlags(0)
+
 	//Get a greeting message and print it.
 	message := greetings.Hello("Gladys")
 	fmt.Println(message)
@@ -14,4 +22,70 @@ func main() {
 	message = greetings.Hello("")
 	fmt.Println(message)
 
+	//The error-returning variant lets us handle missing input ourselves.
+	message, err := greetings.HelloE("")
+	switch {
+	case errors.Is(err, greetings.ErrEmptyName):
+		log.Println("skipping greeting:", err)
+	case err != nil:
+		log.Fatal(err)
+	default:
+		fmt.Println(message)
+	}
+
+	//Greet several people at once; bad entries are reported, not fatal.
+	names := []string{"Gladys", "Samantha", "Darrin", "", "Gladys"}
+	messages, err := greetings.Hellos(names)
+	if err != nil {
+		log.Println(err)
+	}
+	for _, name := range names {
+		if message, ok := messages[name]; ok {
+			fmt.Println(message)
+			delete(messages, name)
+		}
+	}
+
+	//A random template on every call.
+	if message, err := greetings.RandomHello("Gladys"); err == nil {
+		fmt.Println(message)
+	}
+
+	//The same template for the same person on every run.
+	if message, err := greetings.StableHello("Gladys"); err == nil {
+		fmt.Println(message)
+	}
+
+	//A configured Greeter instead of the package-level functions.
+	greeter, err := greetings.New(
+		greetings.WithTemplate("Howdy, %v"),
+		greetings.WithPunctuation("!!"),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if message, err := greeter.Hello("Gladys"); err == nil {
+		fmt.Println(message)
+	}
+
+	//Greetings from the locale catalog; unknown locales fall back to English.
+	for _, locale := range []string{"es", "fr", "xx"} {
+		message, err := greetings.HelloLocale("Gladys", locale)
+		if err != nil {
+			log.Println(err)
+		}
+		fmt.Println(message)
+	}
+
+	//Time-of-day greeting at a fixed instant.
+	noon := time.Date(2025, time.March, 1, 12, 30, 0, 0, time.UTC)
+	if message, err := greetings.HelloAt("Gladys", noon); err == nil {
+		fmt.Println(message)
+	}
+
+	//Farewells share the locale catalogs and options of greetings.
+	if message, err := farewells.GoodbyeLocale("Gladys", "fr"); err == nil {
+		fmt.Println(message)
+	}
+
 }
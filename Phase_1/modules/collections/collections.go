@@ -0,0 +1,73 @@
+// Package collections has generic helpers for working with slices, the
+// functional odds and ends the slices package leaves out: Map, Filter,
+// Reduce, Chunk and Unique. Every function returns a new slice (or, for
+// Chunk, views of its input) and leaves its input unchanged.
+package collections
+
+// Map returns the results of calling f on each element of s, in order.
+func Map[S ~[]E, E, R any](s S, f func(E) R) []R {
+	if s == nil {
+		return nil
+	}
+	out := make([]R, len(s))
+	for i, e := range s {
+		out[i] = f(e)
+	}
+	return out
+}
+
+// Filter returns the elements of s for which keep returns true, in order.
+func Filter[S ~[]E, E any](s S, keep func(E) bool) S {
+	var out S
+	for _, e := range s {
+		if keep(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Reduce folds s into a single value, calling f with the value so far,
+// starting from init, and each element in turn.
+func Reduce[S ~[]E, E, A any](s S, init A, f func(A, E) A) A {
+	acc := init
+	for _, e := range s {
+		acc = f(acc, e)
+	}
+	return acc
+}
+
+// Chunk splits s into consecutive sub-slices of n elements, the last of
+// which may be shorter. The chunks share s's backing array but have their
+// capacity clipped, so appending to one never overwrites the next. Chunk
+// panics if n is less than 1.
+func Chunk[S ~[]E, E any](s S, n int) []S {
+
+	if n < 1 {
+		panic("collections: chunk size must be at least 1")
+	}
+
+	chunks := make([]S, 0, (len(s)+n-1)/n)
+	for lo := 0; lo < len(s); lo += n {
+		hi := min(lo+n, len(s))
+		chunks = append(chunks, s[lo:hi:hi])
+	}
+
+	return chunks
+}
+
+// Unique returns the distinct elements of s in the order they first
+// appear.
+func Unique[S ~[]E, E comparable](s S) S {
+
+	var out S
+	seen := make(map[E]bool, len(s))
+	for _, e := range s {
+		if !seen[e] {
+			seen[e] = true
+			out = append(out, e)
+		}
+	}
+
+	return out
+}
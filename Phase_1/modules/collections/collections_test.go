@@ -0,0 +1,121 @@
+package collections_test
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"example.com/collections"
+)
+
+func TestMap(t *testing.T) {
+	for _, tt := range []struct {
+		in   []int
+		want []string
+	}{
+		{nil, nil},
+		{[]int{}, []string{}},
+		{[]int{1, 20, -3}, []string{"1", "20", "-3"}},
+	} {
+		got := collections.Map(tt.in, strconv.Itoa)
+		if !slices.Equal(got, tt.want) || (got == nil) != (tt.want == nil) {
+			t.Errorf("Map(%v) = %#v, want %#v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	even := func(n int) bool { return n%2 == 0 }
+	for _, tt := range []struct {
+		in, want []int
+	}{
+		{nil, nil},
+		{[]int{1, 3}, nil},
+		{[]int{1, 2, 3, 4, 6}, []int{2, 4, 6}},
+	} {
+		in := slices.Clone(tt.in)
+		if got := collections.Filter(in, even); !slices.Equal(got, tt.want) {
+			t.Errorf("Filter(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+		if !slices.Equal(in, tt.in) {
+			t.Errorf("Filter changed its input to %v", in)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	sum := func(acc, n int) int { return acc + n }
+	for _, tt := range []struct {
+		in         []int
+		init, want int
+	}{
+		{nil, 7, 7},
+		{[]int{1, 2, 3}, 0, 6},
+		{[]int{1, 2, 3}, 10, 16},
+	} {
+		if got := collections.Reduce(tt.in, tt.init, sum); got != tt.want {
+			t.Errorf("Reduce(%v, %d) = %d, want %d", tt.in, tt.init, got, tt.want)
+		}
+	}
+
+	// The fold is in order: the accumulator may differ in type.
+	if got := collections.Reduce([]int{1, 2, 3}, "", func(acc string, n int) string { return acc + strconv.Itoa(n) }); got != "123" {
+		t.Errorf("Reduce concatenating = %q, want %q", got, "123")
+	}
+}
+
+func TestChunk(t *testing.T) {
+	for _, tt := range []struct {
+		in   []int
+		n    int
+		want [][]int
+	}{
+		{nil, 2, [][]int{}},
+		{[]int{1, 2, 3, 4}, 2, [][]int{{1, 2}, {3, 4}}},
+		{[]int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{[]int{1, 2}, 5, [][]int{{1, 2}}},
+		{[]int{1, 2, 3}, 1, [][]int{{1}, {2}, {3}}},
+	} {
+		got := collections.Chunk(tt.in, tt.n)
+		if !slices.EqualFunc(got, tt.want, slices.Equal) {
+			t.Errorf("Chunk(%v, %d) = %v, want %v", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestChunkClipsCapacity(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	chunks := collections.Chunk(s, 2)
+	_ = append(chunks[0], 99)
+	if !slices.Equal(s, []int{1, 2, 3, 4}) {
+		t.Errorf("appending to a chunk overwrote the next: %v", s)
+	}
+}
+
+func TestChunkPanics(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Chunk(s, %d) did not panic", n)
+				}
+			}()
+			collections.Chunk([]int{1}, n)
+		}()
+	}
+}
+
+func TestUnique(t *testing.T) {
+	for _, tt := range []struct {
+		in, want []string
+	}{
+		{nil, nil},
+		{[]string{"a"}, []string{"a"}},
+		{[]string{"b", "a", "b", "c", "a"}, []string{"b", "a", "c"}},
+		{[]string{"", ""}, []string{""}},
+	} {
+		if got := collections.Unique(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("Unique(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
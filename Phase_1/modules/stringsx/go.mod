@@ -0,0 +1,3 @@
+module example.com/stringsx
+
+go 1.25.5
@@ -0,0 +1,91 @@
+// Package stringsx has string helpers that work on runes rather than
+// bytes, so text in any script survives them intact: Reverse,
+// IsPalindrome, Truncate and TitleCase.
+package stringsx
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Reverse returns s with its runes in reverse order. Combining marks are
+// kept after the letter they modify, so "café" written with a combining
+// accent reverses to "éfac" rather than moving the accent onto the "c".
+func Reverse(s string) string {
+
+	var clusters []string
+	for len(s) > 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		for size < len(s) {
+			r, n := utf8.DecodeRuneInString(s[size:])
+			if !unicode.Is(unicode.Mn, r) {
+				break
+			}
+			size += n
+		}
+		clusters = append(clusters, s[:size])
+		s = s[size:]
+	}
+	slices.Reverse(clusters)
+
+	return strings.Join(clusters, "")
+}
+
+// IsPalindrome reports whether s reads the same backwards, looking only at
+// its letters and digits and ignoring case: "A man, a plan, a canal:
+// Panama" is a palindrome.
+func IsPalindrome(s string) bool {
+
+	var runes []rune
+	for _, r := range s {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			runes = append(runes, unicode.ToLower(r))
+		}
+	}
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		if runes[i] != runes[j] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Truncate returns the first n runes of s, or s itself when it is no
+// longer. It never splits a multi-byte character, as slicing the string
+// by bytes could. Truncate returns "" for n of zero or less.
+func Truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	for i := range s {
+		if n == 0 {
+			return s[:i]
+		}
+		n--
+	}
+	return s
+}
+
+// TitleCase upper-cases the first letter of every word of s, where words
+// are separated by white space, hyphens or apostrophes, and leaves the
+// other letters alone: "jean-luc o'neill" becomes "Jean-Luc O'Neill" and
+// "mcDonald" stays "McDonald". For language-specific rules, such as the
+// Dutch "IJ", use golang.org/x/text/cases instead.
+func TitleCase(s string) string {
+
+	var b strings.Builder
+	b.Grow(len(s))
+	start := true
+	for _, r := range s {
+		if start && unicode.IsLetter(r) {
+			r = unicode.ToTitle(r)
+		}
+		start = unicode.IsSpace(r) || r == '-' || r == '\'' || r == '’'
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
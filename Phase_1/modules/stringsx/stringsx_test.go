@@ -0,0 +1,92 @@
+package stringsx_test
+
+import (
+	"testing"
+
+	"example.com/stringsx"
+)
+
+func TestReverse(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"a", "a"},
+		{"Hello", "olleH"},
+		{"héllo", "olléh"},
+		{"日本語", "語本日"},
+		{"ab👋", "👋ba"},
+		{"cafe\u0301", "e\u0301fac"},
+		{"cafe\u0301\u0327!", "!e\u0301\u0327fac"},
+		{"\u0301ab", "ba\u0301"},
+	} {
+		if got := stringsx.Reverse(tt.in); got != tt.want {
+			t.Errorf("Reverse(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIsPalindrome(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		want bool
+	}{
+		{"", true},
+		{"a", true},
+		{"racecar", true},
+		{"Racecar", true},
+		{"A man, a plan, a canal: Panama", true},
+		{"No 'x' in Nixon", true},
+		{"12321", true},
+		{"ésé", true},
+		{"éso", false},
+		{"hello", false},
+		{"ab", false},
+	} {
+		if got := stringsx.IsPalindrome(tt.in); got != tt.want {
+			t.Errorf("IsPalindrome(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	for _, tt := range []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{"Hello", 3, "Hel"},
+		{"Hello", 5, "Hello"},
+		{"Hello", 10, "Hello"},
+		{"Hello", 0, ""},
+		{"Hello", -1, ""},
+		{"", 3, ""},
+		{"héllo", 2, "hé"},
+		{"日本語", 1, "日"},
+		{"👋👋", 1, "👋"},
+	} {
+		if got := stringsx.Truncate(tt.in, tt.n); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestTitleCase(t *testing.T) {
+	for _, tt := range []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"ada lovelace", "Ada Lovelace"},
+		{"jean-luc o'neill", "Jean-Luc O'Neill"},
+		{"o’brien", "O’Brien"},
+		{"mcDonald", "McDonald"},
+		{"ÉMILE zola", "ÉMILE Zola"},
+		{"élodie  dupont", "Élodie  Dupont"},
+		{"ǆemal", "ǅemal"},
+		{"42 street", "42 Street"},
+	} {
+		if got := stringsx.TitleCase(tt.in); got != tt.want {
+			t.Errorf("TitleCase(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+)
+
+// FanOut spreads the values of in over n channels, each value going to
+// exactly one of them, so n goroutines can share the work. A channel that
+// is not read holds up only the value waiting on it. The channels are
+// closed once in is closed or ctx is done.
+func FanOut[T any](ctx context.Context, in <-chan T, n int) []<-chan T {
+
+	outs := make([]<-chan T, max(n, 1))
+	for i := range outs {
+		out := make(chan T)
+		outs[i] = out
+		go func() {
+			defer close(out)
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case v, ok := <-in:
+					if !ok {
+						return
+					}
+					select {
+					case out <- v:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	return outs
+}
+
+// Merge gathers the values of every channel in ins onto one channel, in
+// no particular order. It is closed once all of ins are closed or ctx is
+// done.
+func Merge[T any](ctx context.Context, ins ...<-chan T) <-chan T {
+
+	out := make(chan T)
+	var wg sync.WaitGroup
+	for _, in := range ins {
+		wg.Go(func() {
+			for {
+				var v T
+				select {
+				case <-ctx.Done():
+					return
+				case next, ok := <-in:
+					if !ok {
+						return
+					}
+					v = next
+				}
+				select {
+				case out <- v:
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+	}
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
@@ -0,0 +1,95 @@
+package concurrent_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"example.com/concurrent"
+)
+
+// generate sends 0 through n-1 on the returned channel, then closes it.
+func generate(n int) <-chan int {
+	c := make(chan int)
+	go func() {
+		defer close(c)
+		for i := range n {
+			c <- i
+		}
+	}()
+	return c
+}
+
+func TestFanOutMerge(t *testing.T) {
+	want := make([]int, 100)
+	for i := range want {
+		want[i] = i
+	}
+	for _, n := range []int{-1, 0, 1, 3, 8} {
+		outs := concurrent.FanOut(context.Background(), generate(100), n)
+		if want := max(n, 1); len(outs) != want {
+			t.Errorf("FanOut(%d) made %d channels, want %d", n, len(outs), want)
+		}
+		var got []int
+		for v := range concurrent.Merge(context.Background(), outs...) {
+			got = append(got, v)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("FanOut(%d) then Merge gave %v, want each of 0-99 once", n, got)
+		}
+	}
+}
+
+func TestFanOutSharesWork(t *testing.T) {
+	outs := concurrent.FanOut(context.Background(), generate(1000), 4)
+	counts := make([]int, len(outs))
+	var wg sync.WaitGroup
+	for i, out := range outs {
+		wg.Go(func() {
+			for range out {
+				counts[i]++
+			}
+		})
+	}
+	wg.Wait()
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	if total != 1000 {
+		t.Errorf("workers got %v, %d values in all, want 1000", counts, total)
+	}
+}
+
+func TestMergeNothing(t *testing.T) {
+	if _, ok := <-concurrent.Merge[int](context.Background()); ok {
+		t.Error("Merge of no channels sent a value")
+	}
+}
+
+func TestFanOutCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int) // never sends or closes
+	outs := concurrent.FanOut(ctx, in, 3)
+	cancel()
+	for i, out := range outs {
+		if _, ok := <-out; ok {
+			t.Errorf("channel %d sent a value after cancel", i)
+		}
+	}
+}
+
+func TestMergeCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	idle := make(chan int)    // never sends or closes
+	busy := make(chan int, 1) // has a value nobody reads
+	busy <- 1
+	out := concurrent.Merge(ctx, idle, busy)
+	cancel()
+	for range out {
+		// A value may be sent before the cancel is seen; the channel
+		// must still close.
+	}
+}
@@ -0,0 +1,3 @@
+module example.com/concurrent
+
+go 1.25.5
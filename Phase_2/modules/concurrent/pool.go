@@ -0,0 +1,48 @@
+// Package concurrent has small, reusable building blocks for concurrent
+// programs: a worker Pool, FanOut and Merge for spreading a channel over
+// goroutines and gathering it back, and a counting Semaphore.
+package concurrent
+
+import "sync"
+
+// Pool hands jobs to a fixed number of worker goroutines, each calling the
+// same handler. Submit jobs, then Close to wait for them all:
+//
+//	p := concurrent.NewPool(4, func(path string) { process(path) })
+//	for _, path := range paths {
+//		p.Submit(path)
+//	}
+//	p.Close()
+//
+// A Pool is safe for concurrent use until Close.
+type Pool[T any] struct {
+	jobs chan T
+	wg   sync.WaitGroup
+}
+
+// NewPool starts workers goroutines (at least one) calling handle for
+// every submitted job.
+func NewPool[T any](workers int, handle func(T)) *Pool[T] {
+	p := &Pool[T]{jobs: make(chan T)}
+	for range max(workers, 1) {
+		p.wg.Go(func() {
+			for job := range p.jobs {
+				handle(job)
+			}
+		})
+	}
+	return p
+}
+
+// Submit hands job to the next idle worker, blocking until one is free.
+// It panics if called after Close.
+func (p *Pool[T]) Submit(job T) {
+	p.jobs <- job
+}
+
+// Close stops accepting jobs and waits for the workers to finish the ones
+// submitted.
+func (p *Pool[T]) Close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
@@ -0,0 +1,80 @@
+package concurrent_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"example.com/concurrent"
+)
+
+func TestPool(t *testing.T) {
+	var mu sync.Mutex
+	seen := make(map[int]bool)
+	p := concurrent.NewPool(4, func(job int) {
+		mu.Lock()
+		seen[job] = true
+		mu.Unlock()
+	})
+	for i := range 100 {
+		p.Submit(i)
+	}
+	p.Close()
+	if len(seen) != 100 {
+		t.Errorf("handled %d jobs, want 100", len(seen))
+	}
+}
+
+func TestPoolWorkers(t *testing.T) {
+	for _, tt := range []struct {
+		workers, want int
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 3},
+	} {
+		var running, peak atomic.Int32
+		release := make(chan struct{})
+		p := concurrent.NewPool(tt.workers, func(int) {
+			n := running.Add(1)
+			for {
+				old := peak.Load()
+				if n <= old || peak.CompareAndSwap(old, n) {
+					break
+				}
+			}
+			<-release
+			running.Add(-1)
+		})
+		done := make(chan struct{})
+		go func() {
+			for i := range 6 {
+				p.Submit(i)
+			}
+			close(done)
+		}()
+		// Let the workers fill up before releasing them.
+		for peak.Load() < int32(tt.want) {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+		<-done
+		p.Close()
+		if got := peak.Load(); got != int32(tt.want) {
+			t.Errorf("NewPool(%d): %d jobs ran at once, want %d", tt.workers, got, tt.want)
+		}
+	}
+}
+
+func TestPoolSubmitAfterClose(t *testing.T) {
+	p := concurrent.NewPool(1, func(int) {})
+	p.Close()
+	defer func() {
+		if recover() == nil {
+			t.Error("Submit after Close did not panic")
+		}
+	}()
+	p.Submit(1)
+}
@@ -0,0 +1,46 @@
+package concurrent
+
+import "context"
+
+// Semaphore limits how many goroutines hold it at once. The zero value is
+// not usable; call NewSemaphore.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore returns a Semaphore n goroutines can hold at once (at least
+// one).
+func NewSemaphore(n int) *Semaphore {
+	return &Semaphore{slots: make(chan struct{}, max(n, 1))}
+}
+
+// Acquire blocks until s has a free slot and takes it, or returns ctx.Err()
+// once ctx is done.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryAcquire takes a slot if one is free and reports whether it did.
+func (s *Semaphore) TryAcquire() bool {
+	select {
+	case s.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// Release frees a slot taken by Acquire or TryAcquire. It panics if no
+// slot is taken.
+func (s *Semaphore) Release() {
+	select {
+	case <-s.slots:
+	default:
+		panic("concurrent: Release without Acquire")
+	}
+}
@@ -0,0 +1,57 @@
+package concurrent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example.com/concurrent"
+)
+
+func TestSemaphore(t *testing.T) {
+	for _, tt := range []struct {
+		n, slots int
+	}{
+		{-1, 1},
+		{0, 1},
+		{2, 2},
+	} {
+		s := concurrent.NewSemaphore(tt.n)
+		for i := range tt.slots {
+			if !s.TryAcquire() {
+				t.Errorf("NewSemaphore(%d): TryAcquire %d failed", tt.n, i+1)
+			}
+		}
+		if s.TryAcquire() {
+			t.Errorf("NewSemaphore(%d): TryAcquire past %d slots succeeded", tt.n, tt.slots)
+		}
+		s.Release()
+		if err := s.Acquire(context.Background()); err != nil {
+			t.Errorf("NewSemaphore(%d): Acquire after Release: %v", tt.n, err)
+		}
+	}
+}
+
+func TestSemaphoreAcquireCanceled(t *testing.T) {
+	s := concurrent.NewSemaphore(1)
+	if err := s.Acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() { errc <- s.Acquire(ctx) }()
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Acquire on a full semaphore = %v, want context.Canceled", err)
+	}
+}
+
+func TestSemaphoreReleaseWithoutAcquire(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Release without Acquire did not panic")
+		}
+	}()
+	concurrent.NewSemaphore(1).Release()
+}
@@ -0,0 +1,141 @@
+// Package pipeline chains processing stages over channels. A Source puts
+// values on a channel, each Then runs one Stage over them in its own
+// goroutine, and a Sink takes the results off the end:
+//
+//	items := pipeline.From(ctx, "a.txt", "b.txt")
+//	sizes := pipeline.Then(ctx, items, stat)
+//	err := pipeline.Sink(ctx, sizes, func(it pipeline.Item[int64]) error { ... })
+//
+// Every step stops and closes its channel once ctx is done, so cancelling
+// ctx tears down the whole pipeline.
+package pipeline
+
+import "context"
+
+// Stage turns one value into another, or fails.
+type Stage[In, Out any] func(ctx context.Context, v In) (Out, error)
+
+// Item is a value on its way down a pipeline. Index is its position in
+// the source, so results can be matched to inputs; Err is the error of the
+// stage that failed it, if any.
+type Item[T any] struct {
+	Index int
+	Value T
+	Err   error
+}
+
+// Source sends the values of in down a pipeline, numbering them from 0.
+// The returned channel is closed once in is closed or ctx is done.
+func Source[T any](ctx context.Context, in <-chan T) <-chan Item[T] {
+
+	out := make(chan Item[T])
+	go func() {
+		defer close(out)
+		for i := 0; ; i++ {
+			var v T
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case v, ok = <-in:
+				if !ok {
+					return
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- Item[T]{Index: i, Value: v}:
+			}
+		}
+	}()
+
+	return out
+}
+
+// From is Source for a fixed list of values.
+func From[T any](ctx context.Context, values ...T) <-chan Item[T] {
+
+	out := make(chan Item[T])
+	go func() {
+		defer close(out)
+		for i, v := range values {
+			select {
+			case <-ctx.Done():
+				return
+			case out <- Item[T]{Index: i, Value: v}:
+			}
+		}
+	}()
+
+	return out
+}
+
+// Then runs stage over the items of in, in order, and sends its results
+// on. Items that an earlier stage failed skip stage and pass on with
+// their error. The returned channel is closed once in is closed or ctx is
+// done.
+func Then[In, Out any](ctx context.Context, in <-chan Item[In], stage Stage[In, Out]) <-chan Item[Out] {
+
+	out := make(chan Item[Out])
+	go func() {
+		defer close(out)
+		for {
+			var it Item[In]
+			var ok bool
+			select {
+			case <-ctx.Done():
+				return
+			case it, ok = <-in:
+				if !ok {
+					return
+				}
+			}
+			next := Item[Out]{Index: it.Index, Err: it.Err}
+			if next.Err == nil {
+				next.Value, next.Err = stage(ctx, it.Value)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case out <- next:
+			}
+		}
+	}()
+
+	return out
+}
+
+// Sink calls fn for every item of in until in is closed. It stops early
+// with fn's error if fn fails, or with ctx.Err() once ctx is done, even
+// when the earlier steps, stopping for ctx too, have closed in first.
+// Failed items are handed to fn like any other; fn decides whether they
+// end the run.
+func Sink[T any](ctx context.Context, in <-chan Item[T], fn func(Item[T]) error) error {
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case it, ok := <-in:
+			if !ok {
+				return ctx.Err()
+			}
+			if err := fn(it); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Compose joins two stages into one that runs first, then second.
+func Compose[A, B, C any](first Stage[A, B], second Stage[B, C]) Stage[A, C] {
+	return func(ctx context.Context, v A) (C, error) {
+		b, err := first(ctx, v)
+		if err != nil {
+			var zero C
+			return zero, err
+		}
+		return second(ctx, b)
+	}
+}
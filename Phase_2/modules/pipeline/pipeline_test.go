@@ -0,0 +1,138 @@
+package pipeline_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"testing"
+
+	"example.com/pipeline"
+)
+
+var errOdd = errors.New("odd")
+
+// half halves even numbers and fails odd ones.
+func half(_ context.Context, n int) (int, error) {
+	if n%2 != 0 {
+		return 0, errOdd
+	}
+	return n / 2, nil
+}
+
+func itoa(_ context.Context, n int) (string, error) {
+	return strconv.Itoa(n), nil
+}
+
+// collect sinks in and returns its items.
+func collect[T any](t *testing.T, ctx context.Context, in <-chan pipeline.Item[T]) []pipeline.Item[T] {
+	t.Helper()
+	var items []pipeline.Item[T]
+	if err := pipeline.Sink(ctx, in, func(it pipeline.Item[T]) error {
+		items = append(items, it)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return items
+}
+
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	items := collect(t, ctx, pipeline.Then(ctx, pipeline.Then(ctx, pipeline.From(ctx, 4, 3, 10), half), itoa))
+
+	want := []pipeline.Item[string]{
+		{Index: 0, Value: "2"},
+		{Index: 1, Err: errOdd},
+		{Index: 2, Value: "5"},
+	}
+	if !slices.Equal(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+}
+
+func TestSource(t *testing.T) {
+	ctx := context.Background()
+	in := make(chan string, 3)
+	in <- "a"
+	in <- "b"
+	close(in)
+	items := collect(t, ctx, pipeline.Source(ctx, in))
+	want := []pipeline.Item[string]{{Index: 0, Value: "a"}, {Index: 1, Value: "b"}}
+	if !slices.Equal(items, want) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+}
+
+func TestCompose(t *testing.T) {
+	ctx := context.Background()
+	stage := pipeline.Compose(half, itoa)
+	for _, tt := range []struct {
+		in   int
+		want string
+		err  error
+	}{
+		{8, "4", nil},
+		{7, "", errOdd},
+	} {
+		got, err := stage(ctx, tt.in)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("stage(%d) = %q, %v; want %q, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestSinkStopsOnError(t *testing.T) {
+	ctx := context.Background()
+	var seen []int
+	err := pipeline.Sink(ctx, pipeline.Then(ctx, pipeline.From(ctx, 2, 3, 4), half), func(it pipeline.Item[int]) error {
+		if it.Err != nil {
+			return it.Err
+		}
+		seen = append(seen, it.Value)
+		return nil
+	})
+	if !errors.Is(err, errOdd) || !slices.Equal(seen, []int{1}) {
+		t.Errorf("Sink = %v after %v, want errOdd after [1]", err, seen)
+	}
+}
+
+func TestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	in := make(chan int) // never sends or closes
+	blocked := make(chan struct{})
+	stage := func(ctx context.Context, n int) (int, error) {
+		close(blocked)
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+	errc := make(chan error)
+	go func() {
+		items := pipeline.Then(ctx, pipeline.Then(ctx, pipeline.From(ctx, 1, 2, 3), stage), half)
+		errc <- pipeline.Sink(ctx, items, func(pipeline.Item[int]) error { return nil })
+	}()
+	<-blocked
+	source := pipeline.Source(ctx, in)
+	cancel()
+
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("Sink = %v, want context.Canceled", err)
+	}
+	if _, ok := <-source; ok {
+		t.Error("Source sent a value after cancel")
+	}
+}
+
+func TestSinkCanceledAfterUpstreamCloses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for range 100 {
+		// Whichever of ctx.Done and the closed channel Sink sees first,
+		// it reports the cancel.
+		closed := make(chan pipeline.Item[int])
+		close(closed)
+		if err := pipeline.Sink(ctx, closed, func(pipeline.Item[int]) error { return nil }); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Sink = %v, want context.Canceled", err)
+		}
+	}
+}
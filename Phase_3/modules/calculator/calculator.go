@@ -0,0 +1,41 @@
+// Package calculator does arithmetic on float64s and evaluates simple
+// infix expressions such as "2 * (3 + 4)". Its failures are values a
+// caller can inspect: dividing by zero is ErrDivideByZero, and malformed
+// expressions are a *SyntaxError saying where the problem is.
+package calculator
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrDivideByZero is returned by Div, and by Eval, for a zero divisor.
+var ErrDivideByZero = errors.New("calculator: division by zero")
+
+// Add returns a + b.
+func Add(a, b float64) float64 {
+	return a + b
+}
+
+// Sub returns a - b.
+func Sub(a, b float64) float64 {
+	return a - b
+}
+
+// Mul returns a * b.
+func Mul(a, b float64) float64 {
+	return a * b
+}
+
+// Pow returns a raised to the power b.
+func Pow(a, b float64) float64 {
+	return math.Pow(a, b)
+}
+
+// Div returns a / b, or ErrDivideByZero when b is zero.
+func Div(a, b float64) (float64, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	return a / b, nil
+}
@@ -0,0 +1,233 @@
+package calculator
+
+import (
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+)
+
+// SyntaxError reports an expression Eval cannot read.
+type SyntaxError struct {
+	Expr string
+	Pos  int // byte offset of the problem in Expr
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("calculator: %s at offset %d in %q", e.Msg, e.Pos, e.Expr)
+}
+
+// MaxDepth is how deeply Eval lets signs and parentheses nest, so a
+// hostile expression cannot exhaust the stack.
+const MaxDepth = 256
+
+// Eval evaluates an infix expression of numbers, the operators + - * / ^,
+// unary minus and parentheses, with the usual precedence: "1 + 2 * 3" is
+// 7, "-2 ^ 2" is -4, and ^ is right-associative, so "2 ^ 3 ^ 2" is 512.
+// Spaces are ignored. It fails with a *SyntaxError for a malformed
+// expression, or one nested deeper than MaxDepth, and with
+// ErrDivideByZero for a zero divisor.
+func Eval(expr string) (float64, error) {
+
+	p := &parser{expr: expr}
+	p.skipSpace()
+	v, err := p.sum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.expr) {
+		return 0, p.errorf("unexpected %q", p.peekRune())
+	}
+
+	return v, nil
+}
+
+// parser is a recursive descent parser over expr, evaluating as it goes:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | "+" unary | power
+//	power   = primary [ "^" unary ]
+//	primary = number | "(" sum ")"
+type parser struct {
+	expr  string
+	pos   int
+	depth int // unary and primary calls under way
+}
+
+func (p *parser) sum() (float64, error) {
+
+	v, err := p.product()
+	if err != nil {
+		return 0, err
+	}
+	for p.pos < len(p.expr) {
+		op := p.expr[p.pos]
+		if op != '+' && op != '-' {
+			break
+		}
+		p.advance()
+		w, err := p.product()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v = Add(v, w)
+		} else {
+			v = Sub(v, w)
+		}
+	}
+
+	return v, nil
+}
+
+func (p *parser) product() (float64, error) {
+
+	v, err := p.unary()
+	if err != nil {
+		return 0, err
+	}
+	for p.pos < len(p.expr) {
+		op := p.expr[p.pos]
+		if op != '*' && op != '/' {
+			break
+		}
+		p.advance()
+		w, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		if op == '*' {
+			v = Mul(v, w)
+		} else if v, err = Div(v, w); err != nil {
+			return 0, err
+		}
+	}
+
+	return v, nil
+}
+
+func (p *parser) unary() (float64, error) {
+
+	if err := p.enter(); err != nil {
+		return 0, err
+	}
+	defer p.leave()
+
+	if p.pos < len(p.expr) && (p.expr[p.pos] == '-' || p.expr[p.pos] == '+') {
+		neg := p.expr[p.pos] == '-'
+		p.advance()
+		v, err := p.unary()
+		if neg {
+			v = -v
+		}
+		return v, err
+	}
+
+	return p.power()
+}
+
+func (p *parser) power() (float64, error) {
+
+	v, err := p.primary()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.expr) && p.expr[p.pos] == '^' {
+		p.advance()
+		// The exponent is a unary, which takes in any further ^, so
+		// that "2^3^2" is 2^(3^2).
+		w, err := p.unary()
+		if err != nil {
+			return 0, err
+		}
+		v = Pow(v, w)
+	}
+
+	return v, nil
+}
+
+func (p *parser) primary() (float64, error) {
+
+	if p.pos == len(p.expr) {
+		return 0, p.errorf("unexpected end of expression")
+	}
+	if p.expr[p.pos] == '(' {
+		if err := p.enter(); err != nil {
+			return 0, err
+		}
+		defer p.leave()
+		open := p.pos
+		p.advance()
+		v, err := p.sum()
+		if err != nil {
+			return 0, err
+		}
+		if p.pos == len(p.expr) || p.expr[p.pos] != ')' {
+			return 0, &SyntaxError{Expr: p.expr, Pos: open, Msg: "unclosed parenthesis"}
+		}
+		p.advance()
+		return v, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.expr) && isNumberByte(p.expr[p.pos]) {
+		p.pos++
+		// A sign right after the exponent marker belongs to the number.
+		if e := p.expr[p.pos-1]; (e == 'e' || e == 'E') && p.pos < len(p.expr) &&
+			(p.expr[p.pos] == '+' || p.expr[p.pos] == '-') {
+			p.pos++
+		}
+	}
+	if start == p.pos {
+		return 0, p.errorf("unexpected %q", p.peekRune())
+	}
+	v, err := strconv.ParseFloat(p.expr[start:p.pos], 64)
+	if err != nil {
+		return 0, &SyntaxError{Expr: p.expr, Pos: start, Msg: fmt.Sprintf("bad number %q", p.expr[start:p.pos])}
+	}
+	p.skipSpace()
+
+	return v, nil
+}
+
+// enter counts one more level of nesting, failing past MaxDepth; leave
+// undoes it.
+func (p *parser) enter() error {
+	if p.depth == MaxDepth {
+		return p.errorf("expression nested deeper than %d", MaxDepth)
+	}
+	p.depth++
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+// advance moves past the current byte and any spaces after it.
+func (p *parser) advance() {
+	p.pos++
+	p.skipSpace()
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.expr) && (p.expr[p.pos] == ' ' || p.expr[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *parser) peekRune() rune {
+	r, _ := utf8.DecodeRuneInString(p.expr[p.pos:])
+	return r
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return &SyntaxError{Expr: p.expr, Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// isNumberByte reports whether c can be part of a number literal. The
+// literal is checked by strconv.ParseFloat once it is complete.
+func isNumberByte(c byte) bool {
+	return '0' <= c && c <= '9' || c == '.' || c == 'e' || c == 'E'
+}
@@ -0,0 +1,97 @@
+package calculator_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"example.com/calculator"
+)
+
+func TestEval(t *testing.T) {
+	for _, tt := range []struct {
+		expr string
+		want float64
+	}{
+		{"42", 42},
+		{"1.5e3", 1500},
+		{"2e-1 + 1", 1.2},
+		{"1 + 2 * 3", 7},
+		{"(1 + 2) * 3", 9},
+		{"10 - 4 - 3", 3},
+		{"24 / 4 / 2", 3},
+		{"2 ^ 3 ^ 2", 512},
+		{"(2 ^ 3) ^ 2", 64},
+		{"2 * 3 ^ 2", 18},
+		{"-2 ^ 2", -4},
+		{"2 ^ -1", 0.5},
+		{"-3", -3},
+		{"--3", 3},
+		{"+-3", -3},
+		{"4 * -2", -8},
+		{"-(1 + 2)", -3},
+		{"\t 7 /  2 ", 3.5},
+	} {
+		got, err := calculator.Eval(tt.expr)
+		if err != nil || got != tt.want {
+			t.Errorf("Eval(%q) = %v, %v; want %v", tt.expr, got, err, tt.want)
+		}
+	}
+}
+
+func TestEvalDivideByZero(t *testing.T) {
+	for _, expr := range []string{"1 / 0", "1 / (2 - 2)", "3 + 1 / -0"} {
+		if _, err := calculator.Eval(expr); !errors.Is(err, calculator.ErrDivideByZero) {
+			t.Errorf("Eval(%q) error = %v, want ErrDivideByZero", expr, err)
+		}
+	}
+}
+
+func TestEvalSyntaxError(t *testing.T) {
+	for _, tt := range []struct {
+		expr string
+		pos  int
+		msg  string
+	}{
+		{"", 0, "unexpected end of expression"},
+		{"   ", 3, "unexpected end of expression"},
+		{"1 +", 3, "unexpected end of expression"},
+		{"(1 + 2", 0, "unclosed parenthesis"},
+		{"2 * (1 + (3)", 4, "unclosed parenthesis"},
+		{"1 + 2)", 5, `unexpected ')'`},
+		{"1 2", 2, `unexpected '2'`},
+		{"3 x", 2, `unexpected 'x'`},
+		{"1 + é", 4, `unexpected 'é'`},
+		{"1..2", 0, `bad number "1..2"`},
+		{"2 ^", 3, "unexpected end of expression"},
+	} {
+		_, err := calculator.Eval(tt.expr)
+		var se *calculator.SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Eval(%q) error = %v, want a *SyntaxError", tt.expr, err)
+			continue
+		}
+		if se.Pos != tt.pos || se.Msg != tt.msg || se.Expr != tt.expr {
+			t.Errorf("Eval(%q) error = %+v, want %q at %d", tt.expr, *se, tt.msg, tt.pos)
+		}
+	}
+}
+
+func TestEvalDepth(t *testing.T) {
+	for _, tt := range []struct {
+		expr string
+		ok   bool
+	}{
+		{strings.Repeat("(", 100) + "1" + strings.Repeat(")", 100), true},
+		{strings.Repeat("-", 200) + "1", true},
+		{strings.Repeat("(", 100_000) + "1" + strings.Repeat(")", 100_000), false},
+		{strings.Repeat("-", 100_000) + "1", false},
+		{strings.Repeat("2^", 100_000) + "1", false},
+	} {
+		_, err := calculator.Eval(tt.expr)
+		var se *calculator.SyntaxError
+		if tt.ok && err != nil || !tt.ok && (!errors.As(err, &se) || !strings.HasPrefix(se.Msg, "expression nested deeper")) {
+			t.Errorf("Eval(%.10q...) error = %v", tt.expr, err)
+		}
+	}
+}
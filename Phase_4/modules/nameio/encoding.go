@@ -0,0 +1,60 @@
+package nameio
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// sniffLen is how much of the input decode looks at to tell UTF-8 from
+// Windows-1252, as NewReader documents.
+const sniffLen = 4096
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// decode returns r converted to UTF-8, and the name of the encoding it
+// was in; see NewReader.
+func decode(r io.Reader) (io.Reader, string) {
+
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, _ := br.Peek(sniffLen)
+
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		br.Discard(len(bomUTF8))
+		return br, "utf-8"
+	case bytes.HasPrefix(head, bomUTF16LE):
+		return transform.NewReader(br, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()), "utf-16le"
+	case bytes.HasPrefix(head, bomUTF16BE):
+		return transform.NewReader(br, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()), "utf-16be"
+	case !validUTF8(head):
+		return transform.NewReader(br, charmap.Windows1252.NewDecoder()), "windows-1252"
+	}
+
+	return br, "utf-8"
+}
+
+// validUTF8 reports whether head is valid UTF-8, allowing it to end part
+// way through a character since it may be cut from a longer input.
+func validUTF8(head []byte) bool {
+
+	for i := len(head) - 1; i >= max(len(head)-utf8.UTFMax, 0); i-- {
+		if utf8.RuneStart(head[i]) {
+			if !utf8.FullRune(head[i:]) {
+				head = head[:i]
+			}
+			break
+		}
+	}
+
+	return utf8.Valid(head)
+}
@@ -0,0 +1,84 @@
+package nameio_test
+
+import (
+	"bytes"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"example.com/nameio"
+)
+
+// utf16Bytes encodes s as UTF-16 in the given byte order, after bom.
+func utf16Bytes(s string, bigEndian bool, bom ...byte) []byte {
+	b := slices.Clone(bom)
+	for _, u := range utf16.Encode([]rune(s)) {
+		if bigEndian {
+			b = append(b, byte(u>>8), byte(u))
+		} else {
+			b = append(b, byte(u), byte(u>>8))
+		}
+	}
+	return b
+}
+
+// readAll returns every name r reads.
+func readAll(t *testing.T, r *nameio.Reader) []string {
+	t.Helper()
+	var names []string
+	for {
+		name, err := r.Next()
+		if err == io.EOF {
+			return names
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, name)
+	}
+}
+
+func TestEncodingDetection(t *testing.T) {
+	want := []string{"Zoë", "José", "Łukasz"}
+	text := strings.Join(want, "\n") + "\n"
+	for _, tt := range []struct {
+		name     string
+		input    []byte
+		encoding string
+		want     []string
+	}{
+		{"utf-8", []byte(text), "utf-8", want},
+		{"utf-8 bom", append([]byte{0xEF, 0xBB, 0xBF}, text...), "utf-8", want},
+		{"utf-16le bom", utf16Bytes(text, false, 0xFF, 0xFE), "utf-16le", want},
+		{"utf-16be bom", utf16Bytes(text, true, 0xFE, 0xFF), "utf-16be", want},
+		{"latin-1", []byte("Zo\xeb\nJos\xe9\n"), "windows-1252", []string{"Zoë", "José"}},
+		{"windows-1252", []byte("\x93Ada\x94\n"), "windows-1252", []string{"“Ada”"}},
+		{"empty", nil, "utf-8", nil},
+	} {
+		r := nameio.NewReader(bytes.NewReader(tt.input), nameio.Text)
+		if got := readAll(t, r); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: names = %q, want %q", tt.name, got, tt.want)
+		}
+		if r.Encoding() != tt.encoding {
+			t.Errorf("%s: Encoding = %q, want %q", tt.name, r.Encoding(), tt.encoding)
+		}
+	}
+}
+
+func TestEncodingSniffLimit(t *testing.T) {
+	// A character cut by the end of the first 4 KB is still UTF-8...
+	ascii := strings.Repeat("a", 4095)
+	r := nameio.NewReader(strings.NewReader(ascii+"é\n"), nameio.Text)
+	if got := readAll(t, r); r.Encoding() != "utf-8" || len(got) != 1 || got[0] != ascii+"é" {
+		t.Errorf("cut character: %s, %.10q", r.Encoding(), got)
+	}
+
+	// ...and Latin-1 past the first 4 KB is not detected.
+	r = nameio.NewReader(strings.NewReader(strings.Repeat("Ada\n", 1024)+"Zo\xeb\n"), nameio.Text)
+	got := readAll(t, r)
+	if r.Encoding() != "utf-8" || len(got) != 1025 || got[1024] != "Zo\xeb" {
+		t.Errorf("late Latin-1: %s, last name %q", r.Encoding(), got[len(got)-1])
+	}
+}
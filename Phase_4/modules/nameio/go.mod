@@ -0,0 +1,5 @@
+module example.com/nameio
+
+go 1.25.5
+
+require golang.org/x/text v0.40.0
@@ -0,0 +1,299 @@
+// Package nameio reads lists of names from files, one record at a time,
+// so inputs of any size can be processed without loading them into
+// memory. It understands plain text (one name per line), CSV and JSON
+// Lines, and decodes UTF-8, UTF-16 and Windows-1252 input; see NewReader.
+//
+// A Reader's Stream feeds straight into greetings.Greeter.HelloStream:
+//
+//	r, err := nameio.Open("names.csv")
+//	...
+//	defer r.Close()
+//	for res := range g.HelloStream(ctx, r.Stream(ctx)) {
+//		...
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+package nameio
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Format is the layout of a names file.
+type Format int
+
+const (
+	// Text has one name per line. Blank lines are skipped.
+	Text Format = iota
+
+	// CSV has one record per line. Names come from the column headed
+	// "name" when the first record has one, and from the first column
+	// otherwise. Blank names are skipped.
+	CSV
+
+	// JSONL has one JSON value per line: a string, or an object whose
+	// "name" field is the name. Blank lines are skipped.
+	JSONL
+)
+
+var formatNames = [...]string{
+	Text:  "text",
+	CSV:   "csv",
+	JSONL: "jsonl",
+}
+
+// String returns the name of f: "text", "csv" or "jsonl".
+func (f Format) String() string {
+	if f < 0 || int(f) >= len(formatNames) {
+		return fmt.Sprintf("Format(%d)", int(f))
+	}
+	return formatNames[f]
+}
+
+// ParseFormat returns the Format named s, ignoring case.
+func ParseFormat(s string) (Format, error) {
+	for f, name := range formatNames {
+		if strings.EqualFold(s, name) {
+			return Format(f), nil
+		}
+	}
+	return 0, fmt.Errorf("nameio: unknown format %q (want %s)", s, strings.Join(formatNames[:], ", "))
+}
+
+// FormatFor guesses the format of the file at path from its extension:
+// ".csv" is CSV, ".jsonl" and ".ndjson" are JSONL, and anything else is
+// Text.
+func FormatFor(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return CSV
+	case ".jsonl", ".ndjson":
+		return JSONL
+	default:
+		return Text
+	}
+}
+
+// ParseError reports a record that holds no name nameio can read.
+type ParseError struct {
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("nameio: line %d: %v", e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Reader reads names from a names file. It is not safe for concurrent use.
+type Reader struct {
+	next     func() (string, error)
+	encoding string
+	closer   io.Closer
+	line     int
+	err      error
+}
+
+// Open opens the file at path for reading names, in the format FormatFor
+// guesses from its name. Close the Reader when done.
+func Open(path string) (*Reader, error) {
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r := NewReader(f, FormatFor(path))
+	r.closer = f
+
+	return r, nil
+}
+
+// NewReader returns a Reader for names in format f on r. The encoding is
+// detected from the start of r: a byte order mark selects UTF-8, UTF-16LE
+// or UTF-16BE and is dropped, input that is valid UTF-8 is read as such,
+// and anything else is taken to be Windows-1252, which covers Latin-1.
+// Only the first 4 KB are looked at: input that is valid UTF-8 that far
+// is read as UTF-8 throughout, and any bytes after it that are not are
+// passed through unchanged.
+func NewReader(r io.Reader, f Format) *Reader {
+
+	text, encoding := decode(r)
+	nr := &Reader{encoding: encoding}
+	switch f {
+	case CSV:
+		nr.next = nr.csv(text)
+	case JSONL:
+		nr.next = nr.jsonl(text)
+	default:
+		nr.next = nr.text(text)
+	}
+
+	return nr
+}
+
+// Encoding reports the encoding the Reader detected: "utf-8",
+// "utf-16le", "utf-16be" or "windows-1252".
+func (r *Reader) Encoding() string {
+	return r.encoding
+}
+
+// Line reports the line of the input the last name came from.
+func (r *Reader) Line() int {
+	return r.line
+}
+
+// Next returns the next name, or io.EOF once there are no more.
+func (r *Reader) Next() (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	name, err := r.next()
+	if err != nil {
+		r.err = err
+	}
+	return name, err
+}
+
+// Stream sends the names of r on the returned channel, which is closed at
+// the end of the input, at the first error or once ctx is done. Err
+// reports why it stopped.
+func (r *Reader) Stream(ctx context.Context) <-chan string {
+
+	names := make(chan string)
+	go func() {
+		defer close(names)
+		for {
+			name, err := r.Next()
+			if err != nil {
+				return
+			}
+			select {
+			case <-ctx.Done():
+				r.err = ctx.Err()
+				return
+			case names <- name:
+			}
+		}
+	}()
+
+	return names
+}
+
+// Err returns the error that stopped the Reader, or nil if it stopped at
+// the end of the input or has not stopped yet.
+func (r *Reader) Err() error {
+	if errors.Is(r.err, io.EOF) {
+		return nil
+	}
+	return r.err
+}
+
+// Close closes the file of a Reader made by Open. It does nothing for one
+// made by NewReader.
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	return r.closer.Close()
+}
+
+// text returns the next function for Text input.
+func (r *Reader) text(in io.Reader) func() (string, error) {
+	scanner := bufio.NewScanner(in)
+	return func() (string, error) {
+		for scanner.Scan() {
+			r.line++
+			if name := strings.TrimSpace(scanner.Text()); name != "" {
+				return name, nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+}
+
+// csv returns the next function for CSV input.
+func (r *Reader) csv(in io.Reader) func() (string, error) {
+
+	cr := csv.NewReader(in)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	column := -1
+
+	return func() (string, error) {
+		for {
+			record, err := cr.Read()
+			if err != nil {
+				return "", err
+			}
+			r.line, _ = cr.FieldPos(0)
+			if column < 0 {
+				column = slices.IndexFunc(record, func(field string) bool {
+					return strings.EqualFold(strings.TrimSpace(field), "name")
+				})
+				if column >= 0 {
+					continue
+				}
+				column = 0
+			}
+			if column >= len(record) {
+				continue
+			}
+			if name := strings.TrimSpace(record[column]); name != "" {
+				return name, nil
+			}
+		}
+	}
+}
+
+// jsonl returns the next function for JSONL input.
+func (r *Reader) jsonl(in io.Reader) func() (string, error) {
+	scanner := bufio.NewScanner(in)
+	return func() (string, error) {
+		for scanner.Scan() {
+			r.line++
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var name string
+			if strings.HasPrefix(line, "{") {
+				var record struct {
+					Name *string `json:"name"`
+				}
+				if err := json.Unmarshal([]byte(line), &record); err != nil {
+					return "", &ParseError{Line: r.line, Err: err}
+				}
+				if record.Name == nil {
+					return "", &ParseError{Line: r.line, Err: errors.New(`object has no "name" field`)}
+				}
+				name = *record.Name
+			} else if err := json.Unmarshal([]byte(line), &name); err != nil {
+				return "", &ParseError{Line: r.line, Err: err}
+			}
+			if name = strings.TrimSpace(name); name != "" {
+				return name, nil
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+}
@@ -0,0 +1,125 @@
+package nameio_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"example.com/nameio"
+)
+
+func TestFormats(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		format nameio.Format
+		input  string
+		want   []string
+		lines  []int
+	}{
+		{"text", nameio.Text, "Ada\n\n  Bob  \r\nCy", []string{"Ada", "Bob", "Cy"}, []int{1, 3, 4}},
+		{"csv name column", nameio.CSV, "id,Name,team\n1,Ada,x\n2, Bob ,y\n3,,z\n4\n", []string{"Ada", "Bob"}, []int{2, 3}},
+		{"csv first column", nameio.CSV, "Ada,1\nBob,2\n", []string{"Ada", "Bob"}, []int{1, 2}},
+		{"csv quoted", nameio.CSV, "name\n\"Smith, Ada\"\n", []string{"Smith, Ada"}, []int{2}},
+		{"jsonl", nameio.JSONL, "\"Ada\"\n\n{\"name\":\"Bob\",\"id\":2}\n{\"name\":\" \"}\n\"Cy\"\n", []string{"Ada", "Bob", "Cy"}, []int{1, 3, 5}},
+	} {
+		r := nameio.NewReader(strings.NewReader(tt.input), tt.format)
+		var names []string
+		var lines []int
+		for {
+			name, err := r.Next()
+			if err != nil {
+				break
+			}
+			names, lines = append(names, name), append(lines, r.Line())
+		}
+		if err := r.Err(); err != nil {
+			t.Errorf("%s: Err = %v", tt.name, err)
+		}
+		if !slices.Equal(names, tt.want) || !slices.Equal(lines, tt.lines) {
+			t.Errorf("%s: names %q on lines %v, want %q on %v", tt.name, names, lines, tt.want, tt.lines)
+		}
+	}
+}
+
+func TestJSONLParseError(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		line  int
+		msg   string
+	}{
+		{"\"Ada\"\n{\"id\":2}\n", 2, `object has no "name" field`},
+		{"\"Ada\"\n\n{\"name\":\n", 3, "unexpected end of JSON input"},
+		{"42\n", 1, "cannot unmarshal number"},
+		{"\"Ada\"\n\"Bob\"\nAda\n", 3, "invalid character"},
+	} {
+		r := nameio.NewReader(strings.NewReader(tt.input), nameio.JSONL)
+		for range r.Stream(context.Background()) {
+		}
+		var pe *nameio.ParseError
+		if !errors.As(r.Err(), &pe) || pe.Line != tt.line || !strings.Contains(pe.Err.Error(), tt.msg) {
+			t.Errorf("%q: Err = %v, want line %d: %s", tt.input, r.Err(), tt.line, tt.msg)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.csv")
+	if err := os.WriteFile(path, []byte("name\nAda\nBob\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	r, err := nameio.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	var names []string
+	for name := range r.Stream(context.Background()) {
+		names = append(names, name)
+	}
+	if err := r.Err(); err != nil || !slices.Equal(names, []string{"Ada", "Bob"}) {
+		t.Errorf("names = %q, %v", names, err)
+	}
+}
+
+func TestStreamCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	// With names left to send, the stream soon picks ctx.Done over the
+	// next send.
+	r := nameio.NewReader(strings.NewReader(strings.Repeat("Ada\n", 10_000)), nameio.Text)
+	names := r.Stream(ctx)
+	<-names
+	cancel()
+	n := 1
+	for range names {
+		n++
+	}
+	if err := r.Err(); !errors.Is(err, context.Canceled) || n == 10_000 {
+		t.Errorf("after %d names: Err = %v, want context.Canceled", n, err)
+	}
+}
+
+func TestFormatFor(t *testing.T) {
+	for path, want := range map[string]nameio.Format{
+		"names.csv":    nameio.CSV,
+		"NAMES.CSV":    nameio.CSV,
+		"a.jsonl":      nameio.JSONL,
+		"a.ndjson":     nameio.JSONL,
+		"names.txt":    nameio.Text,
+		"names":        nameio.Text,
+		"dir.csv/list": nameio.Text,
+	} {
+		if got := nameio.FormatFor(path); got != want {
+			t.Errorf("FormatFor(%q) = %v, want %v", path, got, want)
+		}
+	}
+	if f, err := nameio.ParseFormat("JSONL"); f != nameio.JSONL || err != nil {
+		t.Errorf("ParseFormat(JSONL) = %v, %v", f, err)
+	}
+	if _, err := nameio.ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) succeeded")
+	}
+}